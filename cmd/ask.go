@@ -1,16 +1,39 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"strings"
 
 	"github.com/connorhough/smix/internal/ask"
 	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
+// askFlags holds the ask command's flag values
+type askFlags struct {
+	chat       bool
+	file       string
+	output     string
+	force      bool
+	template   string
+	context    string
+	batch      string
+	parallel   int
+	count      int
+	promptOnly bool
+	truncate   bool
+}
+
 // NewAskCmd creates and returns the ask command
 func NewAskCmd() *cobra.Command {
+	var flags askFlags
+
 	askCmd := &cobra.Command{
 		Use:   "ask \"[question]\"",
 		Short: "Ask short technical questions and get concise answers",
@@ -21,36 +44,286 @@ Supports multiple providers (Claude, Gemini) with per-command configuration.
 Great for quick lookups like:
 - "what is FastAPI"
 - "does the mv command overwrite duplicate files"
-- "how do I check if a port is open"`,
-		Args: cobra.ExactArgs(1),
-		RunE: runAsk,
+- "how do I check if a port is open"
+
+The question can also be piped on stdin or read from a file with --file:
+  cat question.txt | smix ask
+  smix ask --file question.txt
+
+When a question is given as an argument, piped stdin is attached as context instead:
+  cat main.go | smix ask "what does this do"
+  smix ask --context-file main.go "what does this do"
+Context is capped at commands.ask.max_context_bytes (default 100KiB). A warning is
+printed when the prompt is estimated to exceed the model's context window; pass
+--truncate to cut the context to fit instead.
+
+Use -n to sample several candidate answers, e.g. for brainstorming:
+  smix ask -n 3 "names for a CLI that wraps LLMs"
+
+Use --batch to answer a file of questions, one per line, concurrently:
+  smix ask --batch questions.txt
+  smix ask --batch questions.txt --output-format json
+
+Use --prompt-only to print the assembled prompt (template, question and context)
+without contacting a provider, e.g. when tuning a custom --prompt-template.
+
+Use --chat to start a multi-turn conversation. Type /exit or send EOF (Ctrl+D) to quit.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if flags.chat || flags.batch != "" {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.MaximumNArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAsk(cmd, args, &flags)
+		},
 	}
 
+	askCmd.Flags().BoolVar(&flags.chat, "chat", false, "Start a multi-turn conversation")
+	askCmd.Flags().StringVar(&flags.file, "file", "", "Read the question from a file")
+	askCmd.Flags().StringVarP(&flags.output, "output", "o", "", "Write the answer to a file instead of stdout")
+	askCmd.Flags().BoolVar(&flags.force, "force", false, "Overwrite the --output file if it exists")
+	askCmd.Flags().StringVar(&flags.context, "context-file", "", "Attach a file's content as context for the question")
+	askCmd.Flags().IntVarP(&flags.count, "count", "n", 1, fmt.Sprintf("Number of candidate answers to sample (1-%d)", ask.MaxAnswerCount))
+	askCmd.Flags().StringVar(&flags.batch, "batch", "", "Answer every question in a file, one per line")
+	askCmd.Flags().IntVar(&flags.parallel, "concurrency", 0, "Questions answered in parallel with --batch (default: commands.ask.batch_concurrency or 4)")
+	askCmd.Flags().BoolVar(&flags.truncate, "truncate", false, "Cut the context so the prompt fits the model's estimated context window")
+	askCmd.Flags().BoolVar(&flags.promptOnly, "prompt-only", false, "Print the prompt that would be sent and exit without calling the provider")
+	askCmd.Flags().StringVar(&flags.template, "prompt-template", "", "Path to a text/template file for the prompt, which must include {{.Question}} (default: commands.ask.prompt_template or built-in)")
+
 	return askCmd
 }
 
-func runAsk(cmd *cobra.Command, args []string) error {
-	question := args[0]
-
+func runAsk(cmd *cobra.Command, args []string, flags *askFlags) error {
 	// Resolve configuration
 	cfg := config.ResolveProviderConfig("ask")
 	cfg.ApplyFlags(providerFlag, modelFlag)
+	applyRetriesFlag(cmd, cfg)
 
 	slog.Debug("resolved config", "provider", cfg.Provider, "model", cfg.Model)
 
 	ctx := cmd.Context()
+	streams := llm.NewIOStreams()
+
+	if flags.count != 1 && (flags.chat || flags.batch != "") {
+		return fmt.Errorf("--count cannot be combined with --chat or --batch")
+	}
+	if flags.promptOnly && (flags.chat || flags.batch != "") {
+		return fmt.Errorf("--prompt-only cannot be combined with --chat or --batch")
+	}
+
+	if flags.chat {
+		if flags.file != "" || flags.context != "" {
+			return fmt.Errorf("--file and --context-file cannot be combined with --chat")
+		}
+		if outputFormat == outputFormatJSON {
+			return fmt.Errorf("--output-format json cannot be combined with --chat")
+		}
+		return ask.Chat(ctx, streams, cfg)
+	}
+
+	if flags.batch != "" {
+		if flags.file != "" {
+			return fmt.Errorf("--file cannot be combined with --batch")
+		}
+		return runAskBatch(cmd, streams, cfg, flags)
+	}
+
+	question, err := resolveQuestion(streams, args, flags.file)
+	if err != nil {
+		return err
+	}
 
 	// Get answer
-	answer, err := ask.Answer(ctx, question, cfg)
+	opts, err := askOptions(cmd, streams, args, flags)
+	if err != nil {
+		return err
+	}
+	if flags.promptOnly {
+		prompt, err := ask.BuildPrompt(question, opts)
+		if err != nil {
+			return err
+		}
+		return writeResult(cmd.OutOrStdout(), flags.output, flags.force, prompt)
+	}
+	if flags.count != 1 {
+		return runAskCount(cmd, streams, question, cfg, opts, flags)
+	}
+
+	var answer string
+	var source ask.Source
+	err = withSpinner(streams, "Thinking...", func() error {
+		var err error
+		answer, source, err = ask.Answer(ctx, question, cfg, opts)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	output, err := renderResult(outputFormat, answer, result{
+		Question: question,
+		Answer:   answer,
+		Provider: source.Provider,
+		Model:    source.Model,
+	})
 	if err != nil {
 		return err
 	}
 
 	// Print the answer
-	_, err = fmt.Fprintln(cmd.OutOrStdout(), answer)
+	return writeResult(cmd.OutOrStdout(), flags.output, flags.force, output)
+}
+
+// runAskCount samples --count answers to question and prints them numbered and separated
+func runAskCount(cmd *cobra.Command, streams *llm.IOStreams, question string, cfg *config.ProviderConfig, opts ask.Options, flags *askFlags) error {
+	var answers []string
+	var source ask.Source
+	err := withSpinner(streams, "Thinking...", func() error {
+		var err error
+		answers, source, err = ask.AnswerN(cmd.Context(), question, cfg, opts, flags.count)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	output, err := renderResult(outputFormat, ask.FormatAnswers(answers), result{
+		Question: question,
+		Answers:  answers,
+		Provider: source.Provider,
+		Model:    source.Model,
+	})
+	if err != nil {
+		return err
+	}
+	return writeResult(cmd.OutOrStdout(), flags.output, flags.force, output)
+}
+
+// runAskBatch answers every question in the --batch file and prints the results in input order.
+// Results are printed even when some questions fail; the command then exits with an error.
+func runAskBatch(cmd *cobra.Command, streams *llm.IOStreams, cfg *config.ProviderConfig, flags *askFlags) error {
+	f, err := os.Open(flags.batch)
+	if err != nil {
+		return fmt.Errorf("failed to open batch file: %w", err)
+	}
+	questions, err := ask.ReadBatch(f)
+	f.Close()
 	if err != nil {
 		return err
 	}
+	if len(questions) == 0 {
+		return fmt.Errorf("batch file %s contains no questions", flags.batch)
+	}
+
+	concurrency := flags.parallel
+	if concurrency == 0 {
+		concurrency = ask.DefaultBatchConcurrency
+		if viper.IsSet("commands.ask.batch_concurrency") {
+			concurrency = viper.GetInt("commands.ask.batch_concurrency")
+		}
+	}
 
-	return nil
+	opts, err := askOptions(cmd, streams, nil, flags)
+	if err != nil {
+		return err
+	}
+
+	var results []ask.BatchResult
+	batchErr := withSpinner(streams, fmt.Sprintf("Answering %d questions...", len(questions)), func() error {
+		var err error
+		results, err = ask.AnswerBatch(cmd.Context(), questions, cfg, opts, concurrency)
+		return err
+	})
+	if results == nil {
+		return batchErr
+	}
+
+	var out strings.Builder
+	if outputFormat == outputFormatJSON {
+		err = ask.WriteBatchJSON(&out, results)
+	} else {
+		err = ask.WriteBatch(&out, results)
+	}
+	if err != nil {
+		return err
+	}
+	if err := writeResult(cmd.OutOrStdout(), flags.output, flags.force, strings.TrimSuffix(out.String(), "\n")); err != nil {
+		return err
+	}
+	return batchErr
+}
+
+// askOptions resolves the prompt template and question context shared by single and batch questions
+func askOptions(cmd *cobra.Command, streams *llm.IOStreams, args []string, flags *askFlags) (ask.Options, error) {
+	maxContext := ask.DefaultMaxContextBytes
+	if viper.IsSet("commands.ask.max_context_bytes") {
+		maxContext = viper.GetInt("commands.ask.max_context_bytes")
+	}
+	questionContext, truncated, err := resolveContext(streams, args, flags.context, maxContext)
+	if err != nil {
+		return ask.Options{}, err
+	}
+	if truncated {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: context truncated to %d bytes (raise commands.ask.max_context_bytes to send more)\n", maxContext)
+	}
+
+	promptTemplate := flags.template
+	if promptTemplate == "" {
+		promptTemplate = viper.GetString("commands.ask.prompt_template")
+	}
+	return ask.Options{PromptTemplate: promptTemplate, Context: questionContext, Truncate: flags.truncate}, nil
+}
+
+// resolveQuestion determines the question from, in order: a positional argument,
+// the file given by --file, or stdin when it is not a terminal.
+func resolveQuestion(streams *llm.IOStreams, args []string, file string) (string, error) {
+	if file != "" && len(args) > 0 {
+		return "", fmt.Errorf("provide the question either as an argument or with --file, not both")
+	}
+
+	var question string
+	switch {
+	case len(args) > 0:
+		question = args[0]
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read question file: %w", err)
+		}
+		question = string(data)
+	case !streams.IsInteractive():
+		data, err := io.ReadAll(streams.In)
+		if err != nil {
+			return "", fmt.Errorf("failed to read question from stdin: %w", err)
+		}
+		question = string(data)
+	default:
+		return "", errors.New("no question provided: pass it as an argument, with --file, or on stdin")
+	}
+
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return "", errors.New("question is empty")
+	}
+
+	return question, nil
+}
+
+// resolveContext returns context for the question from --context-file, or from stdin when
+// stdin is piped and the question was given as an argument (otherwise stdin is the question)
+func resolveContext(streams *llm.IOStreams, args []string, file string, max int) (string, bool, error) {
+	switch {
+	case file != "":
+		f, err := os.Open(file)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to open context file: %w", err)
+		}
+		defer f.Close()
+		return ask.ReadContext(f, max)
+	case len(args) > 0 && !streams.IsInteractive():
+		return ask.ReadContext(streams.In, max)
+	default:
+		return "", false, nil
+	}
 }
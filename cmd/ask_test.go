@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/connorhough/smix/internal/llm"
+)
+
+func TestResolveQuestion(t *testing.T) {
+	tmpDir := t.TempDir()
+	questionFile := filepath.Join(tmpDir, "question.txt")
+	if err := os.WriteFile(questionFile, []byte("what is a goroutine\n\nexplain briefly\n"), 0o644); err != nil {
+		t.Fatalf("failed to write question file: %v", err)
+	}
+
+	t.Run("positional argument", func(t *testing.T) {
+		streams, _, _ := llm.TestIOStreams()
+		got, err := resolveQuestion(streams, []string{"what is TCP"}, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "what is TCP" {
+			t.Errorf("got %q, want %q", got, "what is TCP")
+		}
+	})
+
+	t.Run("file flag", func(t *testing.T) {
+		streams, _, _ := llm.TestIOStreams()
+		got, err := resolveQuestion(streams, nil, questionFile)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "what is a goroutine\n\nexplain briefly" {
+			t.Errorf("got %q", got)
+		}
+	})
+
+	t.Run("piped stdin", func(t *testing.T) {
+		streams, in, _ := llm.TestIOStreamsNonInteractive()
+		in.WriteString("a long\nmulti-paragraph\n\nquestion\n")
+		got, err := resolveQuestion(streams, nil, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "a long\nmulti-paragraph\n\nquestion" {
+			t.Errorf("got %q", got)
+		}
+	})
+
+	t.Run("argument and file conflict", func(t *testing.T) {
+		streams, _, _ := llm.TestIOStreams()
+		_, err := resolveQuestion(streams, []string{"question"}, questionFile)
+		if err == nil || !strings.Contains(err.Error(), "not both") {
+			t.Errorf("expected conflict error, got %v", err)
+		}
+	})
+
+	t.Run("terminal without input", func(t *testing.T) {
+		streams, _, _ := llm.TestIOStreams()
+		if _, err := resolveQuestion(streams, nil, ""); err == nil {
+			t.Error("expected error when no question is provided")
+		}
+	})
+
+	t.Run("empty stdin", func(t *testing.T) {
+		streams, _, _ := llm.TestIOStreamsNonInteractive()
+		if _, err := resolveQuestion(streams, nil, ""); err == nil {
+			t.Error("expected error for empty question")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		streams, _, _ := llm.TestIOStreams()
+		if _, err := resolveQuestion(streams, nil, filepath.Join(tmpDir, "missing.txt")); err == nil {
+			t.Error("expected error for missing file")
+		}
+	})
+}
+
+func TestResolveContext(t *testing.T) {
+	contextFile := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(contextFile, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("piped stdin with question argument", func(t *testing.T) {
+		streams, in, _ := llm.TestIOStreamsNonInteractive()
+		in.WriteString("func main() {}\n")
+		got, truncated, err := resolveContext(streams, []string{"what does this do"}, "", 100)
+		if err != nil || truncated || got != "func main() {}\n" {
+			t.Errorf("resolveContext() = %q, %v, %v", got, truncated, err)
+		}
+	})
+
+	t.Run("stdin is the question without an argument", func(t *testing.T) {
+		streams, in, _ := llm.TestIOStreamsNonInteractive()
+		in.WriteString("what is TCP")
+		got, _, err := resolveContext(streams, nil, "", 100)
+		if err != nil || got != "" {
+			t.Errorf("resolveContext() = %q, %v, want no context", got, err)
+		}
+		if in.Len() == 0 {
+			t.Error("stdin was consumed as context")
+		}
+	})
+
+	t.Run("terminal stdin is not read", func(t *testing.T) {
+		streams, _, _ := llm.TestIOStreams()
+		got, _, err := resolveContext(streams, []string{"question"}, "", 100)
+		if err != nil || got != "" {
+			t.Errorf("resolveContext() = %q, %v, want no context", got, err)
+		}
+	})
+
+	t.Run("context file", func(t *testing.T) {
+		streams, _, _ := llm.TestIOStreams()
+		got, _, err := resolveContext(streams, []string{"question"}, contextFile, 100)
+		if err != nil || got != "package main\n" {
+			t.Errorf("resolveContext() = %q, %v", got, err)
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		streams, _, _ := llm.TestIOStreams()
+		got, truncated, err := resolveContext(streams, []string{"question"}, contextFile, 7)
+		if err != nil || !truncated || got != "package" {
+			t.Errorf("resolveContext() = %q, %v, %v, want truncated %q", got, truncated, err, "package")
+		}
+	})
+
+	t.Run("missing context file", func(t *testing.T) {
+		streams, _, _ := llm.TestIOStreams()
+		if _, _, err := resolveContext(streams, []string{"question"}, filepath.Join(t.TempDir(), "missing"), 100); err == nil {
+			t.Error("expected error for missing context file")
+		}
+	})
+}
+
+func TestPromptOnly(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "ask",
+			args: []string{"ask", "--prompt-only", "what is FastAPI"},
+			want: []string{"You are a helpful technical assistant", "User's Question: what is FastAPI"},
+		},
+		{
+			name: "do",
+			args: []string{"do", "--prompt-only", "--shell", "fish", "list large files"},
+			want: []string{"translate the user's request into a single", "Write fish syntax", "User's Request: list large files"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := NewRootCmd()
+			var out bytes.Buffer
+			root.SetOut(&out)
+			// An unknown provider fails if anything tries to resolve it
+			root.SetArgs(append([]string{
+				"--config", filepath.Join(t.TempDir(), "config.yaml"),
+				"--provider", "does-not-exist",
+			}, tt.args...))
+
+			if err := root.Execute(); err != nil {
+				t.Fatalf("%s --prompt-only error = %v", tt.name, err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("prompt missing %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestCommandFlagsAreIndependent(t *testing.T) {
+	tests := []struct {
+		name  string
+		new   func() *cobra.Command
+		flag  string
+		value string
+	}{
+		{name: "ask", new: NewAskCmd, flag: "prompt-only", value: "true"},
+		{name: "ask count", new: NewAskCmd, flag: "count", value: "3"},
+		{name: "do", new: NewDoCmd, flag: "json", value: "true"},
+		{name: "do shell", new: NewDoCmd, flag: "shell", value: "fish"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := tt.new(), tt.new()
+			if err := first.Flags().Set(tt.flag, tt.value); err != nil {
+				t.Fatal(err)
+			}
+			want := second.Flags().Lookup(tt.flag).DefValue
+			if got := second.Flags().Lookup(tt.flag).Value.String(); got != want {
+				t.Errorf("--%s on a new command = %q, want the default %q", tt.flag, got, want)
+			}
+		})
+	}
+}
+
+func TestAskJSONReportsResolvedProvider(t *testing.T) {
+	bin := t.TempDir()
+	script := "#!/bin/sh\necho \"FastAPI is a Python web framework.\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "claude"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "single answer", args: []string{"ask", "what is FastAPI"}},
+		{name: "count", args: []string{"ask", "--count", "2", "what is FastAPI"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := NewRootCmd()
+			var out bytes.Buffer
+			root.SetOut(&out)
+			root.SetArgs(append([]string{
+				"--config", filepath.Join(t.TempDir(), "config.yaml"),
+				"--provider", "auto",
+				"--output-format", "json",
+			}, tt.args...))
+
+			if err := root.Execute(); err != nil {
+				t.Fatalf("ask error = %v", err)
+			}
+			for _, want := range []string{`"provider": "claude"`, `"model": "haiku"`} {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output missing %s:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
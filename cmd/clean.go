@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"github.com/connorhough/smix/internal/clean"
+	"github.com/connorhough/smix/internal/do"
+	"github.com/spf13/cobra"
+)
+
+func newCleanCmd() *cobra.Command {
+	var (
+		dryRun  bool
+		reviews bool
+		history bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Remove fetched review directories and other generated files",
+		Long: `Remove files smix generates as it runs.
+
+--reviews removes the pr_review_* and gca_* directories directly under the review
+output base (commands.pr.output_base, default $XDG_CACHE_HOME/smix/reviews). Other
+files there, and review directories written elsewhere with --local or --out, are
+left alone. --history removes the do history ($XDG_DATA_HOME/smix/do_history.jsonl).
+Without either flag only review directories are removed.
+
+Use --dry-run to list what would be removed without removing anything.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := clean.Options{Reviews: reviews || !history, History: history}
+
+			var err error
+			if opts.ReviewBase, err = reviewOutputBase(); err != nil {
+				return err
+			}
+			if opts.HistoryPath, err = do.DefaultHistoryPath(); err != nil {
+				return err
+			}
+
+			return clean.Clean(cmd.OutOrStdout(), opts, dryRun)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List what would be removed without removing anything")
+	cmd.Flags().BoolVar(&reviews, "reviews", false, "Remove fetched review directories under the review output base (the default)")
+	cmd.Flags().BoolVar(&history, "history", false, "Remove the do history file")
+	return cmd
+}
@@ -2,48 +2,288 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"strconv"
 
 	"github.com/connorhough/smix/internal/config"
 	"github.com/connorhough/smix/internal/do"
+	"github.com/connorhough/smix/internal/llm"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
+// doFlags holds the do command's flag values
+type doFlags struct {
+	iUnderstand bool
+	output      string
+	force       bool
+	json        bool
+	interactive bool
+	cli         bool
+	history     bool
+	noHistory   bool
+	explain     bool
+	shell       string
+	promptOnly  bool
+	preview     bool
+}
+
 // NewDoCmd creates and returns the do command
 func NewDoCmd() *cobra.Command {
+	var flags doFlags
+
 	doCmd := &cobra.Command{
 		Use:   "do \"natural language task description\"",
 		Short: "Translate natural language to shell commands",
 		Long: `Translate natural language task descriptions into executable shell commands
 using your configured LLM provider.
 
-Supports multiple providers (Claude, Gemini) with per-command configuration.`,
-		Args: cobra.ExactArgs(1),
-		RunE: runDo,
+Supports multiple providers (Claude, Gemini) with per-command configuration.
+
+Generated commands are scanned for destructive patterns (rm -rf, mkfs, dd to a
+device, fork bombs, curl | sh). Dangerous commands are withheld unless
+--i-understand is passed. Extra regular expressions can be added to the denylist
+with the commands.do.denylist config key.
+
+Use --explain to print a 1-2 sentence explanation after the command. Without it
+the output is only the command, so it can be piped or evaluated directly.
+
+Commands are written for the shell given by --shell (bash, zsh, fish, or
+powershell). By default the shell is detected from $SHELL, falling back to bash.
+
+Use --prompt-only to print the prompt that would be sent without contacting a
+provider.
+
+Use --preview to see, as a diff on stderr, what a generated in-place edit would change
+before running it. Only single sed -i substitutions can be previewed; files are read
+but never written.
+
+Use --interactive to refine the command conversationally ("use gzip not zip",
+"add verbose") before accepting it with Enter. Each candidate is risk-checked, and the
+accepted command goes through the same checks, output and history as any other.
+Add --cli to hand the session to the claude or gemini CLI on a terminal instead; the
+CLI shows its own commands, which are not risk-checked, recorded or returned, so
+--cli cannot be combined with --output, --explain, --preview or --output-format json.
+
+Generated commands are recorded in $XDG_DATA_HOME/smix/do_history.jsonl.
+Use --history [N] to print the last N entries (default 10). Disable recording
+with --no-history, or for every run with commands.do.history: false.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if flags.history {
+				return cobra.MaximumNArgs(1)(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDo(cmd, args, &flags)
+		},
 	}
 
+	doCmd.Flags().BoolVar(&flags.iUnderstand, "i-understand", false, "Print commands classified as dangerous")
+	doCmd.Flags().StringVarP(&flags.output, "output", "o", "", "Write the command to a file instead of stdout")
+	doCmd.Flags().BoolVar(&flags.force, "force", false, "Overwrite the --output file if it exists")
+	doCmd.Flags().BoolVarP(&flags.interactive, "interactive", "i", false, "Refine the generated command with follow-up instructions")
+	doCmd.Flags().BoolVar(&flags.cli, "cli", false, "With --interactive, hand the session to the provider's own CLI (commands are not risk-checked or recorded)")
+	doCmd.Flags().BoolVar(&flags.json, "json", false, "Request structured JSON from the provider to reliably extract the command")
+	doCmd.Flags().BoolVar(&flags.history, "history", false, "Print the last N generated commands instead of generating one (smix do --history [N])")
+	doCmd.Flags().BoolVar(&flags.explain, "explain", false, "Follow the command with a blank line and a short explanation of it")
+	doCmd.Flags().StringVar(&flags.shell, "shell", "", "Shell syntax to generate: bash, zsh, fish, or powershell (default: detected from $SHELL)")
+	doCmd.Flags().BoolVar(&flags.promptOnly, "prompt-only", false, "Print the prompt that would be sent and exit without calling the provider")
+	doCmd.Flags().BoolVar(&flags.preview, "preview", false, "Show the changes a generated sed -i substitution would make as a diff on stderr")
+	doCmd.Flags().BoolVar(&flags.noHistory, "no-history", false, "Do not record this command in the history file")
+
 	return doCmd
 }
 
-func runDo(cmd *cobra.Command, args []string) error {
+func runDo(cmd *cobra.Command, args []string, flags *doFlags) error {
+	history, err := newDoHistory(flags.noHistory)
+	if err != nil {
+		return err
+	}
+	if flags.history {
+		return printDoHistory(cmd, history, args)
+	}
+
 	taskDescription := args[0]
 
 	// Resolve configuration
 	cfg := config.ResolveProviderConfig("do")
 	cfg.ApplyFlags(providerFlag, modelFlag)
+	applyRetriesFlag(cmd, cfg)
 
 	slog.Debug("resolved config for 'do'", "provider", cfg.Provider, "model", cfg.Model)
 
+	shell := flags.shell
+	if shell == "" {
+		shell = do.DetectShell(os.Getenv("SHELL"))
+	}
+	if err := do.ValidateShell(shell); err != nil {
+		return err
+	}
+	slog.Debug("target shell for 'do'", "shell", shell)
+
+	if err := checkHandOffFlags(flags); err != nil {
+		return err
+	}
+
 	ctx := cmd.Context()
+	denylist := viper.GetStringSlice("commands.do.denylist")
+	opts := do.Options{
+		Shell:           shell,
+		JSON:            flags.json,
+		StopAtBlankLine: viper.GetBool("commands.do.stop_at_blank_line"),
+		Denylist:        denylist,
+		AllowDangerous:  flags.iUnderstand,
+		HandOff:         flags.cli,
+	}
+
+	if flags.promptOnly {
+		if flags.interactive {
+			return fmt.Errorf("--prompt-only cannot be combined with --interactive")
+		}
+		return writeResult(cmd.OutOrStdout(), flags.output, flags.force, do.BuildPrompt(taskDescription, opts))
+	}
 
 	// Translate
-	shellCommand, err := do.Translate(ctx, taskDescription, cfg)
+	var shellCommand string
+	if flags.interactive {
+		if outputFormat == outputFormatJSON {
+			return fmt.Errorf("--output-format json cannot be combined with --interactive")
+		}
+		shellCommand, err = do.Refine(ctx, llm.NewIOStreams(), taskDescription, cfg, opts)
+		if err != nil || shellCommand == "" {
+			return err
+		}
+	} else {
+		err = withSpinner(llm.NewIOStreams(), "Generating command...", func() error {
+			var err error
+			shellCommand, err = do.Translate(ctx, taskDescription, cfg, opts)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	risk := do.ClassifyRiskWithDenylist(shellCommand, denylist)
+	slog.Debug("classified command risk", "risk", risk)
+
+	switch risk {
+	case do.Dangerous:
+		if !flags.iUnderstand {
+			return fmt.Errorf("refusing to print a potentially destructive command; re-run with --i-understand to see it")
+		}
+		fmt.Fprintln(cmd.ErrOrStderr(), "warning: this command is potentially destructive, review it carefully before running")
+	case do.Caution:
+		fmt.Fprintln(cmd.ErrOrStderr(), "caution: this command modifies or removes data, review it before running")
+	}
+
+	if flags.preview {
+		writeEditPreview(cmd.ErrOrStderr(), shellCommand)
+	}
+
+	text := shellCommand
+	var explanation string
+	if flags.explain {
+		err = withSpinner(llm.NewIOStreams(), "Explaining command...", func() error {
+			var err error
+			explanation, err = do.Explain(ctx, shellCommand, taskDescription, cfg)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		text = shellCommand + "\n\n" + explanation
+	}
+
+	output, err := renderResult(outputFormat, text, result{
+		Request:     taskDescription,
+		Command:     shellCommand,
+		Explanation: explanation,
+		Shell:       shell,
+	})
 	if err != nil {
 		return err
 	}
 
+	if err := history.Record(taskDescription, shellCommand); err != nil {
+		slog.Warn("failed to record do history", "error", err)
+	}
+
 	// Print the resulting shell command
-	fmt.Println(shellCommand)
+	return writeResult(cmd.OutOrStdout(), flags.output, flags.force, output)
+}
+
+// checkHandOffFlags rejects --cli without --interactive, and with flags that need the command
+// back, since a session handed to the provider's CLI returns none
+func checkHandOffFlags(flags *doFlags) error {
+	if !flags.cli {
+		return nil
+	}
+	if !flags.interactive {
+		return fmt.Errorf("--cli requires --interactive")
+	}
+
+	var conflict string
+	switch {
+	case flags.output != "":
+		conflict = "--output"
+	case flags.explain:
+		conflict = "--explain"
+	case flags.preview:
+		conflict = "--preview"
+	case outputFormat == outputFormatJSON:
+		conflict = "--output-format json"
+	default:
+		return nil
+	}
+	return fmt.Errorf("%s cannot be combined with --cli: the CLI session shows its own commands and returns none", conflict)
+}
+
+// writeEditPreview writes the diff an in-place edit command would produce, or a note when
+// the command cannot be previewed
+func writeEditPreview(w io.Writer, shellCommand string) {
+	diff, ok, err := do.PreviewEdit(shellCommand)
+	switch {
+	case err != nil:
+		fmt.Fprintf(w, "warning: cannot preview this edit: %v\n", err)
+	case !ok:
+		fmt.Fprintln(w, "note: no preview available (only sed -i substitutions can be previewed)")
+	default:
+		fmt.Fprint(w, diff)
+	}
+}
+
+// newDoHistory returns the do history, disabled by --no-history or commands.do.history: false
+func newDoHistory(noHistory bool) (*do.History, error) {
+	path, err := do.DefaultHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+	disabled := noHistory || (viper.IsSet("commands.do.history") && !viper.GetBool("commands.do.history"))
+	return do.NewHistory(path, disabled), nil
+}
+
+// printDoHistory prints the last N history entries, where N is the optional argument to --history
+func printDoHistory(cmd *cobra.Command, history *do.History, args []string) error {
+	limit := do.DefaultHistoryLimit
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("--history expects a positive number of entries, got %q", args[0])
+		}
+		limit = n
+	}
 
-	return nil
+	entries, err := history.Last(limit)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(cmd.ErrOrStderr(), "No do history yet")
+		return nil
+	}
+	return do.WriteHistory(cmd.OutOrStdout(), entries)
 }
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDoCLIFlagConflicts(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "requires interactive", args: []string{"--cli"}, want: "--cli requires --interactive"},
+		{name: "output", args: []string{"-i", "--cli", "--output", "cmd.sh"}, want: "--output cannot be combined with --cli"},
+		{name: "explain", args: []string{"-i", "--cli", "--explain"}, want: "--explain cannot be combined with --cli"},
+		{name: "preview", args: []string{"-i", "--cli", "--preview"}, want: "--preview cannot be combined with --cli"},
+		{name: "json output", args: []string{"-i", "--cli", "--output-format", "json"}, want: "--output-format json cannot be combined with --cli"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := NewRootCmd()
+			args := append([]string{"--config", filepath.Join(t.TempDir(), "config.yaml"), "do", "--shell", "bash"}, tt.args...)
+			root.SetArgs(append(args, "list files"))
+
+			err := root.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
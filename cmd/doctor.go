@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/connorhough/smix/internal/doctor"
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+func newDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose provider availability and configuration",
+		Long: `Check each provider's CLI and environment requirements, send a short test
+prompt to each one, and show the resolved provider and model for every command.
+
+Exits with an error if the provider configured for any command is unusable.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			checker := doctor.NewChecker(cmd.OutOrStdout())
+			checker.Style = llm.NewIOStreams().Styler()
+			return checker.Run(cmd.Context())
+		},
+	}
+}
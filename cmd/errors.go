@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/pr"
+)
+
+// Formats accepted by the --error-format flag
+const (
+	errorFormatText = "text"
+	errorFormatJSON = "json"
+)
+
+// Error kinds reported by --error-format json
+const (
+	errorKindAuth                = "auth"
+	errorKindRateLimit           = "rate_limit"
+	errorKindNotFound            = "not_found"
+	errorKindProviderUnavailable = "provider_unavailable"
+	errorKindEmptyResponse       = "empty_response"
+	errorKindOther               = "other"
+)
+
+// errorReport is the JSON object printed for a failed command with --error-format json
+type errorReport struct {
+	Error    string `json:"error"`
+	Kind     string `json:"kind"`
+	Provider string `json:"provider,omitempty"`
+	Hint     string `json:"hint,omitempty"`
+}
+
+// validateErrorFormat reports an error for values other than text and json
+func validateErrorFormat(format string) error {
+	switch format {
+	case errorFormatText, errorFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid --error-format %q (expected %s or %s)", format, errorFormatText, errorFormatJSON)
+	}
+}
+
+// errorKind maps the provider or GitHub error classification to the kind reported to scripts
+func errorKind(err error) string {
+	var notFound *pr.NotFoundError
+	switch {
+	case errors.As(err, &notFound):
+		return errorKindNotFound
+	case errors.Is(err, pr.ErrGitHubAuth):
+		return errorKindAuth
+	}
+
+	switch llm.KindOf(err) {
+	case llm.KindAuthentication:
+		return errorKindAuth
+	case llm.KindRateLimit:
+		return errorKindRateLimit
+	case llm.KindModelNotFound:
+		return errorKindNotFound
+	case llm.KindNotAvailable:
+		return errorKindProviderUnavailable
+	case llm.KindEmptyResponse:
+		return errorKindEmptyResponse
+	default:
+		return errorKindOther
+	}
+}
+
+// ReportError prints err to w as plain text with an optional hint line, or as a single
+// JSON object when --error-format json was given
+func ReportError(w io.Writer, err error) {
+	if errorFormat != errorFormatJSON {
+		fmt.Fprintln(w, err)
+		if hint := llm.HintOf(err); hint != "" {
+			fmt.Fprintf(w, "hint: %s\n", hint)
+		}
+		return
+	}
+
+	report := errorReport{
+		Error:    err.Error(),
+		Kind:     errorKind(err),
+		Provider: llm.ProviderOf(err),
+		Hint:     llm.HintOf(err),
+	}
+	if encodeErr := json.NewEncoder(w).Encode(report); encodeErr != nil {
+		fmt.Fprintln(w, err)
+	}
+}
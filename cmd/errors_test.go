@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/pr"
+)
+
+func TestErrorKind(t *testing.T) {
+	cause := errors.New("cause")
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"authentication", llm.ErrAuthenticationFailed("gemini", cause), errorKindAuth},
+		{"rate limit", llm.ErrRateLimitExceeded("claude", cause), errorKindRateLimit},
+		{"model not found", llm.ErrModelNotFound("opuss", "claude", cause), errorKindNotFound},
+		{"provider not available", llm.ErrProviderNotAvailable("claude", cause), errorKindProviderUnavailable},
+		{"empty response", llm.ErrEmptyResponse("gemini", cause), errorKindEmptyResponse},
+		{"wrapped provider error", fmt.Errorf("failed to get provider: %w", llm.ErrAuthenticationFailed("gemini", cause)), errorKindAuth},
+		{"PR not found", &pr.NotFoundError{Owner: "o", Repo: "r", Number: 1}, errorKindNotFound},
+		{"GitHub auth", fmt.Errorf("%w for o/r", pr.ErrGitHubAuth), errorKindAuth},
+		{"plain error", errors.New("invalid PR number"), errorKindOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorKind(tt.err); got != tt.want {
+				t.Errorf("errorKind() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReportError(t *testing.T) {
+	t.Cleanup(func() { errorFormat = errorFormatText })
+	err := fmt.Errorf("failed to get provider: %w", llm.ErrRateLimitExceeded("gemini", errors.New("429")))
+
+	t.Run("text", func(t *testing.T) {
+		errorFormat = errorFormatText
+		var out bytes.Buffer
+		ReportError(&out, err)
+
+		want := err.Error() + "\nhint: " + llm.HintOf(err) + "\n"
+		if out.String() != want {
+			t.Errorf("ReportError() = %q, want %q", out.String(), want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		errorFormat = errorFormatJSON
+		var out bytes.Buffer
+		ReportError(&out, err)
+
+		var got errorReport
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatalf("invalid JSON output %q: %v", out.String(), err)
+		}
+		want := errorReport{Error: err.Error(), Kind: errorKindRateLimit, Provider: "gemini", Hint: llm.HintOf(err)}
+		if got != want {
+			t.Errorf("ReportError() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("json for plain errors", func(t *testing.T) {
+		errorFormat = errorFormatJSON
+		var out bytes.Buffer
+		ReportError(&out, errors.New("boom"))
+
+		if want := `{"error":"boom","kind":"other"}` + "\n"; out.String() != want {
+			t.Errorf("ReportError() = %q, want %q", out.String(), want)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// writeResult prints content to w, or writes it to path when set.
+// Existing files are only overwritten when force is true.
+func writeResult(w io.Writer, path string, force bool, content string) error {
+	if path == "" {
+		_, err := fmt.Fprintln(w, content)
+		return err
+	}
+
+	if !force {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("output file %s already exists (use --force to overwrite)", path)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, []byte(content+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	return nil
+}
+
+// Formats accepted by the --output-format flag
+const (
+	outputFormatText = "text"
+	outputFormatJSON = "json"
+)
+
+// result is the structured envelope printed by ask and do when --output-format is json
+type result struct {
+	Question    string   `json:"question,omitempty"`
+	Answer      string   `json:"answer,omitempty"`
+	Answers     []string `json:"answers,omitempty"`
+	Request     string   `json:"request,omitempty"`
+	Command     string   `json:"command,omitempty"`
+	Explanation string   `json:"explanation,omitempty"`
+	Shell       string   `json:"shell,omitempty"`
+	Provider    string   `json:"provider,omitempty"`
+	Model       string   `json:"model,omitempty"`
+}
+
+// validateOutputFormat reports an error for values other than text and json
+func validateOutputFormat(format string) error {
+	switch format {
+	case outputFormatText, outputFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid --output-format %q (expected %s or %s)", format, outputFormatText, outputFormatJSON)
+	}
+}
+
+// renderResult returns text unchanged for text output, or the indented JSON encoding of r for json output
+func renderResult(format, text string, r result) (string, error) {
+	if format != outputFormatJSON {
+		return text, nil
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode result: %w", err)
+	}
+	return string(data), nil
+}
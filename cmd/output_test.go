@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteResult(t *testing.T) {
+	t.Run("writes to stdout by default", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := writeResult(&out, "", false, "ls -la"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.String() != "ls -la\n" {
+			t.Errorf("got %q, want %q", out.String(), "ls -la\n")
+		}
+	})
+
+	t.Run("creates file and parent directories", func(t *testing.T) {
+		var out bytes.Buffer
+		path := filepath.Join(t.TempDir(), "nested", "dir", "answer.txt")
+
+		if err := writeResult(&out, path, false, "the answer"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read output file: %v", err)
+		}
+		if string(content) != "the answer\n" {
+			t.Errorf("file content = %q, want %q", content, "the answer\n")
+		}
+		if out.Len() != 0 {
+			t.Errorf("expected stdout to stay empty, got %q", out.String())
+		}
+	})
+
+	t.Run("refuses to overwrite without force", func(t *testing.T) {
+		var out bytes.Buffer
+		path := filepath.Join(t.TempDir(), "answer.txt")
+		if err := os.WriteFile(path, []byte("original"), 0o644); err != nil {
+			t.Fatalf("failed to seed file: %v", err)
+		}
+
+		err := writeResult(&out, path, false, "new")
+		if err == nil || !strings.Contains(err.Error(), "--force") {
+			t.Fatalf("expected overwrite error, got %v", err)
+		}
+
+		content, _ := os.ReadFile(path)
+		if string(content) != "original" {
+			t.Errorf("file was modified: %q", content)
+		}
+	})
+
+	t.Run("overwrites with force", func(t *testing.T) {
+		var out bytes.Buffer
+		path := filepath.Join(t.TempDir(), "answer.txt")
+		if err := os.WriteFile(path, []byte("original"), 0o644); err != nil {
+			t.Fatalf("failed to seed file: %v", err)
+		}
+
+		if err := writeResult(&out, path, true, "new"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		content, _ := os.ReadFile(path)
+		if string(content) != "new\n" {
+			t.Errorf("file content = %q, want %q", content, "new\n")
+		}
+		if out.Len() != 0 {
+			t.Errorf("expected stdout to stay empty, got %q", out.String())
+		}
+	})
+}
+
+func TestRenderResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		result result
+		want   map[string]string
+	}{
+		{
+			name: "ask",
+			text: "A web framework.",
+			result: result{
+				Question: "what is FastAPI",
+				Answer:   "A web framework.",
+				Provider: "claude",
+				Model:    "haiku",
+			},
+			want: map[string]string{
+				"question": "what is FastAPI",
+				"answer":   "A web framework.",
+				"provider": "claude",
+				"model":    "haiku",
+			},
+		},
+		{
+			name:   "do",
+			text:   "ls -la",
+			result: result{Request: "list files", Command: "ls -la"},
+			want: map[string]string{
+				"request": "list files",
+				"command": "ls -la",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" json", func(t *testing.T) {
+			got, err := renderResult(outputFormatJSON, tt.text, tt.result)
+			if err != nil {
+				t.Fatalf("renderResult() error = %v", err)
+			}
+
+			var decoded map[string]string
+			if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+				t.Fatalf("output is not valid JSON: %v\n%s", err, got)
+			}
+			if len(decoded) != len(tt.want) {
+				t.Errorf("got keys %v, want %v", decoded, tt.want)
+			}
+			for key, want := range tt.want {
+				if decoded[key] != want {
+					t.Errorf("%s = %q, want %q", key, decoded[key], want)
+				}
+			}
+		})
+
+		t.Run(tt.name+" text", func(t *testing.T) {
+			got, err := renderResult(outputFormatText, tt.text, tt.result)
+			if err != nil {
+				t.Fatalf("renderResult() error = %v", err)
+			}
+			if got != tt.text {
+				t.Errorf("renderResult() = %q, want %q", got, tt.text)
+			}
+		})
+	}
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	for _, format := range []string{outputFormatText, outputFormatJSON} {
+		if err := validateOutputFormat(format); err != nil {
+			t.Errorf("validateOutputFormat(%q) error = %v", format, err)
+		}
+	}
+	if err := validateOutputFormat("yaml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
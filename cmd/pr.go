@@ -1,14 +1,22 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
 	"github.com/connorhough/smix/internal/pr"
 	"github.com/google/go-github/github"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"golang.org/x/oauth2"
 )
 
@@ -20,12 +28,41 @@ func newPRCmd() *cobra.Command {
 	}
 
 	prCmd.AddCommand(newPRReviewCmd())
+	prCmd.AddCommand(newPRSummaryCmd())
+	prCmd.AddCommand(newPROpenCmd())
+	prCmd.AddCommand(newPRApplyCmd())
 
 	return prCmd
 }
 
 func newPRReviewCmd() *cobra.Command {
-	var useExistingDir string
+	var (
+		useExistingDir string
+		format         string
+		pathFilters    []string
+		noGeneral      bool
+		concurrency    int
+		dryRun         bool
+		promptTemplate string
+		providerList   []string
+		outPattern     string
+		since          string
+		refetch        bool
+		noFetch        bool
+		limit          int
+		check          bool
+		skipInvalid    bool
+		yes            bool
+		fullFileUnder  int
+		groupOverlap   bool
+		conventions    bool
+		commentIDs     []int64
+		delay          time.Duration
+		noIndex        bool
+		retryFailed    bool
+		retryRejected  bool
+		local          bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "review <repo> <pr_number>",
@@ -34,68 +71,235 @@ func newPRReviewCmd() *cobra.Command {
 
 Note: This command currently only supports Claude provider in interactive mode.
 
-The repo argument should be in the format "owner/name" (e.g. "octocat/Hello-World").
+The repo argument should be in the format "owner/name" (e.g. "octocat/Hello-World"),
+or just "name" when github.default_owner is configured.
 The pr_number argument should be the PR number (e.g. 123).
 
-To process an existing pr_review folder without fetching, use the --dir flag.`,
+To process an existing pr_review folder without fetching, use the --dir flag. When
+<repo> <pr_number> are also given, the PR head recorded in the folder is compared with
+the live PR and you are asked whether to refetch if it moved. --refetch always refetches
+and --no-fetch skips the check.
+
+Fetched feedback is written to pr_review_pr<number> under commands.pr.output_base
+(default $XDG_CACHE_HOME/smix/reviews) so it stays out of the working tree. Use
+--local to write it under the current directory instead. commands.pr.output_dir
+changes the name within the base, and --out gives the exact directory; {repo},
+{pr}, and {date} are replaced in both, e.g. --out 'reviews/{repo}/{pr}-{date}'.
+pr summary, pr open and pr apply resolve a {date} in commands.pr.output_dir to the
+latest existing directory, so feedback fetched on an earlier day is still found.
+
+Use --format json to write the extracted feedback to feedback.json for use by
+other tools instead of generating prompt files and launching sessions.
+
+Use --path-filter (repeatable) to keep only feedback on files matching a glob,
+e.g. --path-filter 'internal/**'. General PR comments are dropped when a filter
+is given or when --no-general is set.
+
+Use --comment-ids to keep only specific review comments by their ID (the number in
+a #discussion_r<ID> link), e.g. --comment-ids 1234,5678. General comments have no
+ID and are dropped.
+
+Use --since to fetch only feedback created or updated after a point in time, given
+as an RFC3339 timestamp, a date (2024-06-01), or a duration ago (24h, 7d).
+
+Use --check to confirm the PR exists with a single lightweight request before
+fetching, so a mistyped repo or PR number fails fast with "PR #N not found".
+
+Before any session starts, each prompt file is checked for the metadata and feedback
+sections written by the fetch. Malformed files (e.g. hand-edited) are reported; pass
+--skip-invalid to leave them out.
+
+Use --limit N to triage only the first N feedback items. Fetching writes only N
+prompt files and notes the truncation in INDEX.md; processing an existing --dir
+launches sessions for the first N files.
+
+Prompts show a window of the commented file (commands.pr.context_before and
+context_after). Files with fewer than --full-file-under lines (default 150, or
+commands.pr.full_file_under) are embedded whole instead.
+
+Comments on the same file within commands.pr.overlap_window lines (default 3) of
+each other can conflict when applied in sequence, so they are reported with a
+warning. --group-overlapping combines them into a single prompt instead.
+
+Use --conventions (or commands.pr.conventions: true) to embed the repository's
+CONVENTIONS.md from the PR head in every prompt, condensed and truncated to 8 KiB.
+
+When there are more feedback files than commands.pr.confirm_threshold (default 10),
+the files are listed and you are asked to confirm before any session starts. Pass
+--yes to skip the question.
+
+Use --delay (or commands.pr.delay) to wait between session launches, e.g. --delay 30s,
+when API-backed providers hit rate limits.
+
+Sessions that fail to launch are recorded as FAILED in the directory's decisions.json,
+alongside the decisions pr apply --batch records. After a pass, --dir X --retry-failed
+reprocesses only the items whose status in decisions.json is FAILED; add --retry-rejected
+to include REJECTED items as well (either flag may be used alone).`,
 		Args: func(cmd *cobra.Command, args []string) error {
-			// If --dir is set, allow 0 args, otherwise require 2
+			// With --dir, the repo and PR number are optional and enable the freshness check
 			if useExistingDir != "" {
-				return cobra.NoArgs(cmd, args)
+				if len(args) != 0 && len(args) != 2 {
+					return fmt.Errorf("--dir accepts no arguments, or <repo> <pr_number> to check that it is up to date")
+				}
+				return nil
 			}
 			return cobra.ExactArgs(2)(cmd, args)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var outputDir string
+			if format != pr.FormatMarkdown && format != pr.FormatJSON {
+				return fmt.Errorf("invalid format %q: must be %q or %q", format, pr.FormatMarkdown, pr.FormatJSON)
+			}
+			if format == pr.FormatJSON && useExistingDir != "" {
+				return fmt.Errorf("--format json cannot be combined with --dir")
+			}
+			if since != "" && useExistingDir != "" {
+				return fmt.Errorf("--since cannot be combined with --dir")
+			}
+			if limit < 0 {
+				return fmt.Errorf("--limit must not be negative")
+			}
+			if !cmd.Flags().Changed("delay") && viper.IsSet("commands.pr.delay") {
+				delay = viper.GetDuration("commands.pr.delay")
+			}
+			if delay < 0 {
+				return fmt.Errorf("--delay must not be negative")
+			}
+			if refetch && noFetch {
+				return fmt.Errorf("--refetch cannot be combined with --no-fetch")
+			}
+			var retryStatuses []string
+			if retryFailed {
+				retryStatuses = append(retryStatuses, pr.StatusFailed)
+			}
+			if retryRejected {
+				retryStatuses = append(retryStatuses, pr.StatusRejected)
+			}
+			if len(retryStatuses) > 0 && (useExistingDir == "" || refetch) {
+				return fmt.Errorf("--retry-failed and --retry-rejected require --dir and cannot be combined with --refetch")
+			}
+			if (refetch || noFetch) && (useExistingDir == "" || len(args) == 0) {
+				return fmt.Errorf("--refetch and --no-fetch require --dir with <repo> <pr_number>")
+			}
+			if err := pr.ValidatePathFilters(pathFilters); err != nil {
+				return err
+			}
 
-			// If using existing directory, skip fetching
-			if useExistingDir != "" {
-				outputDir = useExistingDir
-				fmt.Printf("Using existing directory: %s\n", outputDir)
-			} else {
-				// Parse repo owner and name
-				parts := strings.Split(args[0], "/")
-				if len(parts) != 2 {
-					return fmt.Errorf("invalid repo format. Expected 'owner/name', got '%s'", args[0])
-				}
-				repoOwner := parts[0]
-				repoName := parts[1]
+			ctx := cmd.Context()
 
-				// Parse PR number
-				var prNumber int
-				if _, err := fmt.Sscanf(args[1], "%d", &prNumber); err != nil {
-					return fmt.Errorf("invalid PR number: %w", err)
+			var (
+				repoOwner, repoName string
+				prNumber            int
+				client              *github.Client
+			)
+			if len(args) == 2 {
+				var err error
+				repoOwner, repoName, prNumber, err = parsePRArgs(args)
+				if err != nil {
+					return err
 				}
+				if !noFetch {
+					if client, err = newGitHubClientFromConfig(cmd); err != nil {
+						return err
+					}
+					if check {
+						if err := pr.CheckTarget(ctx, client.PullRequests, repoOwner, repoName, prNumber, rateLimitMaxWait()); err != nil {
+							return err
+						}
+					}
+				}
+			}
 
-				ctx := cmd.Context()
-
-				// Create GitHub client
-				var client *github.Client
-				if token := os.Getenv("GITHUB_TOKEN"); token != "" {
-					ts := oauth2.StaticTokenSource(
-						&oauth2.Token{AccessToken: token},
-					)
-					tc := oauth2.NewClient(ctx, ts)
-					client = github.NewClient(tc)
-				} else {
-					client = github.NewClient(nil)
+			outputDir := useExistingDir
+			fetch := useExistingDir == ""
+			if fetch {
+				var err error
+				if outputDir, err = reviewDir(outPattern, local, repoOwner, repoName, prNumber); err != nil {
+					return err
 				}
+			} else {
+				fmt.Fprintf(progressWriter(cmd), "Using existing directory: %s\n", outputDir)
+				if client != nil {
+					var err error
+					if fetch, err = shouldRefetch(cmd, client, outputDir, repoOwner, repoName, prNumber, refetch); err != nil {
+						return err
+					}
+					if fetch {
+						if err := pr.ClearPromptFiles(outputDir); err != nil {
+							return err
+						}
+					}
+				}
+			}
 
-				// Create output directory
-				outputDir = fmt.Sprintf("./pr_review_pr%d", prNumber)
+			if fetch {
+				sinceTime, err := pr.ParseSince(since, time.Now())
+				if err != nil {
+					return err
+				}
 
 				// Fetch reviews
-				if err := pr.FetchReviews(ctx, client, repoOwner, repoName, prNumber, outputDir); err != nil {
+				opts := pr.FetchOptions{
+					Format:           format,
+					PathFilters:      pathFilters,
+					NoGeneral:        noGeneral,
+					Concurrency:      concurrency,
+					Progress:         progressWriter(cmd),
+					ContextBefore:    pr.DefaultContextBefore,
+					ContextAfter:     pr.DefaultContextAfter,
+					RateLimitMaxWait: rateLimitMaxWait(),
+					Since:            sinceTime,
+					Limit:            limit,
+					FullFileUnder:    fullFileUnder,
+					OverlapWindow:    pr.DefaultOverlapWindow,
+					GroupOverlapping: groupOverlap,
+					Conventions:      conventions || viper.GetBool("commands.pr.conventions"),
+					CommentIDs:       commentIDs,
+					NoIndex:          noIndex,
+				}
+				if viper.IsSet("commands.pr.context_before") {
+					opts.ContextBefore = viper.GetInt("commands.pr.context_before")
+				}
+				if viper.IsSet("commands.pr.context_after") {
+					opts.ContextAfter = viper.GetInt("commands.pr.context_after")
+				}
+				if viper.IsSet("commands.pr.overlap_window") {
+					opts.OverlapWindow = viper.GetInt("commands.pr.overlap_window")
+				}
+				if !cmd.Flags().Changed("full-file-under") && viper.IsSet("commands.pr.full_file_under") {
+					opts.FullFileUnder = viper.GetInt("commands.pr.full_file_under")
+				}
+				if err := pr.FetchReviews(ctx, client, repoOwner, repoName, prNumber, outputDir, opts); err != nil {
 					return fmt.Errorf("failed to fetch reviews: %w", err)
 				}
+
+				// JSON output is meant for other tools, so there is nothing to process interactively
+				if format == pr.FormatJSON {
+					return nil
+				}
 			}
 
 			// Resolve configuration
 			cfg := config.ResolveProviderConfig("pr")
 			cfg.ApplyFlags(providerFlag, modelFlag)
 
+			if promptTemplate == "" {
+				promptTemplate = viper.GetString("commands.pr.prompt_template")
+			}
+
 			// Process reviews
-			if err := pr.ProcessReviews(cmd.Context(), outputDir, cfg); err != nil {
+			if err := pr.ProcessReviews(cmd.Context(), outputDir, cfg, pr.ProcessOptions{
+				DryRun:           dryRun,
+				PromptTemplate:   promptTemplate,
+				Progress:         progressWriter(cmd),
+				Out:              cmd.OutOrStdout(),
+				Providers:        providerList,
+				Limit:            limit,
+				SkipInvalid:      skipInvalid,
+				Yes:              yes,
+				Delay:            delay,
+				ConfirmThreshold: viper.GetInt("commands.pr.confirm_threshold"),
+				RetryStatuses:    retryStatuses,
+			}); err != nil {
 				return fmt.Errorf("failed to process reviews: %w", err)
 			}
 
@@ -104,5 +308,374 @@ To process an existing pr_review folder without fetching, use the --dir flag.`,
 	}
 
 	cmd.Flags().StringVar(&useExistingDir, "dir", "", "Use existing pr_review directory instead of fetching from GitHub")
+	cmd.Flags().BoolVar(&refetch, "refetch", false, "With --dir and <repo> <pr_number>, refetch feedback into the directory without checking freshness")
+	cmd.Flags().BoolVar(&noFetch, "no-fetch", false, "With --dir and <repo> <pr_number>, skip the PR head freshness check")
+	cmd.Flags().StringVar(&format, "format", pr.FormatMarkdown, "Output format for fetched feedback (markdown, json)")
+	cmd.Flags().StringArrayVar(&pathFilters, "path-filter", nil, "Only keep feedback on files matching this glob (repeatable, supports **)")
+	cmd.Flags().Int64SliceVar(&commentIDs, "comment-ids", nil, "Only keep review comments with these IDs, comma-separated (excludes general comments)")
+	cmd.Flags().BoolVar(&noIndex, "no-index", false, "Do not write INDEX.md alongside the prompt files (index.json is still written)")
+	cmd.Flags().BoolVar(&noGeneral, "no-general", false, "Exclude general PR comments that are not attached to a file")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the feedback files that would be processed without launching sessions")
+	cmd.Flags().StringVar(&promptTemplate, "prompt-template", "", "Path to a text/template file for the review session prompt (default: commands.pr.prompt_template or built-in)")
+	cmd.Flags().StringVar(&since, "since", "", "Only fetch feedback newer than a timestamp, date, or duration ago (e.g. 2024-06-01, 24h, 7d)")
+	cmd.Flags().StringVar(&outPattern, "out", "", "Directory for fetched feedback; supports {repo}, {pr}, and {date} (default: commands.pr.output_dir or pr_review_pr<number> under commands.pr.output_base)")
+	cmd.Flags().BoolVar(&local, "local", false, "Write fetched feedback under the current directory instead of commands.pr.output_base")
+	cmd.Flags().StringSliceVar(&providerList, "providers", nil, "Distribute feedback items across these providers round-robin, failing over on rate limits (e.g. claude,gemini)")
+	cmd.Flags().BoolVar(&skipInvalid, "skip-invalid", false, "Skip prompt files missing the expected metadata instead of only warning")
+	cmd.Flags().DurationVar(&delay, "delay", 0, "Wait this long between session launches to stay under provider rate limits (e.g. 30s)")
+	cmd.Flags().BoolVar(&retryFailed, "retry-failed", false, "With --dir, reprocess only items whose status in decisions.json is FAILED")
+	cmd.Flags().BoolVar(&retryRejected, "retry-rejected", false, "With --dir, reprocess only items whose status in decisions.json is REJECTED (combine with --retry-failed for both)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Launch sessions without asking for confirmation, however many feedback files there are")
+	cmd.Flags().BoolVar(&check, "check", false, "Confirm the PR exists before fetching anything else")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Only fetch and process the first N feedback items (0 for all)")
+	cmd.Flags().IntVar(&fullFileUnder, "full-file-under", pr.DefaultFullFileUnder, "Embed the whole file in prompts for files with fewer than N lines instead of a snippet (0 always uses a snippet)")
+	cmd.Flags().BoolVar(&groupOverlap, "group-overlapping", false, "Combine comments on nearby lines of the same file into one prompt instead of only warning")
+	cmd.Flags().BoolVar(&conventions, "conventions", false, "Embed the repository's CONVENTIONS.md (condensed) in every prompt")
+	cmd.Flags().IntVar(&concurrency, "concurrency", pr.DefaultFetchConcurrency, "Maximum concurrent file content requests to GitHub")
+	return cmd
+}
+
+// reviewDir returns the feedback directory for a PR. An --out pattern is used as given;
+// otherwise commands.pr.output_dir (or the default name) is placed under the output base,
+// or under the current directory with --local.
+func reviewDir(outFlag string, local bool, repoOwner, repoName string, prNumber int) (string, error) {
+	if outFlag != "" {
+		return pr.ReviewDir("", outFlag, repoOwner, repoName, prNumber, time.Now()), nil
+	}
+
+	base := ""
+	if !local {
+		var err error
+		if base, err = reviewOutputBase(); err != nil {
+			return "", err
+		}
+	}
+	return pr.ReviewDir(base, viper.GetString("commands.pr.output_dir"), repoOwner, repoName, prNumber, time.Now()), nil
+}
+
+// existingReviewDir returns the feedback directory pr review fetched a PR into. Unlike reviewDir,
+// a {date} in commands.pr.output_dir resolves to the latest existing directory rather than today.
+func existingReviewDir(local bool, repoOwner, repoName string, prNumber int) (string, error) {
+	base := ""
+	if !local {
+		var err error
+		if base, err = reviewOutputBase(); err != nil {
+			return "", err
+		}
+	}
+	return pr.FindReviewDir(base, viper.GetString("commands.pr.output_dir"), repoOwner, repoName, prNumber, time.Now()), nil
+}
+
+// reviewOutputBase returns commands.pr.output_base, or pr.DefaultOutputBase when unset
+func reviewOutputBase() (string, error) {
+	return pr.OutputBase(viper.GetString("commands.pr.output_base"))
+}
+
+// parsePRArgs parses the "owner/name" (or "name" with github.default_owner) and PR number positional arguments
+func parsePRArgs(args []string) (owner, name string, number int, err error) {
+	repo, err := pr.ExpandRepo(args[0], viper.GetString("github.default_owner"))
+	if err != nil {
+		return "", "", 0, err
+	}
+	return pr.ParseTarget(repo, args[1])
+}
+
+// rateLimitMaxWait returns github.rate_limit_max_wait, or pr.DefaultRateLimitMaxWait when unset
+func rateLimitMaxWait() time.Duration {
+	if viper.IsSet("github.rate_limit_max_wait") {
+		return viper.GetDuration("github.rate_limit_max_wait")
+	}
+	return pr.DefaultRateLimitMaxWait
+}
+
+// newGitHubClientFromConfig resolves a GitHub token and creates a client, warning when access is anonymous
+func newGitHubClientFromConfig(cmd *cobra.Command) (*github.Client, error) {
+	ctx := cmd.Context()
+	token, err := resolveGitHubToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		fmt.Fprintln(cmd.ErrOrStderr(), "warning: no GitHub token found (GITHUB_TOKEN, github.token_file, or gh auth token); using anonymous access, which is limited to 60 requests per hour")
+	}
+	return newGitHubClient(ctx, token), nil
+}
+
+// shouldRefetch reports whether an existing review directory should be refetched: always with
+// --refetch, otherwise only when the PR head moved and the user agrees. Non-interactive runs
+// keep the directory and print a warning instead of prompting.
+func shouldRefetch(cmd *cobra.Command, client *github.Client, dir, repoOwner, repoName string, prNumber int, refetch bool) (bool, error) {
+	if refetch {
+		return true, nil
+	}
+
+	freshness, err := pr.CheckFreshness(cmd.Context(), client.PullRequests, dir, repoOwner, repoName, prNumber, rateLimitMaxWait())
+	if err != nil {
+		return false, err
+	}
+	if !freshness.Stale() {
+		return false, nil
+	}
+
+	if freshness.RecordedSHA == "" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "%s has no recorded PR head and may be out of date (PR head is now %s)\n", dir, shortSHA(freshness.HeadSHA))
+	} else {
+		fmt.Fprintf(cmd.ErrOrStderr(), "%s was fetched at %s but the PR head is now %s\n", dir, shortSHA(freshness.RecordedSHA), shortSHA(freshness.HeadSHA))
+	}
+
+	if !llm.NewIOStreams().IsInteractive() {
+		fmt.Fprintln(cmd.ErrOrStderr(), "warning: using the existing directory (pass --refetch to update it)")
+		return false, nil
+	}
+
+	fmt.Fprint(cmd.ErrOrStderr(), "Refetch feedback? [y/N]: ")
+	answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// shortSHA abbreviates a commit SHA for display
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// resolveGitHubToken finds a GitHub token, returning an empty string when none is configured
+func resolveGitHubToken(ctx context.Context) (string, error) {
+	token, source, err := pr.NewTokenResolver().Resolve(ctx, viper.GetString("github.token_file"))
+	if err != nil {
+		return "", err
+	}
+	if token != "" {
+		slog.Debug("using GitHub token", "source", source)
+	}
+	return token, nil
+}
+
+// newGitHubClient creates a GitHub client authenticated with token, or an anonymous client when token is empty
+func newGitHubClient(ctx context.Context, token string) *github.Client {
+	if token == "" {
+		return github.NewClient(nil)
+	}
+	ts := oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: token},
+	)
+	return github.NewClient(oauth2.NewClient(ctx, ts))
+}
+
+func newPRSummaryCmd() *cobra.Command {
+	var (
+		dir    string
+		dryRun bool
+		local  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "summary <repo> <pr_number>",
+		Short: "Post a summary of review decisions as a PR comment",
+		Long: `Read the per-item decisions recorded in a pr_review directory (decisions.json) and post
+a single summary comment to the pull request listing each item's outcome and reasoning.
+
+Decisions are recorded by pr apply --batch, from each response's STATUS report, and by
+pr review, which records items whose session failed to launch as FAILED.
+
+Posting requires a GitHub token with write access to the repository. Use --dry-run to
+print the comment body without posting it.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoOwner, repoName, prNumber, err := parsePRArgs(args)
+			if err != nil {
+				return err
+			}
+
+			if dir == "" {
+				if dir, err = existingReviewDir(local, repoOwner, repoName, prNumber); err != nil {
+					return err
+				}
+			}
+
+			decisions, err := pr.LoadDecisions(dir)
+			if err != nil {
+				return err
+			}
+			body := pr.FormatSummaryComment(decisions)
+
+			if dryRun {
+				fmt.Fprint(cmd.OutOrStdout(), body)
+				return nil
+			}
+
+			ctx := cmd.Context()
+			token, err := resolveGitHubToken(ctx)
+			if err != nil {
+				return err
+			}
+			if token == "" {
+				return fmt.Errorf("posting a summary requires a GitHub token with write access (set GITHUB_TOKEN, github.token_file, or run 'gh auth login')")
+			}
+
+			url, err := pr.PostSummaryComment(ctx, newGitHubClient(ctx, token).Issues, repoOwner, repoName, prNumber, body)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "✓ Summary posted: %s\n", url)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", "", "pr_review directory containing decisions.json (default: the directory pr review fetched into)")
+	cmd.Flags().BoolVar(&local, "local", false, "Look for the pr_review directory under the current directory instead of commands.pr.output_base")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the comment body instead of posting it")
+	return cmd
+}
+
+func newPROpenCmd() *cobra.Command {
+	var (
+		dir   string
+		local bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "open [<repo> <pr_number>]",
+		Short: "Open a fetched pr_review directory in your editor",
+		Long: `Print the path of a pr_review directory's INDEX.md (or the directory itself when it has
+no index) and open it with $EDITOR. Without $EDITOR only the path is printed, so it can be
+used as cd "$(dirname "$(smix pr open owner/repo 123)")".
+
+The directory is located the same way pr review names it (under commands.pr.output_base,
+or the current directory with --local), or given explicitly with --dir.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if dir != "" {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(2)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dir == "" {
+				repoOwner, repoName, prNumber, err := parsePRArgs(args)
+				if err != nil {
+					return err
+				}
+				if dir, err = existingReviewDir(local, repoOwner, repoName, prNumber); err != nil {
+					return err
+				}
+			}
+
+			path, err := pr.OpenPath(dir)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), path)
+
+			editor := os.Getenv("EDITOR")
+			if editor == "" {
+				return nil
+			}
+			return pr.OpenInEditor(cmd.Context(), editor, path, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", "", "pr_review directory to open (default: the directory pr review fetched into)")
+	cmd.Flags().BoolVar(&local, "local", false, "Look for the pr_review directory under the current directory instead of commands.pr.output_base")
+	return cmd
+}
+
+func newPRApplyCmd() *cobra.Command {
+	var (
+		commentID      int64
+		batch          bool
+		promptTemplate string
+		conventions    bool
+		preview        bool
+		dir            string
+		local          bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply <repo> <pr_number> --comment-id <id>",
+		Short: "Act on a single review comment without writing a pr_review directory",
+		Long: `Fetch one gemini-code-assist review comment, build its prompt, and launch a single
+interactive session for it. Nothing is written to a pr_review directory.
+
+With --batch the prompt is sent in a single request instead and the response, documenting
+the decision and any proposed change as a diff, is printed; no files are edited. The decision
+is recorded in the pr_review directory's decisions.json, under the comment's prompt file
+name, for pr summary and pr review --retry-failed/--retry-rejected.
+
+With --preview the prompt is only printed, with its diff hunk colored (see --color),
+and no provider is contacted.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoOwner, repoName, prNumber, err := parsePRArgs(args)
+			if err != nil {
+				return err
+			}
+			client, err := newGitHubClientFromConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			opts := pr.FetchOptions{
+				Progress:         progressWriter(cmd),
+				ContextBefore:    pr.DefaultContextBefore,
+				ContextAfter:     pr.DefaultContextAfter,
+				RateLimitMaxWait: rateLimitMaxWait(),
+				FullFileUnder:    pr.DefaultFullFileUnder,
+				Conventions:      conventions || viper.GetBool("commands.pr.conventions"),
+			}
+			if viper.IsSet("commands.pr.context_before") {
+				opts.ContextBefore = viper.GetInt("commands.pr.context_before")
+			}
+			if viper.IsSet("commands.pr.context_after") {
+				opts.ContextAfter = viper.GetInt("commands.pr.context_after")
+			}
+			if viper.IsSet("commands.pr.full_file_under") {
+				opts.FullFileUnder = viper.GetInt("commands.pr.full_file_under")
+			}
+
+			prompt, err := pr.FetchCommentPrompt(cmd.Context(), client, repoOwner, repoName, prNumber, commentID, opts)
+			if err != nil {
+				return err
+			}
+			if preview {
+				fmt.Fprintln(cmd.OutOrStdout(), pr.PreviewPrompt(prompt.Content, llm.NewIOStreams().Styler()))
+				return nil
+			}
+
+			cfg := config.ResolveProviderConfig("pr")
+			cfg.ApplyFlags(providerFlag, modelFlag)
+
+			if promptTemplate == "" {
+				promptTemplate = viper.GetString("commands.pr.prompt_template")
+			}
+			if batch && dir == "" {
+				if dir, err = existingReviewDir(local, repoOwner, repoName, prNumber); err != nil {
+					return err
+				}
+			}
+
+			return pr.ApplyComment(cmd.Context(), prompt, cfg, pr.ApplyOptions{
+				Batch:          batch,
+				PromptTemplate: promptTemplate,
+				Output:         cmd.OutOrStdout(),
+				Dir:            dir,
+			})
+		},
+	}
+
+	cmd.Flags().Int64Var(&commentID, "comment-id", 0, "ID of the review comment to act on")
+	cmd.Flags().BoolVar(&batch, "batch", false, "Send the prompt in a single request and print the response instead of launching an interactive session")
+	cmd.Flags().StringVar(&promptTemplate, "prompt-template", "", "Path to a text/template file for the session prompt (default: commands.pr.prompt_template or built-in)")
+	cmd.Flags().BoolVar(&conventions, "conventions", false, "Embed the repository's CONVENTIONS.md (condensed) in the prompt")
+	cmd.Flags().BoolVar(&preview, "preview", false, "Print the prompt with its diff hunk colored instead of acting on it")
+	cmd.Flags().StringVar(&dir, "dir", "", "pr_review directory to record the --batch decision in (default: the directory pr review fetches into)")
+	cmd.Flags().BoolVar(&local, "local", false, "Record the --batch decision under the current directory instead of commands.pr.output_base")
+	_ = cmd.MarkFlagRequired("comment-id")
 	return cmd
 }
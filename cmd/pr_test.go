@@ -1,7 +1,15 @@
 package cmd
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/connorhough/smix/internal/pr"
 )
 
 func TestPRCommandStructure(t *testing.T) {
@@ -30,3 +38,127 @@ func TestPRCommandStructure(t *testing.T) {
 		t.Error("expected 'pr review' to have 'dir' flag")
 	}
 }
+
+func TestPRSummaryDryRun(t *testing.T) {
+	dir := t.TempDir()
+	decision := pr.Decision{FeedbackFile: "1_main_go_line10.md", Status: pr.StatusApplied, File: "main.go", Reasoning: "Valid fix."}
+	if err := pr.RecordDecision(dir, decision); err != nil {
+		t.Fatal(err)
+	}
+
+	root := NewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetArgs([]string{
+		"--config", filepath.Join(t.TempDir(), "config.yaml"),
+		"pr", "summary", "owner/repo", "7", "--dir", dir, "--dry-run",
+	})
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("pr summary --dry-run error = %v", err)
+	}
+	if !strings.Contains(out.String(), "1. **APPLIED** `main.go`") {
+		t.Errorf("unexpected dry-run output:\n%s", out.String())
+	}
+}
+
+func TestPROpen(t *testing.T) {
+	withIndex := t.TempDir()
+	if err := os.WriteFile(filepath.Join(withIndex, pr.IndexFile), []byte("# Index\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	withoutIndex := t.TempDir()
+
+	tests := []struct {
+		name   string
+		dir    string
+		editor string
+		want   string
+	}{
+		{name: "editor gets INDEX.md", dir: withIndex, editor: "echo", want: strings.Repeat(filepath.Join(withIndex, pr.IndexFile)+"\n", 2)},
+		{name: "editor gets directory without index", dir: withoutIndex, editor: "echo", want: strings.Repeat(withoutIndex+"\n", 2)},
+		{name: "no editor prints path", dir: withIndex, editor: "", want: filepath.Join(withIndex, pr.IndexFile) + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("EDITOR", tt.editor)
+
+			root := NewRootCmd()
+			var out bytes.Buffer
+			root.SetOut(&out)
+			root.SetArgs([]string{
+				"--config", filepath.Join(t.TempDir(), "config.yaml"),
+				"pr", "open", "--dir", tt.dir,
+			})
+
+			if err := root.Execute(); err != nil {
+				t.Fatalf("pr open error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestReviewDir(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/xdg/cache")
+
+	tests := []struct {
+		name     string
+		settings map[string]string
+		out      string
+		local    bool
+		want     string
+	}{
+		{name: "default base", want: "/xdg/cache/smix/reviews/pr_review_pr42"},
+		{name: "configured base", settings: map[string]string{"commands.pr.output_base": "/srv/reviews"}, want: "/srv/reviews/pr_review_pr42"},
+		{
+			name:     "output_dir under base",
+			settings: map[string]string{"commands.pr.output_base": "/srv/reviews", "commands.pr.output_dir": "{repo}/pr{pr}"},
+			want:     "/srv/reviews/o_r/pr42",
+		},
+		{name: "local", settings: map[string]string{"commands.pr.output_base": "/srv/reviews"}, local: true, want: "./pr_review_pr42"},
+		{name: "out used as given", settings: map[string]string{"commands.pr.output_base": "/srv/reviews"}, out: "feedback/{pr}", want: "feedback/42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			for k, v := range tt.settings {
+				viper.Set(k, v)
+			}
+
+			got, err := reviewDir(tt.out, tt.local, "o", "r", 42)
+			if err != nil {
+				t.Fatalf("reviewDir() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("reviewDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExistingReviewDir(t *testing.T) {
+	base := t.TempDir()
+	fetched := filepath.Join(base, "o_r", "pr42-2020-01-02")
+	if err := os.MkdirAll(fetched, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("commands.pr.output_base", base)
+	viper.Set("commands.pr.output_dir", "{repo}/pr{pr}-{date}")
+
+	got, err := existingReviewDir(false, "o", "r", 42)
+	if err != nil {
+		t.Fatalf("existingReviewDir() error = %v", err)
+	}
+	if got != fetched {
+		t.Errorf("existingReviewDir() = %q, want the directory fetched on an earlier day %q", got, fetched)
+	}
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/connorhough/smix/internal/providers"
+	"github.com/spf13/cobra"
+)
+
+func newProvidersCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "providers",
+		Short: "List available LLM providers and models",
+		Long: `List each supported provider, whether it is currently available, its default
+model, and the model names it is known to accept. Use these values with the
+--provider and --model flags.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			infos := providers.List(cmd.Context())
+			if jsonOutput {
+				return writeProvidersJSON(cmd.OutOrStdout(), infos)
+			}
+			return writeProvidersText(cmd.OutOrStdout(), infos)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	return cmd
+}
+
+func writeProvidersJSON(w io.Writer, infos []providers.Info) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(infos)
+}
+
+func writeProvidersText(w io.Writer, infos []providers.Info) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tAVAILABLE\tDEFAULT MODEL\tMODELS")
+	for _, info := range infos {
+		available := "yes"
+		if !info.Available {
+			available = "no"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Name, available, info.DefaultModel, strings.Join(info.Models, ", "))
+	}
+	return tw.Flush()
+}
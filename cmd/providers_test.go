@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/connorhough/smix/internal/providers"
+)
+
+func testProviderInfos() []providers.Info {
+	return []providers.Info{
+		{Name: "claude", Available: true, DefaultModel: "haiku", Models: []string{"haiku", "sonnet", "opus"}},
+		{Name: "gemini", Available: false, Error: "no key", DefaultModel: "gemini-3-flash-preview", Models: []string{"gemini-3-flash-preview", "gemini-3-pro-preview"}},
+	}
+}
+
+func TestWriteProvidersText(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeProvidersText(&out, testProviderInfos()); err != nil {
+		t.Fatalf("writeProvidersText() error = %v", err)
+	}
+
+	output := out.String()
+	for _, want := range []string{"PROVIDER", "haiku, sonnet, opus", "gemini-3-flash-preview, gemini-3-pro-preview", "yes", "no"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestWriteProvidersJSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeProvidersJSON(&out, testProviderInfos()); err != nil {
+		t.Fatalf("writeProvidersJSON() error = %v", err)
+	}
+
+	var got []providers.Info
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if len(got) != 2 || got[1].Error != "no key" || len(got[0].Models) != 3 {
+		t.Errorf("unexpected decoded infos: %+v", got)
+	}
+}
@@ -4,11 +4,16 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/paths"
+	"github.com/connorhough/smix/internal/providers"
 	"github.com/connorhough/smix/internal/version"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -20,6 +25,12 @@ var (
 	debugFlag    bool
 	providerFlag string
 	modelFlag    string
+	outputFormat string
+	errorFormat  string
+	logFormat    string
+	retriesFlag  int
+	colorFlag    string
+	quietFlag    bool
 )
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -44,22 +55,51 @@ func NewRootCmd() *cobra.Command {
 	// Add persistent flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default locations: $XDG_CONFIG_HOME/smix/config.yaml, ~/.config/smix/config.yaml, or ~/.smix.yaml)")
 	rootCmd.PersistentFlags().BoolVar(&debugFlag, "debug", false, "Enable debug output")
-	rootCmd.PersistentFlags().StringVar(&providerFlag, "provider", "", "Override LLM provider (claude, gemini)")
+	rootCmd.PersistentFlags().StringVar(&providerFlag, "provider", "", "Override LLM provider (claude, gemini, or auto for the first available)")
 	rootCmd.PersistentFlags().StringVar(&modelFlag, "model", "", "Override model name")
+	rootCmd.PersistentFlags().IntVar(&retriesFlag, "retries", llm.DefaultRetries, "Retries for transient provider API failures (0 disables retries)")
+	rootCmd.PersistentFlags().BoolVarP(&quietFlag, "quiet", "q", false, "Suppress progress messages")
+	rootCmd.PersistentFlags().StringVar(&colorFlag, "color", llm.ColorAuto, "Colorize output (auto, always, never); auto respects NO_COLOR and TTY detection")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", logFormatText, "Log output format on stderr (text, json)")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output-format", outputFormatText, "Output format for ask and do results (text, json)")
+	rootCmd.PersistentFlags().StringVar(&errorFormat, "error-format", errorFormatText, "Format of the error printed on stderr when a command fails (text, json)")
 
 	// Add subcommands
 	rootCmd.AddCommand(newConfigCmd())
 	rootCmd.AddCommand(newPRCmd())
 	rootCmd.AddCommand(NewDoCmd())
 	rootCmd.AddCommand(NewAskCmd())
+	rootCmd.AddCommand(newDoctorCmd())
+	rootCmd.AddCommand(newProvidersCmd())
+	rootCmd.AddCommand(newCleanCmd())
+	rootCmd.AddCommand(newVersionCmd())
 
 	// PersistentPreRun handles configuration initialization
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := validateOutputFormat(outputFormat); err != nil {
+			return err
+		}
+		if err := validateErrorFormat(errorFormat); err != nil {
+			return err
+		}
+		if err := llm.SetDefaultColorMode(colorFlag); err != nil {
+			return err
+		}
+
+		// Install the handler before loading config so config discovery is logged with --debug
+		if err := setupLogging(os.Stderr, ""); err != nil {
+			return err
+		}
+
 		if err := initConfig(); err != nil {
 			return err
 		}
 
-		setupLogging()
+		if err := setupLogging(os.Stderr, viper.GetString("log_level")); err != nil {
+			return err
+		}
+
+		providers.ConfigureAudit(config.ResolveAuditConfig(), commandName(cmd))
 		return nil
 	}
 
@@ -74,9 +114,9 @@ func initConfig() error {
 		return fmt.Errorf("failed to get user home directory: %w", err)
 	}
 
-	xdgConfig := os.Getenv("XDG_CONFIG_HOME")
-	if xdgConfig == "" {
-		xdgConfig = filepath.Join(home, ".config")
+	configDir, err := paths.ConfigDir()
+	if err != nil {
+		return err
 	}
 
 	// Determine config file path
@@ -87,7 +127,7 @@ func initConfig() error {
 		viper.SetConfigFile(cfgFile)
 	} else {
 		// Check for existing config files in order of preference
-		xdgPath := filepath.Join(xdgConfig, "smix", "config.yaml")
+		xdgPath := filepath.Join(configDir, "config.yaml")
 		dotPath := filepath.Join(home, ".smix.yaml")
 
 		if _, err := os.Stat(xdgPath); err == nil {
@@ -119,21 +159,82 @@ func initConfig() error {
 		return fmt.Errorf("failed to read config: %w", err)
 	}
 
+	// Expand ${VAR} references so secrets can stay in the environment
+	unset, err := config.ExpandConfigFile(configPath)
+	if err != nil {
+		return err
+	}
+	for _, name := range unset {
+		slog.Warn("config references unset environment variable", "name", name)
+	}
+
 	slog.Debug("Config loaded successfully")
 
 	return nil
 }
 
-func setupLogging() {
-	level := slog.LevelInfo
+// Formats accepted by the --log-format flag
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// setupLogging installs the default slog handler writing to w. The level is Debug when --debug
+// is set, otherwise it comes from configuredLevel (debug, info, warn, error) and defaults to Info.
+func setupLogging(w io.Writer, configuredLevel string) error {
+	handler, err := newLogHandler(w, logFormat, resolveLogLevel(debugFlag, configuredLevel))
+	if err != nil {
+		return err
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// resolveLogLevel maps the --debug flag and the log_level config value to a slog level
+func resolveLogLevel(debug bool, configured string) slog.Level {
+	if debug {
+		return slog.LevelDebug
+	}
 
-	if debugFlag || viper.GetString("log_level") == "debug" {
-		level = slog.LevelDebug
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(configured)); err != nil {
+		return slog.LevelInfo
 	}
+	return level
+}
 
-	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-		Level: level,
-	})
+// newLogHandler returns a text or JSON slog handler writing to w at the given level
+func newLogHandler(w io.Writer, format string, level slog.Level) (slog.Handler, error) {
+	opts := &slog.HandlerOptions{Level: level}
+
+	switch format {
+	case logFormatText, "":
+		return slog.NewTextHandler(w, opts), nil
+	case logFormatJSON:
+		return slog.NewJSONHandler(w, opts), nil
+	default:
+		return nil, fmt.Errorf("invalid --log-format %q (expected %s or %s)", format, logFormatText, logFormatJSON)
+	}
+}
 
-	slog.SetDefault(slog.New(handler))
+// applyRetriesFlag sets cfg.Retries when --retries was passed explicitly
+func applyRetriesFlag(cmd *cobra.Command, cfg *config.ProviderConfig) {
+	if cmd.Flags().Changed("retries") {
+		retries := retriesFlag
+		cfg.Retries = &retries
+	}
+}
+
+// progressWriter returns where commands should write progress messages: stderr, or nowhere with --quiet
+func progressWriter(cmd *cobra.Command) io.Writer {
+	if quietFlag {
+		return io.Discard
+	}
+	return cmd.ErrOrStderr()
+}
+
+// commandName returns the command path without the root command name (e.g. "pr review")
+func commandName(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
 }
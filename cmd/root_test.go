@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestResolveLogLevel(t *testing.T) {
+	tests := []struct {
+		name       string
+		debug      bool
+		configured string
+		want       slog.Level
+	}{
+		{"default", false, "", slog.LevelInfo},
+		{"debug flag", true, "", slog.LevelDebug},
+		{"debug flag overrides config", true, "error", slog.LevelDebug},
+		{"config debug", false, "debug", slog.LevelDebug},
+		{"config warn", false, "warn", slog.LevelWarn},
+		{"config error", false, "ERROR", slog.LevelError},
+		{"invalid config", false, "verbose", slog.LevelInfo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveLogLevel(tt.debug, tt.configured); got != tt.want {
+				t.Errorf("resolveLogLevel(%v, %q) = %s, want %s", tt.debug, tt.configured, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLogHandler(t *testing.T) {
+	t.Run("info level drops debug records", func(t *testing.T) {
+		var buf bytes.Buffer
+		handler, err := newLogHandler(&buf, logFormatText, slog.LevelInfo)
+		if err != nil {
+			t.Fatalf("newLogHandler() error = %v", err)
+		}
+
+		logger := slog.New(handler)
+		logger.Debug("hidden detail")
+		logger.Info("visible message", "provider", "claude")
+
+		out := buf.String()
+		if strings.Contains(out, "hidden detail") {
+			t.Errorf("debug record should be filtered at info level:\n%s", out)
+		}
+		if !strings.Contains(out, "visible message") || !strings.Contains(out, "provider=claude") {
+			t.Errorf("expected info record in output:\n%s", out)
+		}
+	})
+
+	t.Run("debug level keeps debug records", func(t *testing.T) {
+		var buf bytes.Buffer
+		handler, err := newLogHandler(&buf, logFormatText, slog.LevelDebug)
+		if err != nil {
+			t.Fatalf("newLogHandler() error = %v", err)
+		}
+
+		slog.New(handler).Debug("resolved config", "model", "haiku")
+
+		if !strings.Contains(buf.String(), "level=DEBUG") || !strings.Contains(buf.String(), "model=haiku") {
+			t.Errorf("expected debug record in output:\n%s", buf.String())
+		}
+	})
+
+	t.Run("json format", func(t *testing.T) {
+		var buf bytes.Buffer
+		handler, err := newLogHandler(&buf, logFormatJSON, slog.LevelInfo)
+		if err != nil {
+			t.Fatalf("newLogHandler() error = %v", err)
+		}
+
+		slog.New(handler).Warn("slow response", "elapsed", "3s")
+
+		var record map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+			t.Fatalf("log line is not valid JSON: %v\n%s", err, buf.String())
+		}
+		if record["level"] != "WARN" || record["msg"] != "slow response" || record["elapsed"] != "3s" {
+			t.Errorf("unexpected record: %v", record)
+		}
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		if _, err := newLogHandler(&bytes.Buffer{}, "xml", slog.LevelInfo); err == nil {
+			t.Error("expected error for unsupported log format")
+		}
+	})
+}
+
+func TestProgressWriter(t *testing.T) {
+	t.Cleanup(func() { quietFlag = false })
+
+	cmd := NewRootCmd()
+	var errOut bytes.Buffer
+	cmd.SetErr(&errOut)
+
+	quietFlag = false
+	fmt.Fprint(progressWriter(cmd), "Fetched 3 review comments")
+	if errOut.String() != "Fetched 3 review comments" {
+		t.Errorf("expected progress on stderr, got %q", errOut.String())
+	}
+
+	errOut.Reset()
+	quietFlag = true
+	fmt.Fprint(progressWriter(cmd), "Fetched 3 review comments")
+	if errOut.Len() != 0 {
+		t.Errorf("expected no progress with --quiet, got %q", errOut.String())
+	}
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/connorhough/smix/internal/llm"
+)
+
+// spinnerFrames are drawn in turn while waiting for a provider response
+var spinnerFrames = []string{"|", "/", "-", `\`}
+
+const spinnerInterval = 100 * time.Millisecond
+
+// withSpinner runs fn while animating a spinner with label on streams.ErrOut.
+// The spinner is skipped when stdout or stderr is not a terminal or --quiet is set,
+// and its line is erased before withSpinner returns so the result prints cleanly.
+func withSpinner(streams *llm.IOStreams, label string, fn func() error) error {
+	if quietFlag || !streams.StdoutIsTerminal() || !streams.StderrIsTerminal() {
+		return fn()
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(spinnerInterval)
+		defer ticker.Stop()
+
+		for i := 0; ; i++ {
+			fmt.Fprintf(streams.ErrOut, "\r%s %s", spinnerFrames[i%len(spinnerFrames)], label)
+			select {
+			case <-done:
+				// Return to the start of the line and clear it
+				fmt.Fprint(streams.ErrOut, "\r\033[K")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	err := fn()
+	close(done)
+	wg.Wait()
+	return err
+}
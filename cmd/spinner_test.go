@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/connorhough/smix/internal/llm"
+)
+
+func TestWithSpinner(t *testing.T) {
+	tests := []struct {
+		name        string
+		streams     func() (*llm.IOStreams, *bytes.Buffer)
+		quiet       bool
+		wantSpinner bool
+	}{
+		{name: "non-interactive streams", streams: nonTerminalStreams},
+		{name: "terminal", streams: terminalStreams, wantSpinner: true},
+		{name: "terminal with quiet", streams: terminalStreams, quiet: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origQuiet := quietFlag
+			quietFlag = tt.quiet
+			t.Cleanup(func() { quietFlag = origQuiet })
+
+			streams, out := tt.streams()
+			var answer string
+			err := withSpinner(streams, "Thinking...", func() error {
+				time.Sleep(2 * spinnerInterval)
+				answer = "42"
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("withSpinner() error = %v", err)
+			}
+			fmt.Fprintln(streams.Out, answer)
+
+			got := out.String()
+			if !strings.HasSuffix(got, "42\n") {
+				t.Errorf("output = %q, want result printed last", got)
+			}
+			if hasSpinner := strings.Contains(got, "Thinking..."); hasSpinner != tt.wantSpinner {
+				t.Errorf("spinner drawn = %v, want %v (output %q)", hasSpinner, tt.wantSpinner, got)
+			}
+			if tt.wantSpinner && !strings.HasSuffix(got, "\r\033[K42\n") {
+				t.Errorf("output = %q, want spinner line cleared before the result", got)
+			}
+		})
+	}
+}
+
+func TestWithSpinner_ReturnsError(t *testing.T) {
+	streams, _ := terminalStreams()
+	wantErr := errors.New("provider failed")
+	if err := withSpinner(streams, "Thinking...", func() error { return wantErr }); !errors.Is(err, wantErr) {
+		t.Errorf("withSpinner() error = %v, want %v", err, wantErr)
+	}
+}
+
+// nonTerminalStreams returns piped streams; Out and ErrOut share the returned buffer
+func nonTerminalStreams() (*llm.IOStreams, *bytes.Buffer) {
+	streams, _, out := llm.TestIOStreamsNonInteractive()
+	return streams, out
+}
+
+// terminalStreams returns TTY streams; Out and ErrOut share the returned buffer
+func terminalStreams() (*llm.IOStreams, *bytes.Buffer) {
+	streams, _, out := llm.TestIOStreamsTerminal()
+	return streams, out
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/connorhough/smix/internal/version"
+	"github.com/spf13/cobra"
+)
+
+func newVersionCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print version and build information",
+		Long: `Print the smix version, git commit, build date, and Go version.
+Use --json (or --output-format json) for machine-readable output.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := version.Info()
+			if jsonOutput || outputFormat == outputFormatJSON {
+				return writeVersionJSON(cmd.OutOrStdout(), info)
+			}
+			return writeVersionText(cmd.OutOrStdout(), info)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	return cmd
+}
+
+func writeVersionJSON(w io.Writer, info version.BuildInfo) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(info)
+}
+
+func writeVersionText(w io.Writer, info version.BuildInfo) error {
+	_, err := fmt.Fprintf(w, "smix %s\ncommit: %s\nbuilt: %s\ngo: %s\n", info.Version, info.GitCommit, info.BuildDate, info.GoVersion)
+	return err
+}
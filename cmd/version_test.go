@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/connorhough/smix/internal/version"
+)
+
+func testBuildInfo() version.BuildInfo {
+	return version.BuildInfo{Version: "v1.2.3", GitCommit: "abc1234", BuildDate: "2026-01-02T03:04:05Z", GoVersion: "go1.99"}
+}
+
+func TestWriteVersionText(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeVersionText(&out, testBuildInfo()); err != nil {
+		t.Fatalf("writeVersionText() error = %v", err)
+	}
+
+	output := out.String()
+	for _, want := range []string{"smix v1.2.3", "commit: abc1234", "built: 2026-01-02T03:04:05Z", "go: go1.99"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestWriteVersionJSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeVersionJSON(&out, testBuildInfo()); err != nil {
+		t.Fatalf("writeVersionJSON() error = %v", err)
+	}
+
+	var got version.BuildInfo
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if got != testBuildInfo() {
+		t.Errorf("decoded = %+v, want %+v", got, testBuildInfo())
+	}
+}
@@ -5,13 +5,17 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
+	"strings"
+	"text/template"
 
 	"github.com/connorhough/smix/internal/config"
 	"github.com/connorhough/smix/internal/llm"
 	"github.com/connorhough/smix/internal/providers"
 )
 
-const promptTemplate = `You are a helpful technical assistant that provides concise, accurate answers to user questions.
+// defaultPromptTemplate is the built-in prompt. Custom templates use the same {{.Question}} placeholder.
+const defaultPromptTemplate = `You are a helpful technical assistant that provides concise, accurate answers to user questions.
 
 Requirements:
 1. Provide clear, direct answers without unnecessary elaboration
@@ -28,22 +32,241 @@ Output: FastAPI is a modern Python web framework for building APIs. It's known f
 User: "does the mv command overwrite duplicate files"
 Output: Yes, mv overwrites files by default without prompting. If a file with the same name exists in the destination, it will be replaced. Use mv -i for interactive mode to get a confirmation prompt before overwriting, or mv -n to prevent overwriting entirely.
 
-User's Question: %s`
+User's Question: {{.Question}}`
 
-// Answer processes a user's question and returns a concise answer
-func Answer(ctx context.Context, question string, cfg *config.ProviderConfig) (string, error) {
+var builtinPromptTemplate = template.Must(template.New("builtin").Option("missingkey=error").Parse(defaultPromptTemplate))
+
+// PromptData is the data available to ask prompt templates
+type PromptData struct {
+	// Question is the user's question
+	Question string
+}
+
+// Options configures Answer
+type Options struct {
+	// PromptTemplate is the path to a text/template file used instead of the built-in prompt
+	PromptTemplate string
+	// Context is file content the question refers to, appended to the prompt (see ReadContext)
+	Context string
+	// Truncate cuts Context so the prompt fits the model's context window instead of only warning
+	Truncate bool
+}
+
+// LoadPromptTemplate parses the prompt template at path, or returns the built-in template when path is empty.
+// Templates that fail to parse, reference unknown fields, or never include {{.Question}} are rejected.
+func LoadPromptTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return builtinPromptTemplate, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompt template: %w", err)
+	}
+
+	tmpl, err := template.New(path).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt template %s: %w", path, err)
+	}
+
+	// Render a sentinel question so a template that drops the question fails now rather than silently
+	const sentinel = "smix-question-placeholder"
+	rendered, err := renderPrompt(tmpl, sentinel)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt template %s: %w", path, err)
+	}
+	if !strings.Contains(rendered, sentinel) {
+		return nil, fmt.Errorf("invalid prompt template %s: must include {{.Question}}", path)
+	}
+
+	return tmpl, nil
+}
+
+// renderPrompt executes tmpl with question
+func renderPrompt(tmpl *template.Template, question string) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, PromptData{Question: question}); err != nil {
+		return "", fmt.Errorf("failed to render prompt template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// BuildPrompt returns the exact prompt Answer would send for question, without resolving a provider
+func BuildPrompt(question string, opts Options) (string, error) {
+	tmpl, err := LoadPromptTemplate(opts.PromptTemplate)
+	if err != nil {
+		return "", err
+	}
+	return buildPrompt(tmpl, question, opts.Context)
+}
+
+// buildPrompt renders tmpl for question and appends any context
+func buildPrompt(tmpl *template.Template, question, questionContext string) (string, error) {
+	prompt, err := renderPrompt(tmpl, question)
+	if err != nil {
+		return "", err
+	}
+	return appendContext(prompt, questionContext), nil
+}
+
+// MaxAnswerCount bounds how many answers AnswerN samples for one question
+const MaxAnswerCount = 8
+
+// Source identifies the provider and model that answered a question
+type Source struct {
+	// Provider is the resolved provider name, never AutoProvider
+	Provider string
+	// Model is the model the request used, the provider's default when none is configured
+	Model string
+}
+
+// sourceOf returns the Source of requests sent to provider with cfg
+func sourceOf(provider llm.Provider, cfg *config.ProviderConfig) Source {
+	return Source{Provider: provider.Name(), Model: effectiveModel(provider, cfg)}
+}
+
+// effectiveModel returns cfg.Model, or the provider's default model when none is configured
+func effectiveModel(provider llm.Provider, cfg *config.ProviderConfig) string {
+	if cfg.Model != "" {
+		return cfg.Model
+	}
+	return provider.DefaultModel()
+}
+
+// Answer processes a user's question and returns a concise answer along with the provider
+// and model that produced it
+func Answer(ctx context.Context, question string, cfg *config.ProviderConfig, opts Options) (string, Source, error) {
+	provider, tmpl, err := prepare(ctx, cfg, opts)
+	if err != nil {
+		return "", Source{}, err
+	}
+
+	questionContext, err := fitContext(provider, question, cfg, tmpl, opts)
+	if err != nil {
+		return "", Source{}, err
+	}
+	response, err := answer(ctx, provider, question, cfg, tmpl, questionContext)
+	if err != nil {
+		return "", Source{}, err
+	}
+	return response, sourceOf(provider, cfg), nil
+}
+
+// AnswerN samples n candidate answers to a question, using the provider's native
+// multi-candidate support when it has one and sequential requests otherwise
+func AnswerN(ctx context.Context, question string, cfg *config.ProviderConfig, opts Options, n int) ([]string, Source, error) {
+	if n < 1 || n > MaxAnswerCount {
+		return nil, Source{}, fmt.Errorf("answer count must be between 1 and %d, got %d", MaxAnswerCount, n)
+	}
+
+	provider, tmpl, err := prepare(ctx, cfg, opts)
+	if err != nil {
+		return nil, Source{}, err
+	}
+
+	questionContext, err := fitContext(provider, question, cfg, tmpl, opts)
+	if err != nil {
+		return nil, Source{}, err
+	}
+	answers, err := answerN(ctx, provider, question, cfg, tmpl, questionContext, n)
+	if err != nil {
+		return nil, Source{}, err
+	}
+	return answers, sourceOf(provider, cfg), nil
+}
+
+// fitContext warns when the prompt for question is estimated to exceed the model's context
+// window. With opts.Truncate the context is cut to fit instead, and the cut context returned.
+func fitContext(provider llm.Provider, question string, cfg *config.ProviderConfig, tmpl *template.Template, opts Options) (string, error) {
+	prompt, err := buildPrompt(tmpl, question, opts.Context)
+	if err != nil {
+		return "", err
+	}
+	model := effectiveModel(provider, cfg)
+
+	tokens, limit, fits := llm.CheckPromptSize(prompt, model)
+	if fits {
+		return opts.Context, nil
+	}
+	if !opts.Truncate || opts.Context == "" {
+		slog.Warn("prompt may exceed the model's context window (pass --truncate to cut the context to fit)", "estimated_tokens", tokens, "limit", limit, "model", model)
+		return opts.Context, nil
+	}
+
+	questionContext, _ := llm.TruncateToTokens(opts.Context, llm.EstimateTokens(opts.Context)-(tokens-limit))
+	slog.Warn("context truncated to fit the model's context window", "estimated_tokens", tokens, "limit", limit, "model", model)
+	return questionContext, nil
+}
+
+// prepare loads the prompt template and resolves the configured provider
+func prepare(ctx context.Context, cfg *config.ProviderConfig, opts Options) (llm.Provider, *template.Template, error) {
 	slog.Debug("ask command config", "provider", cfg.Provider, "model", cfg.Model)
 
+	tmpl, err := LoadPromptTemplate(opts.PromptTemplate)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Get provider from factory
-	provider, err := providers.GetProvider(ctx, cfg.Provider)
+	provider, err := providers.GetProviderWithFallback(ctx, cfg, os.Stderr)
 	if err != nil {
-		return "", fmt.Errorf("failed to get provider: %w", err)
+		return nil, nil, fmt.Errorf("failed to get provider: %w", err)
+	}
+	if err := providers.CheckModel(provider, cfg); err != nil {
+		return nil, nil, err
 	}
 
 	slog.Debug("resolved provider", "name", provider.Name())
 
+	return provider, tmpl, nil
+}
+
+// answer builds the prompt and generates a response from an already resolved provider
+func answer(ctx context.Context, provider llm.Provider, question string, cfg *config.ProviderConfig, tmpl *template.Template, questionContext string) (string, error) {
+	prompt, opts, err := buildRequest(provider, question, cfg, tmpl, questionContext)
+	if err != nil {
+		return "", err
+	}
+
+	stats := &llm.RetryStats{}
+	response, err := provider.Generate(llm.WithRetryStats(ctx, stats), prompt, opts...)
+	if err != nil {
+		return "", err
+	}
+	logRetries(stats)
+	return response, nil
+}
+
+// answerN builds the prompt and samples n responses from an already resolved provider
+func answerN(ctx context.Context, provider llm.Provider, question string, cfg *config.ProviderConfig, tmpl *template.Template, questionContext string, n int) ([]string, error) {
+	prompt, opts, err := buildRequest(provider, question, cfg, tmpl, questionContext)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &llm.RetryStats{}
+	answers, err := llm.GenerateN(llm.WithRetryStats(ctx, stats), provider, prompt, n, opts...)
+	if err != nil {
+		return nil, err
+	}
+	logRetries(stats)
+	return answers, nil
+}
+
+// logRetries logs at debug level how many retries a successful request needed
+func logRetries(stats *llm.RetryStats) {
+	if stats.Retries > 0 {
+		slog.Debug(stats.Summary(), "attempts", stats.Attempts)
+	}
+}
+
+// buildRequest renders the prompt and generation options for a question
+func buildRequest(provider llm.Provider, question string, cfg *config.ProviderConfig, tmpl *template.Template, questionContext string) (string, []llm.Option, error) {
 	// Build prompt
-	prompt := fmt.Sprintf(promptTemplate, question)
+	prompt, err := buildPrompt(tmpl, question, questionContext)
+	if err != nil {
+		return "", nil, err
+	}
 	slog.Debug("prompt constructed", "length", len(prompt))
 
 	// Generate response
@@ -54,7 +277,25 @@ func Answer(ctx context.Context, question string, cfg *config.ProviderConfig) (s
 	} else {
 		opts = append(opts, llm.WithModel(resolvedModel))
 	}
+	if cfg.Retries != nil {
+		opts = append(opts, llm.WithMaxRetries(*cfg.Retries))
+	}
 	slog.Debug("resolved model", "model", resolvedModel)
 
-	return provider.Generate(ctx, prompt, opts...)
+	return prompt, opts, nil
+}
+
+// answerDivider separates answers printed by FormatAnswers
+const answerDivider = "---"
+
+// FormatAnswers numbers each answer and separates them with a divider
+func FormatAnswers(answers []string) string {
+	var b strings.Builder
+	for i, a := range answers {
+		if i > 0 {
+			b.WriteString("\n\n" + answerDivider + "\n\n")
+		}
+		fmt.Fprintf(&b, "%d. %s", i+1, strings.TrimSpace(a))
+	}
+	return b.String()
 }
@@ -0,0 +1,198 @@
+package ask
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/llm/llmtest"
+)
+
+func TestLoadPromptTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "named placeholder", content: "Answer at length: {{.Question}}"},
+		{name: "missing question", content: "Answer something", wantErr: "must include {{.Question}}"},
+		{name: "unknown field", content: "{{.Question}} {{.Tone}}", wantErr: "invalid prompt template"},
+		{name: "parse error", content: "{{.Question", wantErr: "invalid prompt template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "ask.tmpl")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadPromptTemplate(path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := LoadPromptTemplate(filepath.Join(t.TempDir(), "missing.tmpl")); err == nil {
+			t.Error("expected error for missing file")
+		}
+	})
+}
+
+func TestAnswer_PromptTemplate(t *testing.T) {
+	t.Run("built-in template", func(t *testing.T) {
+		tmpl, err := LoadPromptTemplate("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fake := &llmtest.FakeProvider{Responses: []string{"answer"}}
+
+		if _, err := answer(context.Background(), fake, "what is FastAPI", &config.ProviderConfig{}, tmpl, ""); err != nil {
+			t.Fatalf("answer() error = %v", err)
+		}
+		prompt := fake.Prompts()[0]
+		if !strings.HasSuffix(prompt, "User's Question: what is FastAPI") || !strings.HasPrefix(prompt, "You are a helpful technical assistant") {
+			t.Errorf("built-in prompt = %q", prompt)
+		}
+	})
+
+	t.Run("custom template", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ask.tmpl")
+		if err := os.WriteFile(path, []byte("Explain in detail: {{.Question}}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		tmpl, err := LoadPromptTemplate(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fake := &llmtest.FakeProvider{Responses: []string{"answer"}}
+
+		got, err := answer(context.Background(), fake, "100% of {{.X}}", &config.ProviderConfig{}, tmpl, "")
+		if err != nil {
+			t.Fatalf("answer() error = %v", err)
+		}
+		if got != "answer" {
+			t.Errorf("answer() = %q, want %q", got, "answer")
+		}
+		if want := "Explain in detail: 100% of {{.X}}"; fake.Prompts()[0] != want {
+			t.Errorf("prompt = %q, want %q", fake.Prompts()[0], want)
+		}
+	})
+}
+
+func TestBuildPrompt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ask.tmpl")
+	if err := os.WriteFile(path, []byte("Answer tersely: {{.Question}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{name: "built-in", want: []string{"You are a helpful technical assistant", "User's Question: what is FastAPI"}},
+		{name: "custom template", opts: Options{PromptTemplate: path}, want: []string{"Answer tersely: what is FastAPI"}},
+		{name: "with context", opts: Options{Context: "package main"}, want: []string{"User's Question: what is FastAPI", "package main"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildPrompt("what is FastAPI", tt.opts)
+			if err != nil {
+				t.Fatalf("BuildPrompt() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("BuildPrompt() missing %q:\n%s", want, got)
+				}
+			}
+
+			// The prompt-only output must match what a provider would receive
+			tmpl, err := LoadPromptTemplate(tt.opts.PromptTemplate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fake := &llmtest.FakeProvider{Responses: []string{"answer"}}
+			if _, err := answer(context.Background(), fake, "what is FastAPI", &config.ProviderConfig{}, tmpl, tt.opts.Context); err != nil {
+				t.Fatal(err)
+			}
+			if fake.Prompts()[0] != got {
+				t.Errorf("BuildPrompt() differs from the sent prompt:\n%s\n---\n%s", got, fake.Prompts()[0])
+			}
+		})
+	}
+}
+
+// candidateProvider implements llm.CandidateGenerator and records the requested counts
+type candidateProvider struct {
+	llmtest.FakeProvider
+	counts []int
+}
+
+func (c *candidateProvider) GenerateCandidates(ctx context.Context, prompt string, n int, opts ...llm.Option) ([]string, error) {
+	c.counts = append(c.counts, n)
+	answers := make([]string, n)
+	for i := range answers {
+		answers[i] = fmt.Sprintf("candidate %d", i+1)
+	}
+	return answers, nil
+}
+
+func TestAnswerN(t *testing.T) {
+	t.Run("providers without candidate support are called n times", func(t *testing.T) {
+		fake := &llmtest.FakeProvider{Responses: []string{"one", "two", "three"}}
+
+		got, err := answerN(context.Background(), fake.Basic(), "name ideas", &config.ProviderConfig{}, builtinPromptTemplate, "", 3)
+		if err != nil {
+			t.Fatalf("answerN() error = %v", err)
+		}
+		if strings.Join(got, "|") != "one|two|three" {
+			t.Errorf("answerN() = %q, want three answers in order", got)
+		}
+		if len(fake.Prompts()) != 3 {
+			t.Errorf("got %d Generate calls, want 3", len(fake.Prompts()))
+		}
+	})
+
+	t.Run("candidate providers are asked once", func(t *testing.T) {
+		provider := &candidateProvider{}
+
+		got, err := answerN(context.Background(), provider, "name ideas", &config.ProviderConfig{}, builtinPromptTemplate, "", 2)
+		if err != nil {
+			t.Fatalf("answerN() error = %v", err)
+		}
+		if len(got) != 2 || len(provider.counts) != 1 || provider.counts[0] != 2 || len(provider.Prompts()) != 0 {
+			t.Errorf("answers = %q, counts = %v, Generate calls = %d", got, provider.counts, len(provider.Prompts()))
+		}
+	})
+
+	t.Run("count out of range", func(t *testing.T) {
+		for _, n := range []int{0, -1, MaxAnswerCount + 1} {
+			if _, _, err := AnswerN(context.Background(), "q", &config.ProviderConfig{}, Options{}, n); err == nil {
+				t.Errorf("AnswerN(n=%d) expected an error", n)
+			}
+		}
+	})
+}
+
+func TestFormatAnswers(t *testing.T) {
+	got := FormatAnswers([]string{"First idea.\n", "Second idea."})
+	want := "1. First idea.\n\n---\n\n2. Second idea."
+	if got != want {
+		t.Errorf("FormatAnswers() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,143 @@
+package ask
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
+)
+
+// DefaultBatchConcurrency is the number of batch questions answered in parallel
+const DefaultBatchConcurrency = 4
+
+// BatchResult is the outcome of one batch question
+type BatchResult struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ReadBatch reads one question per line from r, skipping blank lines
+func ReadBatch(r io.Reader) ([]string, error) {
+	var questions []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			questions = append(questions, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read batch questions: %w", err)
+	}
+	return questions, nil
+}
+
+// AnswerBatch answers questions concurrently with at most concurrency requests in flight.
+// Results are returned in input order; a failed question records its error and does not stop the rest.
+// The returned error is non-nil only when the batch could not start or some questions failed.
+func AnswerBatch(ctx context.Context, questions []string, cfg *config.ProviderConfig, opts Options, concurrency int) ([]BatchResult, error) {
+	provider, tmpl, err := prepare(ctx, cfg, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every question shares the context, so fit it against the longest one
+	longest := ""
+	for _, q := range questions {
+		if len(q) > len(longest) {
+			longest = q
+		}
+	}
+	questionContext, err := fitContext(provider, longest, cfg, tmpl, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := answerBatch(ctx, provider, questions, cfg, tmpl, questionContext, concurrency)
+	return results, batchError(results)
+}
+
+// answerBatch runs the worker pool against an already resolved provider
+func answerBatch(ctx context.Context, provider llm.Provider, questions []string, cfg *config.ProviderConfig, tmpl *template.Template, questionContext string, concurrency int) []BatchResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]BatchResult, len(questions))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(questions)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = BatchResult{Question: questions[i]}
+				if err := ctx.Err(); err != nil {
+					results[i].Error = err.Error()
+					continue
+				}
+				answer, err := answer(ctx, provider, questions[i], cfg, tmpl, questionContext)
+				if err != nil {
+					results[i].Error = err.Error()
+					continue
+				}
+				results[i].Answer = strings.TrimSpace(answer)
+			}
+		}()
+	}
+
+	for i := range questions {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// batchError summarizes failed questions, or returns nil when all succeeded
+func batchError(results []BatchResult) error {
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d questions failed", failed, len(results))
+}
+
+// WriteBatch prints results as a numbered list, with failed questions showing their error
+func WriteBatch(w io.Writer, results []BatchResult) error {
+	for i, r := range results {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		body := r.Answer
+		if r.Error != "" {
+			body = "error: " + r.Error
+		}
+		if _, err := fmt.Fprintf(w, "%d. %s\n%s\n", i+1, r.Question, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteBatchJSON prints results as an indented JSON array
+func WriteBatchJSON(w io.Writer, results []BatchResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
@@ -0,0 +1,136 @@
+package ask
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
+)
+
+// echoProvider answers each prompt with its own text, finishing earlier prompts last so
+// that completion order differs from input order, and fails prompts containing "fail"
+type echoProvider struct {
+	inFlight, maxInFlight atomic.Int32
+}
+
+func (p *echoProvider) Generate(ctx context.Context, prompt string, opts ...llm.Option) (string, error) {
+	n := p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+	for {
+		max := p.maxInFlight.Load()
+		if n <= max || p.maxInFlight.CompareAndSwap(max, n) {
+			break
+		}
+	}
+
+	if strings.Contains(prompt, "fail") {
+		return "", errors.New("provider failed")
+	}
+	time.Sleep(time.Duration(10-len(prompt)%10) * time.Millisecond)
+	return "answer: " + prompt + "\n", nil
+}
+
+func (p *echoProvider) ValidateModel(model string) error { return nil }
+func (p *echoProvider) DefaultModel() string             { return "echo-model" }
+func (p *echoProvider) Name() string                     { return "echo" }
+
+var questionOnly = template.Must(template.New("question").Parse("{{.Question}}"))
+
+func TestAnswerBatch_PreservesOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "questions.txt")
+	content := "what is go\n\nwhat is a goroutine\nplease fail\n  what is a channel  \nwhat is select\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	questions, err := ReadBatch(f)
+	if err != nil {
+		t.Fatalf("ReadBatch() error = %v", err)
+	}
+	want := []string{"what is go", "what is a goroutine", "please fail", "what is a channel", "what is select"}
+	if strings.Join(questions, "|") != strings.Join(want, "|") {
+		t.Fatalf("ReadBatch() = %q, want %q", questions, want)
+	}
+
+	provider := &echoProvider{}
+	results := answerBatch(context.Background(), provider, questions, &config.ProviderConfig{}, questionOnly, "", 2)
+
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i, r := range results {
+		if r.Question != want[i] {
+			t.Errorf("results[%d].Question = %q, want %q", i, r.Question, want[i])
+		}
+		if want[i] == "please fail" {
+			if r.Error == "" || r.Answer != "" {
+				t.Errorf("results[%d] = %+v, want an error", i, r)
+			}
+			continue
+		}
+		if r.Answer != "answer: "+want[i] || r.Error != "" {
+			t.Errorf("results[%d] = %+v, want answer for %q", i, r, want[i])
+		}
+	}
+	if max := provider.maxInFlight.Load(); max > 2 {
+		t.Errorf("max concurrent requests = %d, want at most 2", max)
+	}
+	if err := batchError(results); err == nil || err.Error() != "1 of 5 questions failed" {
+		t.Errorf("batchError() = %v, want 1 of 5 questions failed", err)
+	}
+}
+
+func TestAnswerBatch_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := answerBatch(ctx, &echoProvider{}, []string{"a", "b"}, &config.ProviderConfig{}, questionOnly, "", 4)
+	for i, r := range results {
+		if r.Error != context.Canceled.Error() {
+			t.Errorf("results[%d].Error = %q, want %q", i, r.Error, context.Canceled.Error())
+		}
+	}
+}
+
+func TestWriteBatch(t *testing.T) {
+	results := []BatchResult{
+		{Question: "what is go", Answer: "A language."},
+		{Question: "please fail", Error: "provider failed"},
+	}
+
+	var text bytes.Buffer
+	if err := WriteBatch(&text, results); err != nil {
+		t.Fatalf("WriteBatch() error = %v", err)
+	}
+	want := "1. what is go\nA language.\n\n2. please fail\nerror: provider failed\n"
+	if text.String() != want {
+		t.Errorf("WriteBatch() = %q, want %q", text.String(), want)
+	}
+
+	var out bytes.Buffer
+	if err := WriteBatchJSON(&out, results); err != nil {
+		t.Fatalf("WriteBatchJSON() error = %v", err)
+	}
+	var decoded []BatchResult
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if len(decoded) != 2 || decoded[0] != results[0] || decoded[1] != results[1] {
+		t.Errorf("decoded = %+v, want %+v", decoded, results)
+	}
+}
@@ -0,0 +1,96 @@
+package ask
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/providers"
+)
+
+// chatExitCommand ends a chat session
+const chatExitCommand = "/exit"
+
+const chatPromptTemplate = `You are a helpful technical assistant having a conversation with a user.
+
+Requirements:
+1. Provide clear, direct answers without unnecessary elaboration
+2. Use plain text formatting (no markdown, code blocks, or special formatting)
+3. Keep responses brief but informative (2-4 sentences typically)
+4. Use the earlier conversation for context when answering follow-up questions
+
+Conversation so far:
+%s
+Assistant:`
+
+const interactiveChatPrompt = `You are a helpful technical assistant. Answer the user's technical questions concisely and accurately, using plain text. Keep answers brief (2-4 sentences typically) and use earlier messages for context on follow-up questions.`
+
+// Chat starts a multi-turn conversation using the configured provider.
+// Providers that support interactive mode take over the terminal when streams are interactive;
+// otherwise the transcript is kept in memory and sent with each Generate call.
+func Chat(ctx context.Context, streams *llm.IOStreams, cfg *config.ProviderConfig) error {
+	provider, err := providers.GetProviderWithFallback(ctx, cfg, streams.ErrOut)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+	if err := providers.CheckModel(provider, cfg); err != nil {
+		return err
+	}
+
+	var opts []llm.Option
+	if cfg.Model != "" {
+		opts = append(opts, llm.WithModel(cfg.Model))
+	}
+	if cfg.Retries != nil {
+		opts = append(opts, llm.WithMaxRetries(*cfg.Retries))
+	}
+
+	return runChat(ctx, streams, provider, opts...)
+}
+
+// runChat drives a chat session with an already resolved provider
+func runChat(ctx context.Context, streams *llm.IOStreams, provider llm.Provider, opts ...llm.Option) error {
+	if interactive, ok := provider.(llm.InteractiveProvider); ok && streams.IsInteractive() {
+		slog.Debug("starting interactive chat", "provider", provider.Name())
+		return interactive.RunInteractive(ctx, streams, interactiveChatPrompt, opts...)
+	}
+
+	slog.Debug("starting transcript chat", "provider", provider.Name())
+
+	var transcript strings.Builder
+	scanner := bufio.NewScanner(streams.In)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		fmt.Fprint(streams.Out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(streams.Out)
+			return scanner.Err()
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == chatExitCommand {
+			return nil
+		}
+
+		fmt.Fprintf(&transcript, "User: %s\n", line)
+
+		answer, err := provider.Generate(ctx, fmt.Sprintf(chatPromptTemplate, transcript.String()), opts...)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(&transcript, "Assistant: %s\n", answer)
+		fmt.Fprintln(streams.Out, answer)
+	}
+}
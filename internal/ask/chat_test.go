@@ -0,0 +1,126 @@
+package ask
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/connorhough/smix/internal/llm"
+)
+
+// mockProvider records prompts and returns numbered answers
+type mockProvider struct {
+	prompts []string
+	err     error
+}
+
+func (m *mockProvider) Generate(ctx context.Context, prompt string, opts ...llm.Option) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	m.prompts = append(m.prompts, prompt)
+	return fmt.Sprintf("answer %d", len(m.prompts)), nil
+}
+
+func (m *mockProvider) ValidateModel(model string) error { return nil }
+func (m *mockProvider) DefaultModel() string             { return "mock-model" }
+func (m *mockProvider) Name() string                     { return "mock" }
+
+// mockInteractiveProvider also implements llm.InteractiveProvider
+type mockInteractiveProvider struct {
+	mockProvider
+	interactiveCalls int
+}
+
+func (m *mockInteractiveProvider) RunInteractive(ctx context.Context, streams *llm.IOStreams, prompt string, opts ...llm.Option) error {
+	m.interactiveCalls++
+	return nil
+}
+
+func TestRunChat_TranscriptLoop(t *testing.T) {
+	mock := &mockProvider{}
+	streams, in, out := llm.TestIOStreams()
+	in.WriteString("what is TCP\n\nand UDP?\n/exit\nnever sent\n")
+
+	if err := runChat(context.Background(), streams, mock); err != nil {
+		t.Fatalf("runChat() error = %v", err)
+	}
+
+	if len(mock.prompts) != 2 {
+		t.Fatalf("expected 2 Generate calls, got %d", len(mock.prompts))
+	}
+
+	if !strings.Contains(mock.prompts[0], "User: what is TCP") {
+		t.Errorf("first prompt missing question: %q", mock.prompts[0])
+	}
+
+	// Second prompt must carry the full transcript so far
+	second := mock.prompts[1]
+	for _, want := range []string{"User: what is TCP", "Assistant: answer 1", "User: and UDP?"} {
+		if !strings.Contains(second, want) {
+			t.Errorf("second prompt missing %q: %q", want, second)
+		}
+	}
+
+	output := out.String()
+	if !strings.Contains(output, "answer 1") || !strings.Contains(output, "answer 2") {
+		t.Errorf("expected both answers in output, got %q", output)
+	}
+}
+
+func TestRunChat_EndsOnEOF(t *testing.T) {
+	mock := &mockProvider{}
+	streams, in, _ := llm.TestIOStreamsNonInteractive()
+	in.WriteString("one question\n")
+
+	if err := runChat(context.Background(), streams, mock); err != nil {
+		t.Fatalf("runChat() error = %v", err)
+	}
+	if len(mock.prompts) != 1 {
+		t.Errorf("expected 1 Generate call, got %d", len(mock.prompts))
+	}
+}
+
+func TestRunChat_ProviderError(t *testing.T) {
+	wantErr := errors.New("boom")
+	mock := &mockProvider{err: wantErr}
+	streams, in, _ := llm.TestIOStreams()
+	in.WriteString("hello\n")
+
+	if err := runChat(context.Background(), streams, mock); !errors.Is(err, wantErr) {
+		t.Errorf("expected provider error, got %v", err)
+	}
+}
+
+func TestRunChat_UsesInteractiveProvider(t *testing.T) {
+	mock := &mockInteractiveProvider{}
+	streams, _, _ := llm.TestIOStreams()
+
+	if err := runChat(context.Background(), streams, mock); err != nil {
+		t.Fatalf("runChat() error = %v", err)
+	}
+	if mock.interactiveCalls != 1 {
+		t.Errorf("expected RunInteractive to be called once, got %d", mock.interactiveCalls)
+	}
+	if len(mock.prompts) != 0 {
+		t.Errorf("expected no Generate calls, got %d", len(mock.prompts))
+	}
+}
+
+func TestRunChat_NonInteractiveStreamsUseTranscript(t *testing.T) {
+	mock := &mockInteractiveProvider{}
+	streams, in, _ := llm.TestIOStreamsNonInteractive()
+	in.WriteString("hello\n")
+
+	if err := runChat(context.Background(), streams, mock); err != nil {
+		t.Fatalf("runChat() error = %v", err)
+	}
+	if mock.interactiveCalls != 0 {
+		t.Errorf("expected no RunInteractive calls without a TTY, got %d", mock.interactiveCalls)
+	}
+	if len(mock.prompts) != 1 {
+		t.Errorf("expected 1 Generate call, got %d", len(mock.prompts))
+	}
+}
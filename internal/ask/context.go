@@ -0,0 +1,39 @@
+package ask
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultMaxContextBytes caps the size of file or stdin context attached to a question
+const DefaultMaxContextBytes = 100 * 1024
+
+// ReadContext reads at most max bytes of context from r. truncated reports whether input beyond
+// max was dropped; the cut is moved back to a UTF-8 boundary so no partial character is sent.
+func ReadContext(r io.Reader, max int) (context string, truncated bool, err error) {
+	data, err := io.ReadAll(io.LimitReader(r, int64(max)+1))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read context: %w", err)
+	}
+	if len(data) <= max {
+		return string(data), false, nil
+	}
+
+	cut := max
+	for cut > 0 && !utf8.RuneStart(data[cut]) {
+		cut--
+	}
+	return string(data[:cut]), true, nil
+}
+
+// appendContext adds context after prompt between explicit delimiters so the model can tell
+// the supplied material apart from the question. Blank context leaves prompt unchanged.
+func appendContext(prompt, context string) string {
+	context = strings.TrimRight(context, "\n")
+	if strings.TrimSpace(context) == "" {
+		return prompt
+	}
+	return prompt + "\n\nThe user supplied the following context for the question:\n<context>\n" + context + "\n</context>"
+}
@@ -0,0 +1,117 @@
+package ask
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/llm/llmtest"
+)
+
+func TestReadContext(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		max           int
+		want          string
+		wantTruncated bool
+	}{
+		{name: "under limit", input: "package main\n", max: 100, want: "package main\n"},
+		{name: "exactly at limit", input: "abcd", max: 4, want: "abcd"},
+		{name: "over limit", input: "abcdef", max: 4, want: "abcd", wantTruncated: true},
+		{name: "cut backs off to rune boundary", input: "abécd", max: 3, want: "ab", wantTruncated: true},
+		{name: "empty", input: "", max: 10, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated, err := ReadContext(strings.NewReader(tt.input), tt.max)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want || truncated != tt.wantTruncated {
+				t.Errorf("ReadContext() = %q, %v, want %q, %v", got, truncated, tt.want, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestAnswer_WithContext(t *testing.T) {
+	tmpl, err := LoadPromptTemplate("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		context string
+		want    string
+	}{
+		{
+			name:    "context appended after question",
+			context: "package main\n\nfunc main() {}\n",
+			want:    "User's Question: what does this do\n\nThe user supplied the following context for the question:\n<context>\npackage main\n\nfunc main() {}\n</context>",
+		},
+		{
+			name:    "blank context omitted",
+			context: "\n  \n",
+			want:    "User's Question: what does this do",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &llmtest.FakeProvider{Responses: []string{"answer"}}
+			if _, err := answer(context.Background(), fake, "what does this do", &config.ProviderConfig{}, tmpl, tt.context); err != nil {
+				t.Fatalf("answer() error = %v", err)
+			}
+			if prompt := fake.Prompts()[0]; !strings.HasSuffix(prompt, tt.want) {
+				t.Errorf("prompt = %q, want suffix %q", prompt, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitContext(t *testing.T) {
+	fake := &llmtest.FakeProvider{Model: "claude-sonnet-4-5"}
+	large := strings.Repeat("0123456789abcdef\n", 60_000) // about 255k estimated tokens
+
+	tests := []struct {
+		name          string
+		context       string
+		truncate      bool
+		wantTruncated bool
+	}{
+		{name: "small context is kept", context: "package main\n", truncate: true},
+		{name: "oversized context only warns", context: large},
+		{name: "oversized context is truncated", context: large, truncate: true, wantTruncated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fitContext(fake, "what does this do", &config.ProviderConfig{}, builtinPromptTemplate, Options{Context: tt.context, Truncate: tt.truncate})
+			if err != nil {
+				t.Fatalf("fitContext() error = %v", err)
+			}
+			if !tt.wantTruncated {
+				if got != tt.context {
+					t.Errorf("fitContext() changed the context (%d -> %d bytes)", len(tt.context), len(got))
+				}
+				return
+			}
+
+			if len(got) >= len(tt.context) || !strings.HasPrefix(tt.context, got) {
+				t.Fatalf("fitContext() = %d bytes, want a prefix shorter than %d", len(got), len(tt.context))
+			}
+			prompt, err := buildPrompt(builtinPromptTemplate, "what does this do", got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tokens, limit, fits := llm.CheckPromptSize(prompt, fake.Model); !fits {
+				t.Errorf("truncated prompt has %d estimated tokens, over the %d limit", tokens, limit)
+			}
+		})
+	}
+}
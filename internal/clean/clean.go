@@ -0,0 +1,136 @@
+// Package clean finds and removes files smix generates: fetched review directories and the
+// do history.
+package clean
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Target kinds
+const (
+	KindReviews = "reviews"
+	KindHistory = "history"
+)
+
+// reviewDirPrefixes are the directory name prefixes of fetched review directories
+var reviewDirPrefixes = []string{"pr_review_", "gca_"}
+
+// Options selects what Plan collects
+type Options struct {
+	// ReviewBase is the directory fetched reviews are written under (commands.pr.output_base).
+	// Only its direct pr_review_*/gca_* subdirectories are collected.
+	ReviewBase string
+	// HistoryPath is the do history file
+	HistoryPath string
+	// Reviews collects fetched review directories
+	Reviews bool
+	// History collects the do history file
+	History bool
+}
+
+// Target is a file or directory to remove
+type Target struct {
+	Kind string
+	Path string
+}
+
+// Plan lists the existing targets selected by opts. Nothing is removed.
+func Plan(opts Options) ([]Target, error) {
+	var targets []Target
+
+	if opts.Reviews && opts.ReviewBase != "" {
+		entries, err := os.ReadDir(opts.ReviewBase)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to list %s: %w", opts.ReviewBase, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && isReviewDir(entry.Name()) {
+				targets = append(targets, Target{Kind: KindReviews, Path: filepath.Join(opts.ReviewBase, entry.Name())})
+			}
+		}
+		sort.Slice(targets, func(i, j int) bool { return targets[i].Path < targets[j].Path })
+	}
+
+	if opts.History && opts.HistoryPath != "" {
+		info, err := os.Stat(opts.HistoryPath)
+		switch {
+		case err == nil && info.Mode().IsRegular():
+			targets = append(targets, Target{Kind: KindHistory, Path: opts.HistoryPath})
+		case err != nil && !errors.Is(err, os.ErrNotExist):
+			return nil, fmt.Errorf("failed to check %s: %w", opts.HistoryPath, err)
+		}
+	}
+
+	return targets, nil
+}
+
+// Clean removes the targets selected by opts, listing each one on w. With dryRun the targets
+// are only listed.
+func Clean(w io.Writer, opts Options, dryRun bool) error {
+	targets, err := Plan(opts)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		fmt.Fprintln(w, "Nothing to clean")
+		return nil
+	}
+
+	verb := "Removed"
+	if dryRun {
+		verb = "Would remove"
+	}
+	for _, target := range targets {
+		if !dryRun {
+			if err := Remove([]Target{target}, opts); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(w, "%s %s (%s)\n", verb, target.Path, target.Kind)
+	}
+	return nil
+}
+
+// Remove deletes targets, checking again that each is one Plan would collect for opts so a
+// path outside the smix-managed locations is never removed
+func Remove(targets []Target, opts Options) error {
+	for _, target := range targets {
+		if !managed(target, opts) {
+			return fmt.Errorf("refusing to remove %s: not a smix-managed path", target.Path)
+		}
+		if err := os.RemoveAll(target.Path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", target.Path, err)
+		}
+	}
+	return nil
+}
+
+// managed reports whether target is a path Plan collects for opts
+func managed(target Target, opts Options) bool {
+	switch target.Kind {
+	case KindReviews:
+		return opts.ReviewBase != "" &&
+			filepath.Clean(filepath.Dir(target.Path)) == filepath.Clean(opts.ReviewBase) &&
+			isReviewDir(filepath.Base(target.Path))
+	case KindHistory:
+		return opts.HistoryPath != "" && filepath.Clean(target.Path) == filepath.Clean(opts.HistoryPath)
+	default:
+		return false
+	}
+}
+
+// isReviewDir reports whether name is a fetched review directory name
+func isReviewDir(name string) bool {
+	for _, prefix := range reviewDirPrefixes {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package clean
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// setupTree creates a review base with review directories and unrelated entries, and a history file
+func setupTree(t *testing.T) Options {
+	t.Helper()
+	root := t.TempDir()
+	base := filepath.Join(root, "reviews")
+	for _, dir := range []string{"pr_review_pr1", "pr_review_pr22", "gca_review_pr3", "notes", "pr_review_"} {
+		if err := os.MkdirAll(filepath.Join(base, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(base, dir, "INDEX.md"), []byte("# Index\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A file with a review prefix is not a review directory
+	if err := os.WriteFile(filepath.Join(base, "pr_review_notes.md"), []byte("keep\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	history := filepath.Join(root, "data", "do_history.jsonl")
+	if err := os.MkdirAll(filepath.Dir(history), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(history, []byte("{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return Options{ReviewBase: base, HistoryPath: history}
+}
+
+func TestPlan(t *testing.T) {
+	opts := setupTree(t)
+	base := opts.ReviewBase
+
+	tests := []struct {
+		name    string
+		reviews bool
+		history bool
+		want    []Target
+	}{
+		{name: "nothing selected"},
+		{
+			name:    "reviews",
+			reviews: true,
+			want: []Target{
+				{Kind: KindReviews, Path: filepath.Join(base, "gca_review_pr3")},
+				{Kind: KindReviews, Path: filepath.Join(base, "pr_review_pr1")},
+				{Kind: KindReviews, Path: filepath.Join(base, "pr_review_pr22")},
+			},
+		},
+		{name: "history", history: true, want: []Target{{Kind: KindHistory, Path: opts.HistoryPath}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := opts
+			o.Reviews, o.History = tt.reviews, tt.history
+			got, err := Plan(o)
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Plan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("missing paths", func(t *testing.T) {
+		dir := t.TempDir()
+		got, err := Plan(Options{ReviewBase: filepath.Join(dir, "none"), HistoryPath: filepath.Join(dir, "none.jsonl"), Reviews: true, History: true})
+		if err != nil || len(got) != 0 {
+			t.Errorf("Plan() = %v, %v, want nothing", got, err)
+		}
+	})
+}
+
+func TestRemove(t *testing.T) {
+	opts := setupTree(t)
+	opts.Reviews, opts.History = true, true
+
+	targets, err := Plan(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Remove(targets, opts); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	for _, target := range targets {
+		if _, err := os.Stat(target.Path); !os.IsNotExist(err) {
+			t.Errorf("%s still exists", target.Path)
+		}
+	}
+	for _, kept := range []string{"notes", "pr_review_", "pr_review_notes.md"} {
+		if _, err := os.Stat(filepath.Join(opts.ReviewBase, kept)); err != nil {
+			t.Errorf("%s should be kept: %v", kept, err)
+		}
+	}
+}
+
+func TestRemove_RefusesUnmanagedPaths(t *testing.T) {
+	opts := setupTree(t)
+	outside := filepath.Join(t.TempDir(), "pr_review_pr9")
+	if err := os.Mkdir(outside, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, target := range []Target{
+		{Kind: KindReviews, Path: outside},
+		{Kind: KindReviews, Path: filepath.Join(opts.ReviewBase, "notes")},
+		{Kind: KindHistory, Path: outside},
+		{Kind: "other", Path: filepath.Join(opts.ReviewBase, "pr_review_pr1")},
+	} {
+		if err := Remove([]Target{target}, opts); err == nil {
+			t.Errorf("Remove(%v) succeeded, want a refusal", target)
+		}
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("unmanaged directory was removed: %v", err)
+	}
+}
+
+func TestClean(t *testing.T) {
+	t.Run("dry run removes nothing", func(t *testing.T) {
+		opts := setupTree(t)
+		opts.Reviews, opts.History = true, true
+
+		var out bytes.Buffer
+		if err := Clean(&out, opts, true); err != nil {
+			t.Fatalf("Clean() error = %v", err)
+		}
+		want := "Would remove " + filepath.Join(opts.ReviewBase, "gca_review_pr3") + " (reviews)\n" +
+			"Would remove " + filepath.Join(opts.ReviewBase, "pr_review_pr1") + " (reviews)\n" +
+			"Would remove " + filepath.Join(opts.ReviewBase, "pr_review_pr22") + " (reviews)\n" +
+			"Would remove " + opts.HistoryPath + " (history)\n"
+		if out.String() != want {
+			t.Errorf("output = %q, want %q", out.String(), want)
+		}
+		for _, path := range []string{filepath.Join(opts.ReviewBase, "pr_review_pr1"), opts.HistoryPath} {
+			if _, err := os.Stat(path); err != nil {
+				t.Errorf("dry run removed %s", path)
+			}
+		}
+	})
+
+	t.Run("removes selected targets only", func(t *testing.T) {
+		opts := setupTree(t)
+		opts.Reviews = true
+
+		var out bytes.Buffer
+		if err := Clean(&out, opts, false); err != nil {
+			t.Fatalf("Clean() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(opts.ReviewBase, "pr_review_pr1")); !os.IsNotExist(err) {
+			t.Error("review directory was not removed")
+		}
+		if _, err := os.Stat(opts.HistoryPath); err != nil {
+			t.Error("history was removed without --history")
+		}
+	})
+
+	t.Run("nothing to clean", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := Clean(&out, Options{ReviewBase: t.TempDir(), Reviews: true}, false); err != nil {
+			t.Fatalf("Clean() error = %v", err)
+		}
+		if out.String() != "Nothing to clean\n" {
+			t.Errorf("output = %q", out.String())
+		}
+	})
+}
@@ -21,17 +21,64 @@ func SetValue(key string, value string) error {
 	return viper.WriteConfig()
 }
 
+// ProviderSetting returns a provider-specific setting from the providers.<provider>.<key> config path
+func ProviderSetting(provider, key string) string {
+	return viper.GetString(fmt.Sprintf("providers.%s.%s", provider, key))
+}
+
+// ProviderArgs returns the extra CLI arguments configured for provider in providers.<provider>.extra_args
+func ProviderArgs(provider string) []string {
+	return viper.GetStringSlice(fmt.Sprintf("providers.%s.extra_args", provider))
+}
+
+// HTTPProxy returns the proxy URL API providers send requests through (http.proxy).
+// Empty means the standard HTTPS_PROXY environment variables apply.
+func HTTPProxy() string {
+	return viper.GetString("http.proxy")
+}
+
+// AutoOrder returns the providers the "auto" provider tries, in order (auto.order).
+// Empty means every registered provider in its default order.
+func AutoOrder() []string {
+	return viper.GetStringSlice("auto.order")
+}
+
+// AuditConfig holds the audit log settings
+type AuditConfig struct {
+	// File is the JSON lines file each Generate call is appended to (empty disables auditing)
+	File string
+	// Full records prompt and response text instead of only a hash and length
+	Full bool
+}
+
+// ResolveAuditConfig reads the audit.* config keys
+func ResolveAuditConfig() AuditConfig {
+	return AuditConfig{
+		File: viper.GetString("audit.file"),
+		Full: viper.GetBool("audit.full"),
+	}
+}
+
 // ProviderConfig holds provider and model configuration
 type ProviderConfig struct {
 	Provider string
 	Model    string
+	// Retries overrides the provider's retry count for transient failures (nil uses the default)
+	Retries *int
+	// StrictModels rejects models the provider does not list before any request is made
+	StrictModels bool
+	// Fallback is the provider used when Provider is not available or fails to authenticate (empty disables fallback)
+	Fallback string
 }
 
 // ResolveProviderConfig resolves provider configuration for a command
 // Precedence: command-specific config -> global config
 // Flags are handled separately in command layer
 func ResolveProviderConfig(commandName string) *ProviderConfig {
-	cfg := &ProviderConfig{}
+	cfg := &ProviderConfig{
+		StrictModels: viper.GetBool("strict_models"),
+		Fallback:     viper.GetString("fallback.provider"),
+	}
 
 	// Try command-specific provider
 	commandProviderKey := fmt.Sprintf("commands.%s.provider", commandName)
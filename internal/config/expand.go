@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	"github.com/spf13/viper"
+)
+
+// envReference matches ${VAR} references in config values. Bare $VAR is left alone so values
+// such as prompts or shell snippets can contain dollar signs.
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// InterpolateEnv replaces ${VAR} references in every string value of settings (including
+// nested maps and lists) using lookup. Unset variables expand to an empty string and are
+// returned, sorted and deduplicated, so the caller can warn about them.
+func InterpolateEnv(settings map[string]any, lookup func(string) (string, bool)) []string {
+	unset := make(map[string]bool)
+	for key, value := range settings {
+		settings[key] = interpolateValue(value, lookup, unset)
+	}
+
+	names := make([]string, 0, len(unset))
+	for name := range unset {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func interpolateValue(value any, lookup func(string) (string, bool), unset map[string]bool) any {
+	switch v := value.(type) {
+	case string:
+		return envReference.ReplaceAllStringFunc(v, func(ref string) string {
+			name := envReference.FindStringSubmatch(ref)[1]
+			val, ok := lookup(name)
+			if !ok {
+				unset[name] = true
+			}
+			return val
+		})
+	case map[string]any:
+		for key, item := range v {
+			v[key] = interpolateValue(item, lookup, unset)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = interpolateValue(item, lookup, unset)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// ExpandConfigFile re-reads the config file at path, expands ${VAR} references from the
+// environment, and merges the result into the global config. Merging keeps the usual
+// precedence, so SMIX_* environment variables and flags still override file values.
+// It returns the names of referenced variables that are not set.
+func ExpandConfigFile(path string) ([]string, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	settings := v.AllSettings()
+	unset := InterpolateEnv(settings, os.LookupEnv)
+	if err := viper.MergeConfigMap(settings); err != nil {
+		return nil, fmt.Errorf("failed to apply expanded config: %w", err)
+	}
+	return unset, nil
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestInterpolateEnv(t *testing.T) {
+	env := map[string]string{"GEMINI_API_KEY": "gm-secret", "GITHUB_TOKEN": "gh-secret", "HOST": "example.com"}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	settings := map[string]any{
+		"provider": "claude",
+		"prompt":   "costs $5 and $HOME stays literal",
+		"url":      "https://${HOST}/api",
+		"github":   map[string]any{"token": "${GITHUB_TOKEN}"},
+		"providers": map[string]any{
+			"gemini": map[string]any{"api_key": "${GEMINI_API_KEY}"},
+		},
+		"denylist": []any{"${MISSING}", "rm -rf", 3},
+		"retries":  2,
+		"note":     "${MISSING}${ALSO_MISSING}",
+	}
+
+	unset := InterpolateEnv(settings, lookup)
+
+	want := map[string]any{
+		"provider": "claude",
+		"prompt":   "costs $5 and $HOME stays literal",
+		"url":      "https://example.com/api",
+		"github":   map[string]any{"token": "gh-secret"},
+		"providers": map[string]any{
+			"gemini": map[string]any{"api_key": "gm-secret"},
+		},
+		"denylist": []any{"", "rm -rf", 3},
+		"retries":  2,
+		"note":     "",
+	}
+	if !reflect.DeepEqual(settings, want) {
+		t.Errorf("settings = %#v\nwant %#v", settings, want)
+	}
+	if wantUnset := []string{"ALSO_MISSING", "MISSING"}; !reflect.DeepEqual(unset, wantUnset) {
+		t.Errorf("unset = %v, want %v", unset, wantUnset)
+	}
+}
+
+func TestExpandConfigFile(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "provider: ${SMIX_TEST_PROVIDER}\nmodel: ${SMIX_TEST_UNSET}\ngithub:\n  token: ${SMIX_TEST_TOKEN}\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SMIX_TEST_PROVIDER", "gemini")
+	t.Setenv("SMIX_TEST_TOKEN", "gh-secret")
+
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	unset, err := ExpandConfigFile(path)
+	if err != nil {
+		t.Fatalf("ExpandConfigFile() error = %v", err)
+	}
+	if !reflect.DeepEqual(unset, []string{"SMIX_TEST_UNSET"}) {
+		t.Errorf("unset = %v, want [SMIX_TEST_UNSET]", unset)
+	}
+	if got := viper.GetString("provider"); got != "gemini" {
+		t.Errorf("provider = %q, want gemini", got)
+	}
+	if got := viper.GetString("model"); got != "" {
+		t.Errorf("model = %q, want empty", got)
+	}
+	if got := viper.GetString("github.token"); got != "gh-secret" {
+		t.Errorf("github.token = %q, want gh-secret", got)
+	}
+
+	// SMIX_* environment variables still take precedence over expanded file values
+	viper.SetEnvPrefix("SMIX")
+	viper.AutomaticEnv()
+	t.Setenv("SMIX_MODEL", "opus")
+	if got := viper.GetString("model"); got != "opus" {
+		t.Errorf("model with SMIX_MODEL set = %q, want opus", got)
+	}
+}
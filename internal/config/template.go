@@ -2,21 +2,66 @@ package config
 
 const configTemplate = `# smix configuration file
 # Provider settings control which LLM provider to use
+# String values may reference environment variables as ${VAR}
 
-# Global default provider (claude or gemini)
+# Global default provider (claude, gemini, or auto for the first available)
 provider: claude
 
+# Providers tried in order when provider is auto (default: claude, gemini)
+#auto:
+#  order: [gemini, claude]
+
 # Global default model (optional, uses provider default if omitted)
 # model: sonnet
 
+# Reject unknown model names (e.g. typos) up front instead of at request time
+# strict_models: true
+
+# Provider to use when the configured one is not available or fails to authenticate (optional)
+#fallback:
+#  provider: claude
+
 # Provider-specific settings
 providers:
   claude:
     # Path to claude CLI if not in PATH (optional)
     # cli_path: /usr/local/bin/claude
+    # API key (ANTHROPIC_API_KEY takes precedence; ${VAR} references are expanded)
+    # api_key: ${ANTHROPIC_API_KEY}
+    # API endpoint override, e.g. for a gateway (optional)
+    # base_url: https://api.anthropic.com
+    # Extra arguments for every claude CLI run, placed before the prompt (optional)
+    # extra_args: ["--allowedTools", "Bash,Edit"]
   gemini:
-    # API key (prefer SMIX_GEMINI_API_KEY environment variable)
-    # api_key: ${SMIX_GEMINI_API_KEY}
+    # API key (SMIX_GEMINI_API_KEY takes precedence; ${VAR} references are expanded)
+    # api_key: ${GEMINI_API_KEY}
+    # Backend for non-interactive requests: api or cli
+    # (default: api when an API key is set, otherwise cli)
+    # prefer: api
+    # API endpoint override, e.g. for a regional or internal gateway (optional)
+    # base_url: https://generativelanguage.googleapis.com/
+    # Extra arguments for every gemini CLI run, placed before the prompt (optional)
+    # extra_args: ["--sandbox"]
+
+# Proxy for API providers (optional; HTTPS_PROXY and NO_PROXY apply when unset)
+#http:
+#  proxy: http://proxy.example.com:3128
+
+# GitHub access for pr review (optional)
+# Token lookup order: GITHUB_TOKEN, github.token_file, then 'gh auth token'
+#github:
+#  token_file: ~/.config/smix/github_token
+#  # Owner assumed when a repo is given without one ('smix pr review myrepo 123')
+#  default_owner: myorg
+#  # Longest time to wait for a GitHub API rate limit to reset before failing (0 fails immediately)
+#  rate_limit_max_wait: 1m
+
+# Audit log of every prompt sent to a provider (optional)
+# Appends JSON lines with timestamp, command, provider, model, prompt hash and response length
+#audit:
+#  file: /var/log/smix/audit.jsonl
+#  # Also record full prompt and response text
+#  full: false
 
 # Per-command overrides (optional)
 # Uncomment and customize as needed
@@ -24,12 +69,45 @@ providers:
 #  ask:
 #    provider: gemini
 #    model: gemini-1.5-flash
+#    # Custom prompt (text/template that must include {{.Question}})
+#    prompt_template: ~/.config/smix/ask_prompt.tmpl
+#    # Largest file or stdin context attached to a question, in bytes
+#    max_context_bytes: 102400
+#    # Questions answered in parallel by --batch
+#    batch_concurrency: 4
 #  do:
 #    provider: gemini
 #    model: gemini-1.5-flash
+#    # Extra regular expressions treated as dangerous (added to the built-in denylist)
+#    denylist:
+#      - '\bterraform\s+destroy\b'
+#    # Record generated commands in $XDG_DATA_HOME/smix/do_history.jsonl (default: true)
+#    history: false
+#    # End responses at the first blank line to drop trailing explanations (cuts multi-line commands short)
+#    stop_at_blank_line: true
 #  pr:
 #    provider: claude
 #    model: sonnet
+#    # Where fetched feedback is kept (default: $XDG_CACHE_HOME/smix/reviews; --local uses the current directory)
+#    output_base: ~/reviews
+#    # Directory name under output_base ({repo}, {pr}, {date} are replaced; pr summary/open/apply
+#    # use the latest existing {date})
+#    output_dir: {repo}/pr{pr}-{date}
+#    # Custom review prompt (text/template with .FeedbackFile, .TargetFile, .Index, .Total)
+#    prompt_template: ~/.config/smix/pr_prompt.tmpl
+#    # Lines of file context shown before and after each commented line
+#    context_before: 10
+#    context_after: 30
+#    # Embed the whole file instead of a snippet when it has fewer lines than this (0 always uses a snippet)
+#    full_file_under: 150
+#    # Warn about comments on the same file within this many lines of each other (negative disables)
+#    overlap_window: 3
+#    # Embed the repository's CONVENTIONS.md in every prompt
+#    conventions: true
+#    # Ask before launching more than this many review sessions (negative never asks)
+#    confirm_threshold: 10
+#    # Wait between session launches to stay under provider rate limits
+#    delay: 30s
 
 # Observability settings
 log_level: info  # debug, info, warn, error
@@ -0,0 +1,48 @@
+package do
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/providers"
+)
+
+const explainPromptTemplate = `You are a shell command expert for Unix-like systems (Linux, macOS).
+Explain what the following shell command does in 1-2 plain sentences, mentioning any flags that matter
+and anything it changes or deletes. Do not repeat the command, and use no markdown formatting.
+
+The command was generated for this request: %s
+
+Command: %s`
+
+// Explain returns a short plain-text explanation of command, which was generated for taskDescription
+func Explain(ctx context.Context, command, taskDescription string, cfg *config.ProviderConfig) (string, error) {
+	provider, err := providers.GetProviderWithFallback(ctx, cfg, os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	return explain(ctx, provider, command, taskDescription, cfg)
+}
+
+// explain asks an already resolved provider to explain command
+func explain(ctx context.Context, provider llm.Provider, command, taskDescription string, cfg *config.ProviderConfig) (string, error) {
+	prompt := fmt.Sprintf(explainPromptTemplate, taskDescription, command)
+	slog.Debug("explain prompt constructed", "length", len(prompt))
+
+	response, err := provider.Generate(ctx, prompt, generateOptions(cfg)...)
+	if err != nil {
+		return "", fmt.Errorf("failed to explain command: %w", err)
+	}
+
+	explanation := strings.TrimSpace(response)
+	if explanation == "" {
+		return "", fmt.Errorf("provider returned an empty explanation")
+	}
+	return explanation, nil
+}
@@ -0,0 +1,54 @@
+package do
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm/llmtest"
+)
+
+func TestExplain(t *testing.T) {
+	fake := &llmtest.FakeProvider{Responses: []string{
+		"find ~ -type f -size +50M",
+		"  Searches your home directory for regular files larger than 50MB. Nothing is modified.\n",
+	}}
+	cfg := &config.ProviderConfig{Model: "sonnet"}
+
+	command, err := translate(context.Background(), fake, "find big files", cfg, Options{})
+	if err != nil {
+		t.Fatalf("translate() error = %v", err)
+	}
+	explanation, err := explain(context.Background(), fake, command, "find big files", cfg)
+	if err != nil {
+		t.Fatalf("explain() error = %v", err)
+	}
+
+	if command != "find ~ -type f -size +50M" {
+		t.Errorf("command = %q", command)
+	}
+	if want := "Searches your home directory for regular files larger than 50MB. Nothing is modified."; explanation != want {
+		t.Errorf("explanation = %q, want %q", explanation, want)
+	}
+
+	prompts := fake.Prompts()
+	if len(prompts) != 2 {
+		t.Fatalf("got %d Generate calls, want 2", len(prompts))
+	}
+	for _, want := range []string{"Command: find ~ -type f -size +50M", "request: find big files"} {
+		if !strings.Contains(prompts[1], want) {
+			t.Errorf("explain prompt missing %q:\n%s", want, prompts[1])
+		}
+	}
+	if got := fake.LastOptions().Model; got != "sonnet" {
+		t.Errorf("explain model = %q, want sonnet", got)
+	}
+}
+
+func TestExplain_EmptyResponse(t *testing.T) {
+	fake := &llmtest.FakeProvider{Responses: []string{"  \n"}}
+	if _, err := explain(context.Background(), fake, "ls", "list files", &config.ProviderConfig{}); err == nil {
+		t.Error("expected an error for an empty explanation")
+	}
+}
@@ -0,0 +1,54 @@
+package do
+
+import "strings"
+
+const fence = "```"
+
+// stripCodeFences removes markdown code fences that models often wrap commands in.
+// Output that is entirely one fenced block (possibly nested) is unwrapped, and prose
+// containing exactly one fenced block is reduced to that block's contents. Anything
+// else, including output with several blocks, is returned trimmed but otherwise unchanged.
+func stripCodeFences(output string) string {
+	trimmed := strings.TrimSpace(output)
+
+	unwrapped := false
+	for strings.HasPrefix(trimmed, fence) && strings.HasSuffix(trimmed, fence) && len(trimmed) >= 2*len(fence) {
+		inner := strings.TrimSuffix(trimmed, fence)
+		if newline := strings.IndexByte(inner, '\n'); newline >= 0 {
+			// Drop the opening fence line along with any language tag
+			inner = inner[newline+1:]
+		} else {
+			inner = strings.TrimPrefix(inner, fence)
+		}
+		inner = strings.TrimSpace(inner)
+
+		// Only unwrap when the block holds no other fences, or is itself a nested block.
+		// Otherwise the outer fences belong to separate blocks.
+		if strings.Contains(inner, fence) && !(strings.HasPrefix(inner, fence) && strings.HasSuffix(inner, fence)) {
+			break
+		}
+		trimmed = inner
+		unwrapped = true
+	}
+	if unwrapped {
+		return trimmed
+	}
+
+	lines := strings.Split(trimmed, "\n")
+	var fenceLines []int
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), fence) {
+			fenceLines = append(fenceLines, i)
+		}
+	}
+	if len(fenceLines) == 2 {
+		return strings.TrimSpace(strings.Join(lines[fenceLines[0]+1:fenceLines[1]], "\n"))
+	}
+
+	// Single inline code span wrapping the whole command
+	if len(trimmed) > 2 && strings.Count(trimmed, "`") == 2 && trimmed[0] == '`' && trimmed[len(trimmed)-1] == '`' {
+		return strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+	}
+
+	return trimmed
+}
@@ -0,0 +1,33 @@
+package do
+
+import "testing"
+
+func TestStripCodeFences(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"bare command", "ls -la", "ls -la"},
+		{"bare with whitespace", "  ls -la\n", "ls -la"},
+		{"bash fence", "```bash\nls -la\n```", "ls -la"},
+		{"sh fence with trailing newline", "```sh\nfind ~ -type f -size +50M\n```\n", "find ~ -type f -size +50M"},
+		{"fence without language", "```\ndu -sh .\n```", "du -sh ."},
+		{"single line fence", "```ls -la```", "ls -la"},
+		{"double fenced", "```\n```bash\nls -la\n```\n```", "ls -la"},
+		{"fence inside prose", "Here is the command:\n```bash\nfuser -k 3000/tcp\n```\nThis kills the process.", "fuser -k 3000/tcp"},
+		{"multi-line script in fence", "```bash\nfor f in *.log; do\n  gzip \"$f\"\ndone\n```", "for f in *.log; do\n  gzip \"$f\"\ndone"},
+		{"multi-line script without fence", "for f in *.log; do\n  gzip \"$f\"\ndone", "for f in *.log; do\n  gzip \"$f\"\ndone"},
+		{"two separate blocks left alone", "```\nls\n```\nor\n```\nls -a\n```", "```\nls\n```\nor\n```\nls -a\n```"},
+		{"inline code span", "`ls -la`", "ls -la"},
+		{"command substitution untouched", "echo `date`", "echo `date`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripCodeFences(tt.input); got != tt.want {
+				t.Errorf("stripCodeFences(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package do
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/connorhough/smix/internal/paths"
+)
+
+// HistoryFile is the name of the do history file inside the smix data directory
+const HistoryFile = "do_history.jsonl"
+
+// DefaultHistoryLimit is the number of entries shown by --history when no count is given
+const DefaultHistoryLimit = 10
+
+// HistoryEntry is one generated command in the do history
+type HistoryEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Request   string    `json:"request"`
+	Command   string    `json:"command"`
+}
+
+// History appends generated commands to a JSON lines file and reads them back
+type History struct {
+	// Path is the history file
+	Path string
+	// Disabled turns Record into a no-op
+	Disabled bool
+
+	now func() time.Time
+}
+
+// NewHistory creates a history backed by path
+func NewHistory(path string, disabled bool) *History {
+	return &History{Path: path, Disabled: disabled, now: time.Now}
+}
+
+// DefaultHistoryPath returns do_history.jsonl in the smix data directory ($XDG_DATA_HOME/smix)
+func DefaultHistoryPath() (string, error) {
+	dataDir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, HistoryFile), nil
+}
+
+// Record appends a request and its generated command to the history file.
+// Each entry is a single O_APPEND write, so concurrent smix processes do not interleave lines.
+func (h *History) Record(request, command string) error {
+	if h.Disabled {
+		return nil
+	}
+
+	now := time.Now
+	if h.now != nil {
+		now = h.now
+	}
+	data, err := json.Marshal(HistoryEntry{Timestamp: now().UTC(), Request: request, Command: command})
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+
+	if err := paths.EnsureParent(h.Path); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(h.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	return nil
+}
+
+// Last returns the last n entries, oldest first. n <= 0 returns every entry.
+// A missing history file yields no entries; malformed lines are skipped.
+func (h *History) Last(n int) ([]HistoryEntry, error) {
+	f, err := os.Open(h.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			slog.Debug("skipping malformed history line", "error", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	if n > 0 && len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return entries, nil
+}
+
+// WriteHistory prints entries as a timestamped request followed by the indented command
+func WriteHistory(w io.Writer, entries []HistoryEntry) error {
+	for _, entry := range entries {
+		if _, err := fmt.Fprintf(w, "%s  %s\n    %s\n", entry.Timestamp.Local().Format("2006-01-02 15:04"), entry.Request, entry.Command); err != nil {
+			return err
+		}
+	}
+	return nil
+}
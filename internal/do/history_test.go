@@ -0,0 +1,120 @@
+package do
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistory_RecordAndLast(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "smix", HistoryFile)
+	history := NewHistory(path, false)
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	history.now = func() time.Time {
+		clock = clock.Add(time.Minute)
+		return clock
+	}
+
+	records := [][2]string{
+		{"list files", "ls -la"},
+		{"disk usage", "du -sh ."},
+		{"find big files", "find . -size +50M"},
+	}
+	for _, r := range records {
+		if err := history.Record(r[0], r[1]); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		n         int
+		wantFirst string
+		wantLen   int
+	}{
+		{name: "last two", n: 2, wantFirst: "du -sh .", wantLen: 2},
+		{name: "more than recorded", n: 10, wantFirst: "ls -la", wantLen: 3},
+		{name: "all", n: 0, wantFirst: "ls -la", wantLen: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := history.Last(tt.n)
+			if err != nil {
+				t.Fatalf("Last() error = %v", err)
+			}
+			if len(entries) != tt.wantLen {
+				t.Fatalf("got %d entries, want %d", len(entries), tt.wantLen)
+			}
+			if entries[0].Command != tt.wantFirst {
+				t.Errorf("first command = %q, want %q", entries[0].Command, tt.wantFirst)
+			}
+			last := entries[len(entries)-1]
+			if last.Request != "find big files" || !last.Timestamp.Equal(clock) {
+				t.Errorf("last entry = %+v, want the most recent record", last)
+			}
+		})
+	}
+}
+
+func TestHistory_Disabled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), HistoryFile)
+	history := NewHistory(path, true)
+
+	if err := history.Record("list files", "ls"); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("disabled history created %s (stat error = %v)", path, err)
+	}
+}
+
+func TestHistory_LastMissingAndMalformed(t *testing.T) {
+	dir := t.TempDir()
+
+	entries, err := NewHistory(filepath.Join(dir, "missing.jsonl"), false).Last(5)
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Last() on missing file = %v, %v, want no entries and no error", entries, err)
+	}
+
+	path := filepath.Join(dir, HistoryFile)
+	content := `{"timestamp":"2026-03-01T12:00:00Z","request":"a","command":"echo a"}
+not json
+{"timestamp":"2026-03-01T12:01:00Z","request":"b","command":"echo b"}
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	entries, err = NewHistory(path, false).Last(0)
+	if err != nil {
+		t.Fatalf("Last() error = %v", err)
+	}
+	if len(entries) != 2 || entries[1].Command != "echo b" {
+		t.Errorf("entries = %+v, want the two valid lines", entries)
+	}
+}
+
+func TestDefaultHistoryPath(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/tmp/data")
+	path, err := DefaultHistoryPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join("/tmp/data", "smix", HistoryFile); path != want {
+		t.Errorf("DefaultHistoryPath() = %q, want %q", path, want)
+	}
+}
+
+func TestWriteHistory(t *testing.T) {
+	var buf bytes.Buffer
+	entries := []HistoryEntry{{Timestamp: time.Now(), Request: "list files", Command: "ls -la"}}
+	if err := WriteHistory(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "list files\n    ls -la\n") {
+		t.Errorf("WriteHistory() = %q", buf.String())
+	}
+}
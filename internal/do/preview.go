@@ -0,0 +1,353 @@
+package do
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// errNotPreviewable marks commands PreviewEdit does not understand
+var errNotPreviewable = errors.New("not a previewable in-place edit")
+
+// sedEdit is a parsed "sed -i 's/pattern/replacement/flags' file..." command
+type sedEdit struct {
+	re          *regexp.Regexp
+	replacement string
+	global      bool
+	files       []string
+}
+
+// PreviewEdit shows what an in-place edit command would change as a unified diff, without
+// running it. Only single sed -i substitutions (s/pattern/replacement/ with the g and i
+// flags) are understood; ok is false for any other command. Files are read from disk
+// relative to the working directory and are never written.
+func PreviewEdit(command string) (diff string, ok bool, err error) {
+	edit, err := parseSedEdit(command)
+	if errors.Is(err, errNotPreviewable) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	var b strings.Builder
+	for _, file := range edit.files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read %s for preview: %w", file, err)
+		}
+		b.WriteString(edit.diff(file, string(data)))
+	}
+	return b.String(), true, nil
+}
+
+// diff applies the substitution to each line of content and renders the changed lines as
+// unified diff hunks without context
+func (e *sedEdit) diff(file, content string) string {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+
+	var hunks strings.Builder
+	offset := 0
+	for i := 0; i < len(lines); {
+		after := e.apply(lines[i])
+		if after == lines[i] {
+			i++
+			continue
+		}
+
+		start := i
+		var removed, added []string
+		for ; i < len(lines); i++ {
+			after := e.apply(lines[i])
+			if after == lines[i] {
+				break
+			}
+			removed = append(removed, lines[i])
+			added = append(added, strings.Split(after, "\n")...)
+		}
+
+		fmt.Fprintf(&hunks, "@@ -%d,%d +%d,%d @@\n", start+1, len(removed), start+1+offset, len(added))
+		for _, line := range removed {
+			fmt.Fprintf(&hunks, "-%s\n", line)
+		}
+		for _, line := range added {
+			fmt.Fprintf(&hunks, "+%s\n", line)
+		}
+		offset += len(added) - len(removed)
+	}
+
+	if hunks.Len() == 0 {
+		return fmt.Sprintf("(no changes to %s)\n", file)
+	}
+	return fmt.Sprintf("--- %s\n+++ %s (after edit)\n%s", file, file, hunks.String())
+}
+
+// apply performs the substitution on a single line
+func (e *sedEdit) apply(line string) string {
+	if e.global {
+		return e.re.ReplaceAllString(line, e.replacement)
+	}
+	loc := e.re.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return line
+	}
+	expanded := e.re.ExpandString(nil, e.replacement, line, loc)
+	return line[:loc[0]] + string(expanded) + line[loc[1]:]
+}
+
+// parseSedEdit parses a sed command that edits files in place with a single substitution.
+// Anything else, including pipelines and redirections, returns errNotPreviewable.
+func parseSedEdit(command string) (*sedEdit, error) {
+	args, err := splitWords(command)
+	if err != nil || len(args) == 0 || (args[0] != "sed" && args[0] != "gsed") {
+		return nil, errNotPreviewable
+	}
+
+	var (
+		inPlace  bool
+		extended bool
+		script   string
+		files    []string
+	)
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--in-place" || strings.HasPrefix(arg, "--in-place="):
+			inPlace = true
+		case arg == "--regexp-extended":
+			extended = true
+		case arg == "--expression" || arg == "-e":
+			if script != "" || i+1 >= len(args) {
+				return nil, errNotPreviewable
+			}
+			i++
+			script = args[i]
+		case strings.HasPrefix(arg, "-") && len(arg) > 1 && !strings.HasPrefix(arg, "--"):
+			for j := 1; j < len(arg); j++ {
+				switch arg[j] {
+				case 'E', 'r':
+					extended = true
+				case 'i':
+					inPlace = true
+					// BSD sed takes the backup suffix as a separate, possibly empty, argument
+					if j == len(arg)-1 && i+1 < len(args) && args[i+1] == "" {
+						i++
+					}
+					j = len(arg)
+				default:
+					return nil, errNotPreviewable
+				}
+			}
+		case strings.HasPrefix(arg, "--"):
+			return nil, errNotPreviewable
+		case script == "":
+			script = arg
+		default:
+			files = append(files, arg)
+		}
+	}
+	if !inPlace || script == "" || len(files) == 0 {
+		return nil, errNotPreviewable
+	}
+
+	edit, err := parseSubstitution(script, extended)
+	if err != nil {
+		return nil, err
+	}
+	edit.files = files
+	return edit, nil
+}
+
+// parseSubstitution parses a single s/pattern/replacement/flags sed script
+func parseSubstitution(script string, extended bool) (*sedEdit, error) {
+	if len(script) < 4 || script[0] != 's' {
+		return nil, errNotPreviewable
+	}
+	delim := script[1]
+	if delim == '\\' || delim == '\n' {
+		return nil, errNotPreviewable
+	}
+
+	parts := splitUnescaped(script[2:], delim)
+	if len(parts) != 3 {
+		return nil, errNotPreviewable
+	}
+	pattern, replacement, flags := parts[0], parts[1], parts[2]
+
+	global, insensitive := false, false
+	for _, flag := range flags {
+		switch flag {
+		case 'g':
+			global = true
+		case 'i', 'I':
+			insensitive = true
+		default:
+			// Numeric occurrences, p and w change more than a line-for-line substitution
+			return nil, errNotPreviewable
+		}
+	}
+
+	expr, ok := translatePattern(pattern, extended)
+	if !ok {
+		return nil, errNotPreviewable
+	}
+	if insensitive {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("cannot preview sed pattern %q: %w", pattern, err)
+	}
+
+	return &sedEdit{re: re, replacement: translateReplacement(replacement), global: global}, nil
+}
+
+// splitUnescaped splits s on unescaped delim, unescaping the delimiter itself
+func splitUnescaped(s string, delim byte) []string {
+	var parts []string
+	var cur strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == delim:
+			cur.WriteByte(delim)
+			i++
+		case s[i] == '\\' && i+1 < len(s):
+			cur.WriteByte(s[i])
+			cur.WriteByte(s[i+1])
+			i++
+		case s[i] == delim:
+			parts = append(parts, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(s[i])
+		}
+	}
+	return append(parts, cur.String())
+}
+
+// translatePattern converts a sed regular expression to Go syntax. Basic expressions swap
+// the meaning of escaped and bare (){}+?|. Backreferences and word boundaries, which Go
+// does not support, report false.
+func translatePattern(pattern string, extended bool) (string, bool) {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if c == '\\' && i+1 < len(pattern) {
+			next := pattern[i+1]
+			i++
+			switch {
+			case next >= '1' && next <= '9', next == '<', next == '>':
+				return "", false
+			case !extended && strings.IndexByte("(){}+?|", next) >= 0:
+				b.WriteByte(next)
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(next)
+			}
+			continue
+		}
+		if !extended && strings.IndexByte("(){}+?|", c) >= 0 {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), true
+}
+
+// translateReplacement converts a sed replacement (& and \1-\9) to a regexp template
+func translateReplacement(replacement string) string {
+	var b strings.Builder
+	for i := 0; i < len(replacement); i++ {
+		c := replacement[i]
+		switch {
+		case c == '\\' && i+1 < len(replacement):
+			next := replacement[i+1]
+			i++
+			switch {
+			case next >= '0' && next <= '9':
+				fmt.Fprintf(&b, "${%c}", next)
+			case next == 'n':
+				b.WriteByte('\n')
+			case next == 't':
+				b.WriteByte('\t')
+			case next == '$':
+				b.WriteString("$$")
+			default:
+				b.WriteByte(next)
+			}
+		case c == '&':
+			b.WriteString("${0}")
+		case c == '$':
+			b.WriteString("$$")
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// splitWords splits a command into words following POSIX shell quoting. Unquoted shell
+// operators, substitutions and globs are rejected since their effect cannot be previewed.
+func splitWords(command string) ([]string, error) {
+	var (
+		words   []string
+		cur     strings.Builder
+		inWord  bool
+		quote   byte
+		escaped bool
+	)
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case escaped:
+			cur.WriteByte(c)
+			escaped = false
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				cur.WriteByte(c)
+			}
+		case quote == '"':
+			switch c {
+			case '"':
+				quote = 0
+			case '\\':
+				if i+1 < len(command) && strings.IndexByte("\"\\$`", command[i+1]) >= 0 {
+					i++
+				}
+				cur.WriteByte(command[i])
+			case '$', '`':
+				return nil, errNotPreviewable
+			default:
+				cur.WriteByte(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case c == '\\':
+			escaped = true
+			inWord = true
+		case c == ' ' || c == '\t':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		case strings.IndexByte("|;&<>()$`*?[{\n~", c) >= 0:
+			return nil, errNotPreviewable
+		default:
+			cur.WriteByte(c)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errNotPreviewable
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
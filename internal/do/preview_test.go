@@ -0,0 +1,94 @@
+package do
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPreviewEdit(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(path, []byte("a\nabc\nxyz\naaa\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		want    string
+	}{
+		{
+			name:    "first match per line",
+			command: "sed -i 's/a/b/' " + path,
+			want:    "--- " + path + "\n+++ " + path + " (after edit)\n@@ -1,2 +1,2 @@\n-a\n-abc\n+b\n+bbc\n@@ -4,1 +4,1 @@\n-aaa\n+baa\n",
+		},
+		{
+			name:    "global and case-insensitive",
+			command: "sed -i.bak -e 's/A/b/gI' " + path,
+			want:    "--- " + path + "\n+++ " + path + " (after edit)\n@@ -1,2 +1,2 @@\n-a\n-abc\n+b\n+bbc\n@@ -4,1 +4,1 @@\n-aaa\n+bbb\n",
+		},
+		{
+			name:    "basic groups and backreferences in the replacement",
+			command: `sed -i '' 's|\(x\)\(y\)|\2\1-&|' ` + path,
+			want:    "--- " + path + "\n+++ " + path + " (after edit)\n@@ -3,1 +3,1 @@\n-xyz\n+yx-xyz\n",
+		},
+		{
+			name:    "extended regexp with a line split",
+			command: `sed -E -i 's/^(ab)c$/\1\nc/' ` + path,
+			want:    "--- " + path + "\n+++ " + path + " (after edit)\n@@ -2,1 +2,2 @@\n-abc\n+ab\n+c\n",
+		},
+		{
+			name:    "no match",
+			command: "sed -i 's/q/r/g' " + path,
+			want:    "(no changes to " + path + ")\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := PreviewEdit(tt.command)
+			if err != nil || !ok {
+				t.Fatalf("PreviewEdit() = %v, %v", ok, err)
+			}
+			if got != tt.want {
+				t.Errorf("PreviewEdit() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "a\nabc\nxyz\naaa\n" {
+		t.Errorf("file was modified: %q, %v", data, err)
+	}
+}
+
+func TestPreviewEdit_NotPreviewable(t *testing.T) {
+	for _, command := range []string{
+		"ls -la",
+		"sed 's/a/b/' file.txt",
+		"sed -i 's/a/b/'",
+		"sed -i 's/a/b/' *.txt",
+		"sed -i 's/a/b/' file.txt && echo done",
+		"sed -i 's/a/b/2' file.txt",
+		"sed -i 's/a/b/p' file.txt",
+		"sed -i '/a/d' file.txt",
+		`sed -i 's/\(a\)\1/b/' file.txt`,
+		"sed -n -i 's/a/b/' file.txt",
+		`sed -i "s/$HOME/x/" file.txt`,
+	} {
+		t.Run(command, func(t *testing.T) {
+			_, ok, err := PreviewEdit(command)
+			if ok || err != nil {
+				t.Errorf("PreviewEdit() = %v, %v, want not previewable", ok, err)
+			}
+		})
+	}
+}
+
+func TestPreviewEdit_MissingFile(t *testing.T) {
+	_, _, err := PreviewEdit("sed -i 's/a/b/' " + filepath.Join(t.TempDir(), "missing.txt"))
+	if err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,122 @@
+package do
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/providers"
+)
+
+// refineExitCommand cancels a refinement session without accepting a command
+const refineExitCommand = "/exit"
+
+const refinePromptTemplate = `You are a shell command expert for %[1]s.
+You are refining a %[2]s command with the user. Apply the latest refinement to the current command.
+
+Requirements:
+1. Output ONLY the raw updated command with no explanations, preambles, or markdown formatting
+2. Keep every earlier requirement unless the refinement overrides it
+3. Ensure commands are safe and won't cause damage to the system
+4. Commands should be one-liners that can be directly executed or piped
+5. %[3]s
+
+Original request: %[4]s
+
+Refinement history:
+%[5]s
+Updated command:`
+
+const interactiveRefinePrompt = `You are a shell command expert for %[1]s.
+Translate the user's request into a single %[2]s command, then refine it as the user asks for changes.
+%[3]s.
+Reply with only the current command each time, as a one-liner with no markdown. Do not run any commands yourself.
+
+User's Request: %[4]s`
+
+// Refine translates a task into a shell command and lets the user refine it conversationally.
+// It returns the accepted command, or an empty string when the session was cancelled or, with
+// opts.HandOff, handed to an interactive provider that printed its own output.
+func Refine(ctx context.Context, streams *llm.IOStreams, taskDescription string, cfg *config.ProviderConfig, opts Options) (string, error) {
+	provider, err := providers.GetProviderWithFallback(ctx, cfg, streams.ErrOut)
+	if err != nil {
+		return "", fmt.Errorf("failed to get provider: %w", err)
+	}
+	if err := providers.CheckModel(provider, cfg); err != nil {
+		return "", err
+	}
+
+	return refine(ctx, streams, provider, taskDescription, cfg, opts)
+}
+
+// refine drives a refinement session with an already resolved provider
+func refine(ctx context.Context, streams *llm.IOStreams, provider llm.Provider, taskDescription string, cfg *config.ProviderConfig, opts Options) (string, error) {
+	shell, profile := resolveShell(opts.Shell)
+
+	if interactive, ok := provider.(llm.InteractiveProvider); ok && opts.HandOff && streams.IsInteractive() {
+		slog.Debug("starting interactive refinement", "provider", provider.Name())
+		prompt := fmt.Sprintf(interactiveRefinePrompt, profile.platform, shell, profile.syntax, taskDescription)
+		return "", interactive.RunInteractive(ctx, streams, prompt, generateOptions(cfg)...)
+	}
+
+	command, err := translate(ctx, provider, taskDescription, cfg, opts)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(streams.Out, "Describe a change to refine the command, press Enter to accept it, or type %s to cancel.\n", refineExitCommand)
+	printCandidate(streams, command, opts)
+
+	var history strings.Builder
+	scanner := bufio.NewScanner(streams.In)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		fmt.Fprint(streams.Out, "refine> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(streams.Out)
+			return command, scanner.Err()
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		switch line {
+		case "":
+			return command, nil
+		case refineExitCommand:
+			return "", nil
+		}
+
+		fmt.Fprintf(&history, "Command: %s\nRefinement: %s\n", command, line)
+
+		response, err := provider.Generate(ctx, fmt.Sprintf(refinePromptTemplate, profile.platform, shell, profile.syntax, taskDescription, history.String()), generateOptions(cfg)...)
+		if err != nil {
+			return "", err
+		}
+		command = stripCodeFences(response)
+		printCandidate(streams, command, opts)
+	}
+}
+
+// printCandidate shows the current command with its risk level, withholding dangerous
+// commands unless opts.AllowDangerous is set
+func printCandidate(streams *llm.IOStreams, command string, opts Options) {
+	switch ClassifyRiskWithDenylist(command, opts.Denylist) {
+	case Dangerous:
+		if !opts.AllowDangerous {
+			fmt.Fprintln(streams.Out, "Current command: [dangerous, withheld] refine it further or re-run with --i-understand")
+			return
+		}
+		fmt.Fprintf(streams.Out, "Current command [dangerous]: %s\n", command)
+	case Caution:
+		fmt.Fprintf(streams.Out, "Current command [caution]: %s\n", command)
+	default:
+		fmt.Fprintf(streams.Out, "Current command: %s\n", command)
+	}
+}
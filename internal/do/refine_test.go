@@ -0,0 +1,178 @@
+package do
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
+)
+
+// scriptedProvider returns its responses in order and records each prompt
+type scriptedProvider struct {
+	responses []string
+	prompts   []string
+}
+
+func (s *scriptedProvider) Generate(ctx context.Context, prompt string, opts ...llm.Option) (string, error) {
+	s.prompts = append(s.prompts, prompt)
+	response := s.responses[0]
+	if len(s.responses) > 1 {
+		s.responses = s.responses[1:]
+	}
+	return response, nil
+}
+
+func (s *scriptedProvider) ValidateModel(model string) error { return nil }
+func (s *scriptedProvider) DefaultModel() string             { return "mock-model" }
+func (s *scriptedProvider) Name() string                     { return "mock" }
+
+// scriptedInteractiveProvider also implements llm.InteractiveProvider
+type scriptedInteractiveProvider struct {
+	scriptedProvider
+	interactivePrompt string
+}
+
+func (s *scriptedInteractiveProvider) RunInteractive(ctx context.Context, streams *llm.IOStreams, prompt string, opts ...llm.Option) error {
+	s.interactivePrompt = prompt
+	return nil
+}
+
+func TestRefine_AppliesRefinements(t *testing.T) {
+	mock := &scriptedProvider{responses: []string{
+		"zip -r logs.zip logs",
+		"tar czf logs.tar.gz logs",
+		"```\ntar czvf logs.tar.gz logs\n```",
+	}}
+	streams, in, out := llm.TestIOStreams()
+	in.WriteString("use gzip not zip\nadd verbose\n\n")
+
+	got, err := refine(context.Background(), streams, mock, "archive the logs dir", &config.ProviderConfig{}, Options{})
+	if err != nil {
+		t.Fatalf("refine() error = %v", err)
+	}
+	if got != "tar czvf logs.tar.gz logs" {
+		t.Errorf("refine() = %q, want the last refined command", got)
+	}
+
+	if len(mock.prompts) != 3 {
+		t.Fatalf("expected 3 Generate calls, got %d", len(mock.prompts))
+	}
+
+	// The last prompt carries the original request and the full refinement history
+	last := mock.prompts[2]
+	for _, want := range []string{
+		"Original request: archive the logs dir",
+		"Command: zip -r logs.zip logs\nRefinement: use gzip not zip",
+		"Command: tar czf logs.tar.gz logs\nRefinement: add verbose",
+	} {
+		if !strings.Contains(last, want) {
+			t.Errorf("last prompt missing %q:\n%s", want, last)
+		}
+	}
+
+	output := out.String()
+	for _, want := range []string{
+		"Current command: zip -r logs.zip logs",
+		"Current command: tar czf logs.tar.gz logs",
+		"Current command: tar czvf logs.tar.gz logs",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestRefine_EndStates(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      string
+		wantCalls int
+	}{
+		{"enter accepts the first command", "\n", "ls -la", 1},
+		{"EOF accepts the current command", "show hidden files too\n", "ls -la", 2},
+		{"exit cancels", "/exit\n", "", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &scriptedProvider{responses: []string{"ls -la"}}
+			streams, in, _ := llm.TestIOStreamsNonInteractive()
+			in.WriteString(tt.input)
+
+			got, err := refine(context.Background(), streams, mock, "list files", &config.ProviderConfig{}, Options{})
+			if err != nil {
+				t.Fatalf("refine() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("refine() = %q, want %q", got, tt.want)
+			}
+			if len(mock.prompts) != tt.wantCalls {
+				t.Errorf("expected %d Generate calls, got %d", tt.wantCalls, len(mock.prompts))
+			}
+		})
+	}
+}
+
+func TestRefine_WithholdsDangerousCommands(t *testing.T) {
+	mock := &scriptedProvider{responses: []string{"rm -rf ./build", "rm -r ./build"}}
+	streams, in, out := llm.TestIOStreamsNonInteractive()
+	in.WriteString("don't force it\n\n")
+
+	got, err := refine(context.Background(), streams, mock, "delete build", &config.ProviderConfig{}, Options{})
+	if err != nil {
+		t.Fatalf("refine() error = %v", err)
+	}
+	if got != "rm -r ./build" {
+		t.Errorf("refine() = %q", got)
+	}
+
+	output := out.String()
+	if strings.Contains(output, "rm -rf") {
+		t.Errorf("dangerous command should be withheld:\n%s", output)
+	}
+	if !strings.Contains(output, "[dangerous, withheld]") || !strings.Contains(output, "Current command [caution]: rm -r ./build") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+}
+
+func TestRefine_UsesInteractiveProvider(t *testing.T) {
+	mock := &scriptedInteractiveProvider{}
+	streams, _, _ := llm.TestIOStreams()
+
+	got, err := refine(context.Background(), streams, mock, "find large files", &config.ProviderConfig{}, Options{HandOff: true})
+	if err != nil {
+		t.Fatalf("refine() error = %v", err)
+	}
+	if got != "" {
+		t.Errorf("refine() = %q, want empty for interactive sessions", got)
+	}
+	if !strings.Contains(mock.interactivePrompt, "User's Request: find large files") {
+		t.Errorf("interactive prompt missing request: %q", mock.interactivePrompt)
+	}
+	if len(mock.prompts) != 0 {
+		t.Errorf("expected no Generate calls, got %d", len(mock.prompts))
+	}
+}
+
+func TestRefine_InteractiveProviderWithoutHandOff(t *testing.T) {
+	mock := &scriptedInteractiveProvider{scriptedProvider: scriptedProvider{responses: []string{"rm -rf /"}}}
+	streams, in, out := llm.TestIOStreams()
+	in.WriteString("\n")
+
+	got, err := refine(context.Background(), streams, mock, "free disk space", &config.ProviderConfig{}, Options{})
+	if err != nil {
+		t.Fatalf("refine() error = %v", err)
+	}
+	if got != "rm -rf /" {
+		t.Errorf("refine() = %q, want the accepted command", got)
+	}
+	if mock.interactivePrompt != "" {
+		t.Error("refine() handed the session to the CLI without HandOff")
+	}
+	if !strings.Contains(out.String(), "[dangerous, withheld]") {
+		t.Errorf("expected the candidate to be risk-checked:\n%s", out.String())
+	}
+}
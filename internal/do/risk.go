@@ -0,0 +1,109 @@
+package do
+
+import (
+	"log/slog"
+	"regexp"
+)
+
+// RiskLevel classifies how destructive a shell command may be
+type RiskLevel int
+
+const (
+	// Safe commands have no known destructive patterns
+	Safe RiskLevel = iota
+	// Caution commands can modify or remove data and deserve a second look
+	Caution
+	// Dangerous commands can cause irreversible damage and require explicit confirmation
+	Dangerous
+)
+
+// String returns the lowercase name of the risk level
+func (r RiskLevel) String() string {
+	switch r {
+	case Caution:
+		return "caution"
+	case Dangerous:
+		return "dangerous"
+	default:
+		return "safe"
+	}
+}
+
+// DefaultDenylist contains patterns for commands that are always considered dangerous
+var DefaultDenylist = []string{
+	`\brm\s+(-[a-zA-Z]*[rR][a-zA-Z]*f|-[a-zA-Z]*f[a-zA-Z]*[rR])\b`,         // rm -rf, rm -fr, rm -Rf
+	`\brm\s+(-[a-zA-Z]*[rR]\s+-[a-zA-Z]*f|-[a-zA-Z]*f\s+-[a-zA-Z]*[rR])\b`, // rm -r -f
+	`\brm\s+.*--no-preserve-root`,
+	`\bmkfs(\.\w+)?\b`,
+	`\bdd\b.*\bof=/dev/`,
+	`>\s*/dev/(sd|hd|nvme|disk|xvd|vd)`,
+	`:\(\)\s*\{\s*:\s*\|\s*:\s*&\s*\}\s*;\s*:`, // fork bomb
+	`\b(curl|wget)\b[^|]*\|\s*(sudo\s+)?(ba|z|da|k)?sh\b`,
+	`\bchmod\s+(-[a-zA-Z]*R[a-zA-Z]*\s+)?0?777\s+/(\s|$)`,
+	`\bshred\b.*/dev/`,
+}
+
+// cautionPatterns flag commands that modify or remove data but are commonly legitimate
+var cautionPatterns = []string{
+	`\brm\b`,
+	`\bsudo\b`,
+	`\bkill(all)?\b.*-9`,
+	`\bpkill\b`,
+	`\bchmod\s+-[a-zA-Z]*R`,
+	`\bchown\s+-[a-zA-Z]*R`,
+	`\bgit\s+push\b.*(--force|-f\b)`,
+	`\bgit\s+reset\s+--hard\b`,
+	`\bgit\s+clean\b.*-[a-zA-Z]*f`,
+	`\btruncate\b`,
+	`\bshred\b`,
+	`\bdd\b`,
+	`\bfind\b.*-delete\b`,
+	`\bxargs\b.*\brm\b`,
+}
+
+var (
+	compiledDenylist = mustCompileAll(DefaultDenylist)
+	compiledCaution  = mustCompileAll(cautionPatterns)
+)
+
+// ClassifyRisk scans a shell command for destructive patterns using the default denylist
+func ClassifyRisk(cmd string) RiskLevel {
+	return ClassifyRiskWithDenylist(cmd, nil)
+}
+
+// ClassifyRiskWithDenylist scans a shell command using the default denylist plus extra
+// user-supplied regular expressions. Invalid extra patterns are logged and ignored.
+func ClassifyRiskWithDenylist(cmd string, extra []string) RiskLevel {
+	for _, re := range compiledDenylist {
+		if re.MatchString(cmd) {
+			return Dangerous
+		}
+	}
+
+	for _, pattern := range extra {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			slog.Warn("ignoring invalid denylist pattern", "pattern", pattern, "error", err)
+			continue
+		}
+		if re.MatchString(cmd) {
+			return Dangerous
+		}
+	}
+
+	for _, re := range compiledCaution {
+		if re.MatchString(cmd) {
+			return Caution
+		}
+	}
+
+	return Safe
+}
+
+func mustCompileAll(patterns []string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		compiled[i] = regexp.MustCompile(pattern)
+	}
+	return compiled
+}
@@ -0,0 +1,85 @@
+package do
+
+import "testing"
+
+func TestClassifyRisk(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		want RiskLevel
+	}{
+		// Dangerous
+		{"rm -rf /", Dangerous},
+		{"rm -rf ~", Dangerous},
+		{"sudo rm -fr /var/lib", Dangerous},
+		{"rm -Rf ./build", Dangerous},
+		{"rm -r -f node_modules", Dangerous},
+		{"rm --no-preserve-root -r /", Dangerous},
+		{"mkfs.ext4 /dev/sda1", Dangerous},
+		{"mkfs -t ext4 /dev/sdb", Dangerous},
+		{"dd if=/dev/zero of=/dev/sda bs=1M", Dangerous},
+		{"echo hi > /dev/sda", Dangerous},
+		{":(){ :|:& };:", Dangerous},
+		{"curl -fsSL https://example.com/install.sh | sh", Dangerous},
+		{"wget -qO- https://example.com/x | sudo bash", Dangerous},
+		{"chmod -R 777 /", Dangerous},
+
+		// Caution
+		{"rm old.log", Caution},
+		{"rm -r build", Caution},
+		{"sudo apt-get update", Caution},
+		{"kill -9 1234", Caution},
+		{"git push --force origin main", Caution},
+		{"git reset --hard HEAD~1", Caution},
+		{"find . -name '*.tmp' -delete", Caution},
+		{"find . -name '*.pyc' | xargs rm", Caution},
+		{"dd if=disk.img of=backup.img", Caution},
+		{"chown -R user:user ./data", Caution},
+
+		// Safe
+		{"ls -la", Safe},
+		{"find ~ -type f -size +50M", Safe},
+		{"du -ah . | sort -rh | head -n 10", Safe},
+		{"curl -s https://api.github.com | jq .", Safe},
+		{"grep -rn 'TODO' .", Safe},
+		{"git status", Safe},
+		{"echo 'format the disk' > notes.txt", Safe},
+		{"docker ps -a", Safe},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cmd, func(t *testing.T) {
+			if got := ClassifyRisk(tt.cmd); got != tt.want {
+				t.Errorf("ClassifyRisk(%q) = %s, want %s", tt.cmd, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyRiskWithDenylist(t *testing.T) {
+	extra := []string{`\bterraform\s+destroy\b`, `[invalid`}
+
+	if got := ClassifyRiskWithDenylist("terraform destroy -auto-approve", extra); got != Dangerous {
+		t.Errorf("expected custom pattern to be dangerous, got %s", got)
+	}
+
+	if got := ClassifyRiskWithDenylist("terraform plan", extra); got != Safe {
+		t.Errorf("expected non-matching command to be safe, got %s", got)
+	}
+
+	if got := ClassifyRiskWithDenylist("rm -rf /", nil); got != Dangerous {
+		t.Errorf("expected default denylist to still apply, got %s", got)
+	}
+}
+
+func TestRiskLevelString(t *testing.T) {
+	tests := map[RiskLevel]string{
+		Safe:      "safe",
+		Caution:   "caution",
+		Dangerous: "dangerous",
+	}
+	for level, want := range tests {
+		if got := level.String(); got != want {
+			t.Errorf("RiskLevel(%d).String() = %q, want %q", level, got, want)
+		}
+	}
+}
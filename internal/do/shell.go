@@ -0,0 +1,103 @@
+package do
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Shells whose syntax the do prompts can target
+const (
+	ShellBash       = "bash"
+	ShellZsh        = "zsh"
+	ShellFish       = "fish"
+	ShellPowerShell = "powershell"
+)
+
+// DefaultShell is used when the shell is not given and cannot be detected
+const DefaultShell = ShellBash
+
+// Shells lists the supported shell names
+var Shells = []string{ShellBash, ShellZsh, ShellFish, ShellPowerShell}
+
+// shellProfile holds the prompt fragments that vary by shell
+type shellProfile struct {
+	// platform completes "You are a shell command expert for ..."
+	platform string
+	// syntax is the requirement describing the shell's syntax
+	syntax string
+	// examples are few-shot request/command pairs written for the shell
+	examples string
+}
+
+const unixPlatform = "Unix-like systems (Linux, macOS)"
+
+const posixExamples = `User: "find all files larger than 50MB in my home directory"
+Output: find ~ -type f -size +50M
+
+User: "list the 10 largest files in the current directory"
+Output: du -ah . | sort -rh | head -n 10
+
+User: "kill the process listening on port 3000"
+Output: fuser -k 3000/tcp`
+
+var shellProfiles = map[string]shellProfile{
+	ShellBash: {
+		platform: unixPlatform + " using bash",
+		syntax:   "Write bash syntax, preferring POSIX-compliant commands when possible. For process killing, prefer safer methods like fuser over kill with lsof",
+		examples: posixExamples,
+	},
+	ShellZsh: {
+		platform: unixPlatform + " using zsh",
+		syntax:   "Write zsh syntax, preferring POSIX-compliant commands when possible. For process killing, prefer safer methods like fuser over kill with lsof",
+		examples: posixExamples,
+	},
+	ShellFish: {
+		platform: unixPlatform + " using fish",
+		syntax:   "Write fish syntax: set VAR value instead of VAR=value or export, (cmd) instead of $(cmd), and no bash-only constructs such as [[ ]] or heredocs",
+		examples: posixExamples,
+	},
+	ShellPowerShell: {
+		platform: "PowerShell (Windows, Linux, macOS)",
+		syntax:   "Write PowerShell syntax using cmdlets and the object pipeline (Get-ChildItem, Where-Object, Stop-Process); do not use bash syntax or Unix-only utilities",
+		examples: `User: "find all files larger than 50MB in my home directory"
+Output: Get-ChildItem ~ -Recurse -File | Where-Object Length -gt 50MB
+
+User: "list the 10 largest files in the current directory"
+Output: Get-ChildItem -File | Sort-Object Length -Descending | Select-Object -First 10
+
+User: "kill the process listening on port 3000"
+Output: Get-NetTCPConnection -LocalPort 3000 | ForEach-Object { Stop-Process -Id $_.OwningProcess }`,
+	},
+}
+
+// ValidateShell reports an error for shells other than those in Shells
+func ValidateShell(shell string) error {
+	if _, ok := shellProfiles[shell]; !ok {
+		return fmt.Errorf("unsupported shell %q (expected one of %s)", shell, strings.Join(Shells, ", "))
+	}
+	return nil
+}
+
+// DetectShell returns the supported shell named by a $SHELL value such as /usr/bin/fish,
+// or DefaultShell when it is empty or not supported
+func DetectShell(shellEnv string) string {
+	name := strings.TrimSuffix(filepath.Base(shellEnv), ".exe")
+	switch name {
+	case "pwsh", ShellPowerShell:
+		return ShellPowerShell
+	case ShellBash, ShellZsh, ShellFish:
+		return name
+	default:
+		return DefaultShell
+	}
+}
+
+// resolveShell returns shell and its prompt fragments, using DefaultShell when shell is empty
+// or unsupported
+func resolveShell(shell string) (string, shellProfile) {
+	if profile, ok := shellProfiles[shell]; ok {
+		return shell, profile
+	}
+	return DefaultShell, shellProfiles[DefaultShell]
+}
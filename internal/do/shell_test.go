@@ -0,0 +1,76 @@
+package do
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/connorhough/smix/internal/config"
+)
+
+func TestTranslate_ShellPrompt(t *testing.T) {
+	tests := []struct {
+		shell   string
+		want    []string
+		notWant string
+	}{
+		{shell: ShellBash, want: []string{"secure bash command", "POSIX-compliant", "find ~ -type f -size +50M"}},
+		{shell: ShellZsh, want: []string{"secure zsh command", "Write zsh syntax"}},
+		{shell: ShellFish, want: []string{"secure fish command", "Write fish syntax", "set VAR value"}},
+		{shell: ShellPowerShell, want: []string{"secure powershell command", "Write PowerShell syntax", "Get-ChildItem"}, notWant: "fuser -k"},
+		{shell: "", want: []string{"secure bash command"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			mock := &mockProvider{response: "ls"}
+			if _, err := translate(context.Background(), mock, "list files", &config.ProviderConfig{}, Options{Shell: tt.shell}); err != nil {
+				t.Fatalf("translate() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(mock.lastPrompt, want) {
+					t.Errorf("prompt missing %q:\n%s", want, mock.lastPrompt)
+				}
+			}
+			if tt.notWant != "" && strings.Contains(mock.lastPrompt, tt.notWant) {
+				t.Errorf("prompt should not contain %q:\n%s", tt.notWant, mock.lastPrompt)
+			}
+			if !strings.HasSuffix(mock.lastPrompt, "User's Request: list files") {
+				t.Errorf("prompt should end with the request:\n%s", mock.lastPrompt)
+			}
+		})
+	}
+}
+
+func TestDetectShell(t *testing.T) {
+	tests := []struct {
+		env  string
+		want string
+	}{
+		{"/bin/bash", ShellBash},
+		{"/usr/bin/zsh", ShellZsh},
+		{"/opt/homebrew/bin/fish", ShellFish},
+		{"/usr/local/bin/pwsh", ShellPowerShell},
+		{"/bin/sh", DefaultShell},
+		{"", DefaultShell},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			if got := DetectShell(tt.env); got != tt.want {
+				t.Errorf("DetectShell(%q) = %q, want %q", tt.env, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateShell(t *testing.T) {
+	for _, shell := range Shells {
+		if err := ValidateShell(shell); err != nil {
+			t.Errorf("ValidateShell(%q) error = %v", shell, err)
+		}
+	}
+	if err := ValidateShell("tcsh"); err == nil {
+		t.Error("ValidateShell(\"tcsh\") should fail")
+	}
+}
@@ -4,63 +4,175 @@ package do
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"os"
+	"strings"
 
 	"github.com/connorhough/smix/internal/config"
 	"github.com/connorhough/smix/internal/llm"
 	"github.com/connorhough/smix/internal/providers"
 )
 
-const promptTemplate = `You are a shell command expert for Unix-like systems (Linux, macOS).
-Your sole purpose is to translate the user's request into a single, functional, and secure shell command.
+// promptTemplate is filled with the shell's platform, name, syntax requirement and examples,
+// then the user's request
+const promptTemplate = `You are a shell command expert for %[1]s.
+Your sole purpose is to translate the user's request into a single, functional, and secure %[2]s command.
 
 Requirements:
 1. Output ONLY the raw command with no explanations, preambles, or markdown formatting
 2. Ensure commands are safe and won't cause damage to the system
-3. Prefer POSIX-compliant commands when possible
+3. %[3]s
 4. For complex tasks, chain commands with pipes and logical operators
 5. Handle errors gracefully within the command (e.g., using || for fallbacks)
 6. Use absolute paths when necessary
-7. For process killing, prefer safer methods like fuser over kill with lsof
-8. Commands should be one-liners that can be directly executed or piped
+7. Commands should be one-liners that can be directly executed or piped
 
 Examples:
-User: "find all files larger than 50MB in my home directory"
-Output: find ~ -type f -size +50M
+%[4]s
 
-User: "list the 10 largest files in the current directory"
-Output: du -ah . | sort -rh | head -n 10
+User's Request: %[5]s`
 
-User: "kill the process listening on port 3000"
-Output: fuser -k 3000/tcp
+// jsonPromptSuffix replaces the raw-output requirement when structured output is requested
+const jsonPromptSuffix = `
 
-User's Request: %s`
+Respond with a JSON object of the form {"command": "<shell command>"} and nothing else.`
+
+// commandStop ends plain responses at the first blank line when Options.StopAtBlankLine is set,
+// dropping any explanation the model adds after the command despite the prompt. Code fences
+// contain no blank line, so they survive.
+var commandStop = []string{"\n\n"}
+
+// commandSchema is the JSON Schema for structured translate responses
+var commandSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"command": map[string]any{"type": "string"},
+	},
+	"required": []string{"command"},
+}
+
+// Options configures a translation
+type Options struct {
+	// Shell selects the shell whose syntax commands are written in (DefaultShell when empty)
+	Shell string
+
+	// JSON requests structured output from the provider so the command can be
+	// separated reliably from any stray text
+	JSON bool
+
+	// StopAtBlankLine ends plain responses at the first blank line. Only suitable when a
+	// single-line command is expected: a multi-line command (e.g. a script with blank lines
+	// between steps) is cut short.
+	StopAtBlankLine bool
+
+	// HandOff lets Refine hand the session to an interactive provider's own CLI when stdin is a
+	// terminal. The CLI shows commands itself, so they are not risk-checked and none is returned.
+	HandOff bool
+
+	// Denylist holds extra dangerous-command patterns used to flag commands shown during refinement
+	Denylist []string
+	// AllowDangerous shows dangerous commands during refinement instead of withholding them
+	AllowDangerous bool
+}
 
 // Translate converts natural language to shell commands
-func Translate(ctx context.Context, taskDescription string, cfg *config.ProviderConfig) (string, error) {
-	slog.Debug("do command config: provider=%s, model=%s", cfg.Provider, cfg.Model)
+func Translate(ctx context.Context, taskDescription string, cfg *config.ProviderConfig, opts Options) (string, error) {
+	slog.Debug("do command config", "provider", cfg.Provider, "model", cfg.Model)
 
-	provider, err := providers.GetProvider(ctx, cfg.Provider)
+	provider, err := providers.GetProviderWithFallback(ctx, cfg, os.Stderr)
 	if err != nil {
 		return "", fmt.Errorf("failed to get provider: %w", err)
 	}
+	if err := providers.CheckModel(provider, cfg); err != nil {
+		return "", err
+	}
 
+	return translate(ctx, provider, taskDescription, cfg, opts)
+}
+
+// translate runs the translation against an already resolved provider
+func translate(ctx context.Context, provider llm.Provider, taskDescription string, cfg *config.ProviderConfig, opts Options) (string, error) {
 	slog.Debug("using provider", "name", provider.Name())
 
-	prompt := fmt.Sprintf(promptTemplate, taskDescription)
+	prompt := BuildPrompt(taskDescription, opts)
 	slog.Debug("prompt constructed", "length", len(prompt))
 
 	// Generate response
-	var opts []llm.Option
+	genOpts := generateOptions(cfg)
 	resolvedModel := cfg.Model
 	if resolvedModel == "" {
 		resolvedModel = provider.DefaultModel()
-	} else {
-		opts = append(opts, llm.WithModel(resolvedModel))
+	}
+	if opts.JSON {
+		genOpts = append(genOpts, llm.WithJSONSchema(commandSchema))
+	} else if opts.StopAtBlankLine {
+		genOpts = append(genOpts, llm.WithStopSequences(commandStop))
 	}
 
 	slog.Debug("resolved model", "model", resolvedModel)
+	if tokens, limit, fits := llm.CheckPromptSize(prompt, resolvedModel); !fits {
+		slog.Warn("prompt may exceed the model's context window", "estimated_tokens", tokens, "limit", limit, "model", resolvedModel)
+	}
+
+	stats := &llm.RetryStats{}
+	response, err := provider.Generate(llm.WithRetryStats(ctx, stats), prompt, genOpts...)
+	if err != nil {
+		return "", err
+	}
+	if stats.Retries > 0 {
+		slog.Debug(stats.Summary(), "attempts", stats.Attempts)
+	}
+
+	if !opts.JSON {
+		return stripCodeFences(response), nil
+	}
+
+	return parseCommandJSON(response)
+}
+
+// BuildPrompt returns the exact prompt Translate would send for taskDescription, without
+// resolving a provider
+func BuildPrompt(taskDescription string, opts Options) string {
+	shell, profile := resolveShell(opts.Shell)
+	prompt := fmt.Sprintf(promptTemplate, profile.platform, shell, profile.syntax, profile.examples, taskDescription)
+	if opts.JSON {
+		prompt += jsonPromptSuffix
+	}
+	return prompt
+}
+
+// generateOptions returns the provider options shared by every request for cfg
+func generateOptions(cfg *config.ProviderConfig) []llm.Option {
+	var opts []llm.Option
+	if cfg.Model != "" {
+		opts = append(opts, llm.WithModel(cfg.Model))
+	}
+	if cfg.Retries != nil {
+		opts = append(opts, llm.WithMaxRetries(*cfg.Retries))
+	}
+	return opts
+}
+
+// parseCommandJSON extracts the command field from a JSON response, tolerating surrounding prose
+func parseCommandJSON(response string) (string, error) {
+	raw, err := llm.ExtractJSON(response)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse structured response: %w", err)
+	}
+
+	var result struct {
+		Command string `json:"command"`
+	}
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		return "", fmt.Errorf("failed to parse structured response: %w", err)
+	}
+
+	command := strings.TrimSpace(result.Command)
+	if command == "" {
+		return "", fmt.Errorf("structured response did not include a command")
+	}
 
-	return provider.Generate(ctx, prompt, opts...)
+	return command, nil
 }
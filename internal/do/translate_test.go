@@ -0,0 +1,179 @@
+package do
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
+)
+
+// mockProvider returns a canned response and records the last call
+type mockProvider struct {
+	response   string
+	lastPrompt string
+	lastOpts   *llm.GenerateOptions
+}
+
+func (m *mockProvider) Generate(ctx context.Context, prompt string, opts ...llm.Option) (string, error) {
+	m.lastPrompt = prompt
+	m.lastOpts = llm.BuildOptions(opts)
+	return m.response, nil
+}
+
+func (m *mockProvider) ValidateModel(model string) error { return nil }
+func (m *mockProvider) DefaultModel() string             { return "mock-model" }
+func (m *mockProvider) Name() string                     { return "mock" }
+
+func TestTranslate_JSONMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+	}{
+		{"bare JSON", `{"command": "ls -la"}`, "ls -la"},
+		{"wrapped in prose", "Sure, here it is:\n{\"command\": \"du -ah . | sort -rh | head -n 10\"}\nThis lists the largest files.", "du -ah . | sort -rh | head -n 10"},
+		{"fenced JSON", "```json\n{\"command\": \"fuser -k 3000/tcp\"}\n```", "fuser -k 3000/tcp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockProvider{response: tt.response}
+			got, err := translate(context.Background(), mock, "task", &config.ProviderConfig{}, Options{JSON: true})
+			if err != nil {
+				t.Fatalf("translate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("translate() = %q, want %q", got, tt.want)
+			}
+			if !mock.lastOpts.JSONMode || mock.lastOpts.JSONSchema == nil {
+				t.Error("expected JSON mode with schema to be requested")
+			}
+			if !strings.Contains(mock.lastPrompt, `{"command":`) {
+				t.Error("expected prompt to describe the JSON shape")
+			}
+		})
+	}
+}
+
+func TestTranslate_JSONModeInvalid(t *testing.T) {
+	for _, response := range []string{"ls -la", `{"cmd": "ls"}`} {
+		mock := &mockProvider{response: response}
+		if _, err := translate(context.Background(), mock, "task", &config.ProviderConfig{}, Options{JSON: true}); err == nil {
+			t.Errorf("expected error for response %q", response)
+		}
+	}
+}
+
+func TestTranslate_PlainMode(t *testing.T) {
+	mock := &mockProvider{response: "ls -la"}
+	got, err := translate(context.Background(), mock, "list files", &config.ProviderConfig{Model: "sonnet"}, Options{})
+	if err != nil {
+		t.Fatalf("translate() error = %v", err)
+	}
+	if got != "ls -la" {
+		t.Errorf("translate() = %q, want %q", got, "ls -la")
+	}
+	if mock.lastOpts.JSONMode {
+		t.Error("did not expect JSON mode")
+	}
+	if mock.lastOpts.Model != "sonnet" {
+		t.Errorf("model = %q, want %q", mock.lastOpts.Model, "sonnet")
+	}
+	if len(mock.lastOpts.StopSequences) != 0 {
+		t.Errorf("StopSequences = %q, want none by default", mock.lastOpts.StopSequences)
+	}
+}
+
+func TestTranslate_StopAtBlankLine(t *testing.T) {
+	multiLine := "for f in *.log; do\n  gzip \"$f\"\ndone\n\nls *.gz"
+
+	tests := []struct {
+		name     string
+		opts     Options
+		response string
+		want     string
+		wantStop []string
+	}{
+		{name: "multi-line command kept by default", opts: Options{}, response: multiLine, want: multiLine},
+		{name: "opt-in stop", opts: Options{StopAtBlankLine: true}, response: "ls -la", want: "ls -la", wantStop: []string{"\n\n"}},
+		{name: "ignored with JSON", opts: Options{JSON: true, StopAtBlankLine: true}, response: `{"command": "ls"}`, want: "ls"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockProvider{response: tt.response}
+			got, err := translate(context.Background(), mock, "compress logs", &config.ProviderConfig{}, tt.opts)
+			if err != nil {
+				t.Fatalf("translate() error = %v", err)
+			}
+			if strings.Join(mock.lastOpts.StopSequences, ",") != strings.Join(tt.wantStop, ",") {
+				t.Errorf("StopSequences = %q, want %q", mock.lastOpts.StopSequences, tt.wantStop)
+			}
+			if got != tt.want {
+				t.Errorf("translate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslate_StripsCodeFences(t *testing.T) {
+	mock := &mockProvider{response: "```bash\nls -la\n```"}
+	got, err := translate(context.Background(), mock, "list files", &config.ProviderConfig{}, Options{})
+	if err != nil {
+		t.Fatalf("translate() error = %v", err)
+	}
+	if got != "ls -la" {
+		t.Errorf("translate() = %q, want %q", got, "ls -la")
+	}
+}
+
+func TestTranslate_ForwardsRetries(t *testing.T) {
+	mock := &mockProvider{response: "ls -la"}
+
+	if _, err := translate(context.Background(), mock, "list files", &config.ProviderConfig{}, Options{}); err != nil {
+		t.Fatalf("translate() error = %v", err)
+	}
+	if mock.lastOpts.MaxRetries != nil {
+		t.Errorf("expected no retry override, got %d", *mock.lastOpts.MaxRetries)
+	}
+
+	retries := 0
+	if _, err := translate(context.Background(), mock, "list files", &config.ProviderConfig{Retries: &retries}, Options{}); err != nil {
+		t.Fatalf("translate() error = %v", err)
+	}
+	if got := mock.lastOpts.Retries(); got != 0 {
+		t.Errorf("Retries() = %d, want 0", got)
+	}
+}
+
+func TestBuildPrompt(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{name: "plain", want: []string{"User's Request: list files", "Output ONLY the raw command"}},
+		{name: "JSON", opts: Options{JSON: true}, want: []string{"User's Request: list files", `{"command": "<shell command>"}`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildPrompt("list files", tt.opts)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("BuildPrompt() missing %q:\n%s", want, got)
+				}
+			}
+
+			mock := &mockProvider{response: `{"command": "ls"}`}
+			if _, err := translate(context.Background(), mock, "list files", &config.ProviderConfig{}, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			if mock.lastPrompt != got {
+				t.Errorf("BuildPrompt() differs from the sent prompt:\n%s\n---\n%s", got, mock.lastPrompt)
+			}
+		})
+	}
+}
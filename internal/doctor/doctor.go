@@ -0,0 +1,144 @@
+// Package doctor diagnoses provider availability and configuration problems.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/llm/claude"
+	"github.com/connorhough/smix/internal/llm/gemini"
+	"github.com/connorhough/smix/internal/providers"
+)
+
+// pingTimeout bounds each provider's test generation
+const pingTimeout = 30 * time.Second
+
+// Commands lists the commands whose resolved provider configuration is checked
+var Commands = []string{"ask", "do", "pr"}
+
+// requirement describes what a provider needs from the environment
+type requirement struct {
+	cli     string
+	envVars []string
+}
+
+var requirements = map[string]requirement{
+	claude.ProviderClaude: {cli: "claude", envVars: []string{claude.APIKeyEnvVar}},
+	gemini.ProviderGemini: {cli: "gemini", envVars: []string{gemini.APIKeyEnvVar}},
+}
+
+// ProviderGetter returns a provider by name (providers.GetProvider in production)
+type ProviderGetter func(ctx context.Context, name string) (llm.Provider, error)
+
+// Checker runs diagnostics against a set of providers
+type Checker struct {
+	GetProvider ProviderGetter
+	LookPath    func(file string) (string, error)
+	Getenv      func(key string) string
+	Out         io.Writer
+	// Style colors headings and statuses; the zero value writes plain text
+	Style llm.Styler
+}
+
+// NewChecker creates a Checker using the global provider factory and real environment
+func NewChecker(out io.Writer) *Checker {
+	return &Checker{
+		GetProvider: providers.GetProvider,
+		LookPath:    exec.LookPath,
+		Getenv:      os.Getenv,
+		Out:         out,
+	}
+}
+
+// Run checks every registered provider and the resolved config for each command.
+// Returns an error if the provider configured for any command is unusable.
+func (c *Checker) Run(ctx context.Context) error {
+	fmt.Fprintln(c.Out, c.Style.Bold("Providers:"))
+
+	healthy := make(map[string]error)
+	for _, name := range providers.Names() {
+		healthy[name] = c.checkProvider(ctx, name)
+	}
+
+	fmt.Fprintln(c.Out)
+	fmt.Fprintln(c.Out, c.Style.Bold("Commands:"))
+
+	var unusable []string
+	for _, command := range Commands {
+		cfg := config.ResolveProviderConfig(command)
+		model := cfg.Model
+		if model == "" {
+			model = "(provider default)"
+		}
+
+		err, known := healthy[cfg.Provider]
+		if !known {
+			err = c.checkProvider(ctx, cfg.Provider)
+			healthy[cfg.Provider] = err
+		}
+
+		status := c.Style.Green("OK  ")
+		if err != nil {
+			status = c.Style.Red("FAIL")
+			unusable = append(unusable, command)
+		}
+		fmt.Fprintf(c.Out, "  %s %-5s provider=%s model=%s\n", status, command, cfg.Provider, model)
+	}
+
+	if len(unusable) > 0 {
+		return fmt.Errorf("configured provider is unusable for: %s", strings.Join(unusable, ", "))
+	}
+
+	return nil
+}
+
+// checkProvider reports environment requirements and attempts a tiny generation
+func (c *Checker) checkProvider(ctx context.Context, name string) error {
+	fmt.Fprintf(c.Out, "  %s\n", c.Style.Bold(name))
+
+	if req, ok := requirements[name]; ok {
+		if path, err := c.LookPath(req.cli); err == nil {
+			fmt.Fprintf(c.Out, "    cli:  %s found at %s\n", req.cli, path)
+		} else {
+			fmt.Fprintf(c.Out, "    cli:  %s not found on PATH\n", req.cli)
+		}
+
+		envVars := append([]string(nil), req.envVars...)
+		sort.Strings(envVars)
+		for _, key := range envVars {
+			state := "set"
+			if c.Getenv(key) == "" {
+				state = "not set"
+			}
+			fmt.Fprintf(c.Out, "    env:  %s %s\n", key, state)
+		}
+	}
+
+	provider, err := c.GetProvider(ctx, name)
+	if err != nil {
+		fmt.Fprintf(c.Out, "    %s [%s] %v\n", c.Style.Red("FAIL"), llm.KindOf(err), err)
+		return err
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	if _, err := provider.Generate(pingCtx, "ping"); err != nil {
+		fmt.Fprintf(c.Out, "    %s [%s] %v\n", c.Style.Red("FAIL"), llm.KindOf(err), err)
+		return err
+	}
+
+	fmt.Fprintf(c.Out, "    %s default model %s\n", c.Style.Green("OK  "), provider.DefaultModel())
+	if caps := llm.CapabilitiesOf(provider).Names(); len(caps) > 0 {
+		fmt.Fprintf(c.Out, "    caps: %s\n", strings.Join(caps, ", "))
+	}
+	return nil
+}
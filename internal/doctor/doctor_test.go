@@ -0,0 +1,121 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/spf13/viper"
+)
+
+type mockProvider struct {
+	name string
+	err  error
+}
+
+func (m *mockProvider) Generate(ctx context.Context, prompt string, opts ...llm.Option) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	return "pong", nil
+}
+
+func (m *mockProvider) ValidateModel(model string) error { return nil }
+func (m *mockProvider) DefaultModel() string             { return m.name + "-default" }
+func (m *mockProvider) Name() string                     { return m.name }
+
+// interactiveMockProvider adds InteractiveProvider support to mockProvider
+type interactiveMockProvider struct {
+	mockProvider
+}
+
+func (m *interactiveMockProvider) RunInteractive(ctx context.Context, streams *llm.IOStreams, prompt string, opts ...llm.Option) error {
+	return nil
+}
+
+func newTestChecker(out *bytes.Buffer) *Checker {
+	mocks := map[string]llm.Provider{
+		"claude": &interactiveMockProvider{mockProvider{name: "claude"}},
+		"gemini": &mockProvider{name: "gemini", err: llm.ErrAuthenticationFailed("gemini", errors.New("bad key"))},
+	}
+
+	return &Checker{
+		GetProvider: func(ctx context.Context, name string) (llm.Provider, error) {
+			if p, ok := mocks[name]; ok {
+				return p, nil
+			}
+			return nil, errors.New("unknown provider: " + name)
+		},
+		LookPath: func(file string) (string, error) {
+			if file == "claude" {
+				return "/usr/bin/claude", nil
+			}
+			return "", errors.New("not found")
+		},
+		Getenv: func(key string) string { return "" },
+		Out:    out,
+	}
+}
+
+func TestCheckerRun_AllCommandsHealthy(t *testing.T) {
+	viper.Reset()
+	viper.Set("provider", "claude")
+	viper.Set("model", "sonnet")
+
+	var out bytes.Buffer
+	if err := newTestChecker(&out).Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	output := out.String()
+	for _, want := range []string{
+		"cli:  claude found at /usr/bin/claude",
+		"cli:  gemini not found on PATH",
+		"env:  SMIX_GEMINI_API_KEY not set",
+		"OK   default model claude-default",
+		"caps: interactive",
+		"FAIL [authentication]",
+		"OK   ask   provider=claude model=sonnet",
+		"OK   pr    provider=claude model=sonnet",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestCheckerRun_UnusableCommandProvider(t *testing.T) {
+	viper.Reset()
+	viper.Set("provider", "claude")
+	viper.Set("commands.do.provider", "gemini")
+
+	var out bytes.Buffer
+	err := newTestChecker(&out).Run(context.Background())
+	if err == nil {
+		t.Fatal("expected error when a command's provider is unusable")
+	}
+	if !strings.Contains(err.Error(), "do") {
+		t.Errorf("expected error to name the do command, got %v", err)
+	}
+
+	output := out.String()
+	if !strings.Contains(output, "FAIL do    provider=gemini model=(provider default)") {
+		t.Errorf("expected do command to be reported as failing:\n%s", output)
+	}
+}
+
+func TestCheckerRun_UnknownProvider(t *testing.T) {
+	viper.Reset()
+	viper.Set("provider", "openai")
+
+	var out bytes.Buffer
+	if err := newTestChecker(&out).Run(context.Background()); err == nil {
+		t.Fatal("expected error for unknown configured provider")
+	}
+	if !strings.Contains(out.String(), "unknown provider: openai") {
+		t.Errorf("expected unknown provider to be reported:\n%s", out.String())
+	}
+}
@@ -0,0 +1,36 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+)
+
+// CandidateGenerator is an optional interface for providers that can sample several
+// answers to the same prompt in a single request (e.g. Gemini's candidate count).
+type CandidateGenerator interface {
+	// GenerateCandidates returns up to n answers to prompt
+	GenerateCandidates(ctx context.Context, prompt string, n int, opts ...Option) ([]string, error)
+}
+
+// GenerateN returns n answers to prompt, using the provider's native candidate support when
+// it implements CandidateGenerator and otherwise calling Generate n times in sequence
+func GenerateN(ctx context.Context, provider Provider, prompt string, n int, opts ...Option) ([]string, error) {
+	if generator, ok := provider.(CandidateGenerator); ok && n > 1 {
+		return generator.GenerateCandidates(ctx, prompt, n, opts...)
+	}
+	return GenerateSequential(ctx, provider, prompt, n, opts...)
+}
+
+// GenerateSequential calls Generate n times and returns the answers in order.
+// Providers use it to implement CandidateGenerator on backends without native support.
+func GenerateSequential(ctx context.Context, provider Provider, prompt string, n int, opts ...Option) ([]string, error) {
+	answers := make([]string, 0, n)
+	for i := range n {
+		answer, err := provider.Generate(ctx, prompt, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("answer %d of %d: %w", i+1, n, err)
+		}
+		answers = append(answers, answer)
+	}
+	return answers, nil
+}
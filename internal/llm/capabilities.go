@@ -0,0 +1,41 @@
+package llm
+
+// Capabilities reports which optional interfaces a provider implements
+type Capabilities struct {
+	// Interactive is set for providers implementing InteractiveProvider
+	Interactive bool `json:"interactive"`
+	// ListModels is set for providers implementing ModelLister
+	ListModels bool `json:"list_models"`
+	// ResolveModel is set for providers implementing ModelResolver
+	ResolveModel bool `json:"resolve_model"`
+	// Candidates is set for providers implementing CandidateGenerator
+	Candidates bool `json:"candidates"`
+}
+
+// CapabilitiesOf inspects provider for each optional interface
+func CapabilitiesOf(provider Provider) Capabilities {
+	var caps Capabilities
+	_, caps.Interactive = provider.(InteractiveProvider)
+	_, caps.ListModels = provider.(ModelLister)
+	_, caps.ResolveModel = provider.(ModelResolver)
+	_, caps.Candidates = provider.(CandidateGenerator)
+	return caps
+}
+
+// Names returns the short names of the supported capabilities in a stable order
+func (c Capabilities) Names() []string {
+	var names []string
+	if c.Interactive {
+		names = append(names, "interactive")
+	}
+	if c.ListModels {
+		names = append(names, "list-models")
+	}
+	if c.ResolveModel {
+		names = append(names, "model-aliases")
+	}
+	if c.Candidates {
+		names = append(names, "candidates")
+	}
+	return names
+}
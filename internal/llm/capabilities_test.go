@@ -0,0 +1,69 @@
+package llm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// basicProvider implements only Provider
+type basicProvider struct{}
+
+func (basicProvider) Generate(ctx context.Context, prompt string, opts ...Option) (string, error) {
+	return "", nil
+}
+func (basicProvider) ValidateModel(model string) error { return nil }
+func (basicProvider) DefaultModel() string             { return "basic-model" }
+func (basicProvider) Name() string                     { return "basic" }
+
+// candidateProvider adds CandidateGenerator to basicProvider
+type candidateProvider struct{ basicProvider }
+
+func (candidateProvider) GenerateCandidates(ctx context.Context, prompt string, n int, opts ...Option) ([]string, error) {
+	return nil, nil
+}
+
+func TestCapabilitiesOf(t *testing.T) {
+	tests := []struct {
+		name      string
+		provider  Provider
+		want      Capabilities
+		wantNames []string
+	}{
+		{
+			name:     "basic provider",
+			provider: basicProvider{},
+			want:     Capabilities{},
+		},
+		{
+			name:      "interactive provider",
+			provider:  &mockInteractiveProvider{},
+			want:      Capabilities{Interactive: true},
+			wantNames: []string{"interactive"},
+		},
+		{
+			name:      "model lister and resolver",
+			provider:  &listingProvider{models: []string{"a"}},
+			want:      Capabilities{ListModels: true, ResolveModel: true},
+			wantNames: []string{"list-models", "model-aliases"},
+		},
+		{
+			name:      "candidate generator",
+			provider:  candidateProvider{},
+			want:      Capabilities{Candidates: true},
+			wantNames: []string{"candidates"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CapabilitiesOf(tt.provider)
+			if got != tt.want {
+				t.Errorf("CapabilitiesOf() = %+v, want %+v", got, tt.want)
+			}
+			if names := got.Names(); !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("Names() = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
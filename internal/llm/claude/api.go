@@ -0,0 +1,138 @@
+package claude
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/connorhough/smix/internal/llm"
+)
+
+const (
+	// DefaultBaseURL is the Anthropic API endpoint
+	DefaultBaseURL = "https://api.anthropic.com"
+
+	apiVersion       = "2023-06-01"
+	defaultMaxTokens = 4096
+)
+
+type messagesRequest struct {
+	Model         string    `json:"model"`
+	MaxTokens     int       `json:"max_tokens"`
+	Messages      []message `json:"messages"`
+	StopSequences []string  `json:"stop_sequences,omitempty"`
+}
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type messagesResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+type apiErrorResponse struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// generateViaAPI sends the prompt to the Anthropic Messages API, retrying transient failures up to retries times.
+// Rate limits, server errors (including 529 overloaded) and network failures are retried; other
+// statuses such as 400, 401 and 404 cannot succeed on a retry and are returned at once.
+func (p *Provider) generateViaAPI(ctx context.Context, model, prompt string, stop []string, retries int) (string, error) {
+	apiModel := APIModelID(model)
+
+	body, err := json.Marshal(messagesRequest{
+		Model:         apiModel,
+		MaxTokens:     defaultMaxTokens,
+		Messages:      []message{{Role: "user", Content: prompt}},
+		StopSequences: stop,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode anthropic request: %w", err)
+	}
+
+	return llm.RetryWithBackoffN(ctx, retries, func(ctx context.Context) (string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/v1/messages", bytes.NewReader(body))
+		if err != nil {
+			return "", llm.Permanent(fmt.Errorf("failed to create anthropic request: %w", err))
+		}
+		req.Header.Set("content-type", "application/json")
+		req.Header.Set("x-api-key", p.apiKey)
+		req.Header.Set("anthropic-version", apiVersion)
+
+		resp, err := p.httpClient.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("anthropic API error: %w", err)
+		}
+		defer resp.Body.Close()
+
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", fmt.Errorf("failed to read anthropic response: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			err := wrapAPIError(resp.StatusCode, respBody, apiModel)
+			if !retryableStatus(resp.StatusCode) {
+				return "", llm.Permanent(err)
+			}
+			return "", err
+		}
+
+		var parsed messagesResponse
+		if err := json.Unmarshal(respBody, &parsed); err != nil {
+			return "", fmt.Errorf("failed to decode anthropic response: %w", err)
+		}
+
+		var result strings.Builder
+		for _, block := range parsed.Content {
+			if block.Type == "text" {
+				result.WriteString(block.Text)
+			}
+		}
+
+		output := strings.TrimSpace(result.String())
+		if output == "" {
+			return "", llm.ErrEmptyResponse(ProviderClaude, errors.New("anthropic API returned empty response"))
+		}
+
+		return output, nil
+	})
+}
+
+// wrapAPIError maps Anthropic API status codes to typed errors
+func wrapAPIError(status int, body []byte, model string) error {
+	msg := strings.TrimSpace(string(body))
+	var apiErr apiErrorResponse
+	if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Error.Message != "" {
+		msg = apiErr.Error.Message
+	}
+	err := fmt.Errorf("anthropic API error: status %d: %s", status, msg)
+
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return llm.ErrAuthenticationFailed(ProviderClaude, err)
+	case http.StatusTooManyRequests:
+		return llm.ErrRateLimitExceeded(ProviderClaude, err)
+	case http.StatusNotFound:
+		return llm.ErrModelNotFound(model, ProviderClaude, err)
+	}
+	return err
+}
+
+// retryableStatus reports whether a request that failed with status may succeed when retried
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
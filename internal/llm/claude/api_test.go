@@ -0,0 +1,220 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/llm/llmtest"
+)
+
+func newAPITestProvider(t *testing.T, handler http.HandlerFunc) *Provider {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	return &Provider{
+		apiKey:     "test-key",
+		baseURL:    srv.URL,
+		httpClient: srv.Client(),
+	}
+}
+
+func TestClaudeProvider_Generate_API(t *testing.T) {
+	var gotReq messagesRequest
+	p := newAPITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "test-key" {
+			t.Errorf("missing api key header")
+		}
+		if r.Header.Get("anthropic-version") == "" {
+			t.Errorf("missing anthropic-version header")
+		}
+		if err := json.NewDecoder(r.Body).Decode(&gotReq); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"  hello from the API  "}]}`))
+	})
+
+	result, err := p.Generate(context.Background(), "say hello", llm.WithModel(ModelSonnet))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if result != "hello from the API" {
+		t.Errorf("Generate() = %q, want %q", result, "hello from the API")
+	}
+
+	if gotReq.Model != APIModelID(ModelSonnet) {
+		t.Errorf("request model = %q, want %q", gotReq.Model, APIModelID(ModelSonnet))
+	}
+	if len(gotReq.Messages) != 1 || gotReq.Messages[0].Content != "say hello" {
+		t.Errorf("unexpected request messages: %+v", gotReq.Messages)
+	}
+	if gotReq.StopSequences != nil {
+		t.Errorf("request stop_sequences = %q, want none", gotReq.StopSequences)
+	}
+}
+
+func TestClaudeProvider_Generate_API_StopSequences(t *testing.T) {
+	var gotReq messagesRequest
+	p := newAPITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&gotReq); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"ls -la"}]}`))
+	})
+
+	if _, err := p.Generate(context.Background(), "list files", llm.WithStopSequences([]string{"\n\n"})); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(gotReq.StopSequences) != 1 || gotReq.StopSequences[0] != "\n\n" {
+		t.Errorf("request stop_sequences = %q, want [\"\\n\\n\"]", gotReq.StopSequences)
+	}
+}
+
+func TestWrapAPIError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   llm.ErrorKind
+	}{
+		{"unauthorized", http.StatusUnauthorized, llm.KindAuthentication},
+		{"rate limited", http.StatusTooManyRequests, llm.KindRateLimit},
+		{"model not found", http.StatusNotFound, llm.KindModelNotFound},
+		{"server error", http.StatusInternalServerError, llm.KindUnknown},
+	}
+
+	body := []byte(`{"type":"error","error":{"type":"x","message":"detailed reason"}}`)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wrapAPIError(tt.status, body, "claude-sonnet-4-5")
+			if got := llm.KindOf(err); got != tt.want {
+				t.Errorf("KindOf() = %q, want %q", got, tt.want)
+			}
+			if !strings.Contains(err.Error(), "detailed reason") {
+				t.Errorf("expected API message in error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestClaudeProvider_Generate_API_Retries(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		wantCalls int
+		wantKind  llm.ErrorKind
+	}{
+		{"bad request", http.StatusBadRequest, 1, llm.KindUnknown},
+		{"unauthorized", http.StatusUnauthorized, 1, llm.KindAuthentication},
+		{"model not found", http.StatusNotFound, 1, llm.KindModelNotFound},
+		{"rate limited", http.StatusTooManyRequests, 3, llm.KindRateLimit},
+		{"server error", http.StatusInternalServerError, 3, llm.KindUnknown},
+		{"overloaded", 529, 3, llm.KindUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llmtest.UseClock(t, llmtest.NewFakeClock(time.Now()))
+			calls := 0
+			p := newAPITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"type":"error","error":{"type":"x","message":"failed"}}`))
+			})
+
+			_, err := p.Generate(context.Background(), "ping", llm.WithMaxRetries(2))
+			if err == nil {
+				t.Fatal("Generate() expected an error")
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("got %d requests, want %d", calls, tt.wantCalls)
+			}
+			if got := llm.KindOf(err); got != tt.wantKind {
+				t.Errorf("KindOf() = %q, want %q", got, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestClaudeProvider_Generate_CLIFallback(t *testing.T) {
+	// Without an API key the provider shells out to the CLI
+	p := &Provider{cliPath: "echo"}
+
+	result, err := p.Generate(context.Background(), "test-prompt", llm.WithModel(ModelHaiku))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !strings.Contains(result, "test-prompt") {
+		t.Errorf("expected CLI output to contain prompt, got %q", result)
+	}
+}
+
+func TestClaudeProvider_Generate_NoAPIKeyOrCLI(t *testing.T) {
+	p := &Provider{}
+
+	_, err := p.Generate(context.Background(), "test-prompt")
+	if err == nil || !strings.Contains(err.Error(), "claude CLI not available") {
+		t.Errorf("expected CLI unavailable error, got %v", err)
+	}
+}
+
+func TestClaudeProvider_Generate_JSONMode(t *testing.T) {
+	var gotReq messagesRequest
+	p := newAPITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&gotReq)
+		_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"Here is the JSON:\n{\"command\": \"ls -la\"}\nLet me know!"}]}`))
+	})
+
+	result, err := p.Generate(context.Background(), "list files", llm.WithJSONMode())
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if result != `{"command": "ls -la"}` {
+		t.Errorf("Generate() = %q, want extracted JSON object", result)
+	}
+	if !strings.Contains(gotReq.Messages[0].Content, llm.JSONInstruction) {
+		t.Error("expected JSON instruction to be appended to the prompt")
+	}
+}
+
+func TestClaudeProvider_Generate_EmptyResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider func(t *testing.T) *Provider
+	}{
+		{
+			name: "api",
+			provider: func(t *testing.T) *Provider {
+				return newAPITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"   "}]}`))
+				})
+			},
+		},
+		{
+			name: "cli",
+			provider: func(t *testing.T) *Provider {
+				return &Provider{cliPath: writeFakeCLI(t, `echo ""`)}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.provider(t).Generate(context.Background(), "ping", llm.WithMaxRetries(0))
+			if llm.KindOf(err) != llm.KindEmptyResponse {
+				t.Fatalf("Generate() error = %v, want an empty response error", err)
+			}
+			if llm.HintOf(err) == "" {
+				t.Error("expected a hint for the empty response")
+			}
+		})
+	}
+}
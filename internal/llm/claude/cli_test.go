@@ -0,0 +1,137 @@
+package claude
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/connorhough/smix/internal/llm"
+)
+
+// writeFakeCLI writes an executable shell script standing in for the claude CLI
+func writeFakeCLI(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "claude")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestGenerateViaCLI_SeparatesStderr(t *testing.T) {
+	cli := writeFakeCLI(t, `echo "warning: update available" >&2
+echo "  the answer  "`)
+	p := &Provider{cliPath: cli}
+
+	result, err := p.Generate(context.Background(), "question")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if result != "the answer" {
+		t.Errorf("Generate() = %q, want stdout only", result)
+	}
+}
+
+func TestGenerateViaCLI_StderrInError(t *testing.T) {
+	cli := writeFakeCLI(t, `echo "partial output"
+echo "error: model not found" >&2
+exit 2`)
+	p := &Provider{cliPath: cli}
+
+	_, err := p.Generate(context.Background(), "question")
+	if err == nil {
+		t.Fatal("expected error for non-zero exit")
+	}
+	if !strings.Contains(err.Error(), "error: model not found") {
+		t.Errorf("expected stderr in error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "partial output") {
+		t.Errorf("stdout should not be included in the error, got %v", err)
+	}
+}
+
+func TestGenerateViaCLI_ContextCancellation(t *testing.T) {
+	// The sleep runs as a child of sh, so it keeps the output pipe open after sh is killed
+	cli := writeFakeCLI(t, `sleep 30`)
+	p := &Provider{cliPath: cli}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := p.Generate(ctx, "question")
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected error wrapping context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed > cliWaitDelay+3*time.Second {
+		t.Errorf("Generate() took %s after cancellation, want prompt return", elapsed)
+	}
+}
+
+func TestProvider_ExtraArgs(t *testing.T) {
+	p := &Provider{cliPath: "echo"}
+	if err := p.SetExtraArgs([]string{"--allowedTools", "Bash"}); err != nil {
+		t.Fatalf("SetExtraArgs() error = %v", err)
+	}
+
+	t.Run("generate", func(t *testing.T) {
+		got, err := p.Generate(context.Background(), "question", llm.WithModel(ModelHaiku), llm.WithExtraArgs([]string{"--verbose"}))
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if want := "--model " + ResolveModel(ModelHaiku) + " --allowedTools Bash --verbose -p question"; got != want {
+			t.Errorf("command line = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("interactive", func(t *testing.T) {
+		streams, _, out := llm.TestIOStreams()
+		if err := p.RunInteractive(context.Background(), streams, "question", llm.WithModel(ModelHaiku), llm.WithExtraArgs([]string{"--verbose"})); err != nil {
+			t.Fatalf("RunInteractive() error = %v", err)
+		}
+		if got, want := strings.TrimSpace(out.String()), "--model "+ResolveModel(ModelHaiku)+" --allowedTools Bash --verbose question"; got != want {
+			t.Errorf("command line = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("reserved flags are rejected", func(t *testing.T) {
+		if err := p.SetExtraArgs([]string{"--model=opus"}); err == nil {
+			t.Error("SetExtraArgs() accepted --model")
+		}
+		if _, err := p.Generate(context.Background(), "question", llm.WithExtraArgs([]string{"-p", "other prompt"})); err == nil {
+			t.Error("Generate() accepted a second -p")
+		}
+	})
+}
+
+func TestRunInteractive_RateLimit(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   llm.ErrorKind
+	}{
+		{name: "usage limit on stderr", script: "echo 'Claude usage limit reached' >&2\nexit 1", want: llm.KindRateLimit},
+		{name: "other failure", script: "echo 'unknown option' >&2\nexit 1", want: llm.KindUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Provider{cliPath: writeFakeCLI(t, tt.script)}
+			streams, _, _ := llm.TestIOStreams()
+
+			err := p.RunInteractive(context.Background(), streams, "question")
+			if err == nil {
+				t.Fatal("RunInteractive() error = nil, want the CLI's failure")
+			}
+			if got := llm.KindOf(err); got != tt.want {
+				t.Errorf("KindOf(%v) = %q, want %q", err, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,8 +3,11 @@ package claude
 
 import (
 	"context"
+	"errors"
+	"os/exec"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/connorhough/smix/internal/llm"
 )
@@ -47,7 +50,7 @@ func TestClaudeProvider_RunInteractive_UsesStreams(t *testing.T) {
 
 func TestClaudeProvider_RunInteractive_Integration(t *testing.T) {
 	// Integration test - only runs if claude CLI is available
-	p, err := NewProvider()
+	p, err := NewProvider("")
 	if err != nil {
 		t.Skipf("claude CLI not available: %v", err)
 	}
@@ -81,3 +84,25 @@ func TestClaudeProvider_RunInteractive_NonInteractive(t *testing.T) {
 	// check streams.IsInteractive() before calling RunInteractive
 	t.Logf("RunInteractive() with non-interactive streams returned: %v", err)
 }
+
+func TestClaudeProvider_RunInteractive_Cancelled(t *testing.T) {
+	cli := writeFakeCLI(t, `trap 'exit 130' INT
+sleep 5 >/dev/null 2>&1 </dev/null &
+wait`)
+	p := &Provider{cliPath: cli}
+	streams, _, _ := llm.TestIOStreams()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := p.RunInteractive(ctx, streams, "prompt")
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Fatalf("RunInteractive() took %v to return after cancellation", elapsed)
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.Is(err, context.Canceled) || !errors.As(err, &exitErr) || exitErr.ExitCode() != 130 {
+		t.Errorf("RunInteractive() error = %v, want context.Canceled with exit status 130", err)
+	}
+}
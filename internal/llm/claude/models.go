@@ -8,7 +8,48 @@ const (
 	ModelOpus   = "opus"
 )
 
+// APIKeyEnvVar is the environment variable used for the Anthropic API key
+const APIKeyEnvVar = "ANTHROPIC_API_KEY"
+
+// apiModelIDPrefix starts every Anthropic API model ID
+const apiModelIDPrefix = "claude-"
+
+// apiModelIDs maps Claude CLI short model names to Anthropic API model IDs
+var apiModelIDs = map[string]string{
+	ModelHaiku:  "claude-haiku-4-5",
+	ModelSonnet: "claude-sonnet-4-5",
+	ModelOpus:   "claude-opus-4-1",
+}
+
+// APIModelID returns the Anthropic API model ID for a CLI short name, returning unknown names unchanged
+func APIModelID(model string) string {
+	if id, ok := apiModelIDs[model]; ok {
+		return id
+	}
+	return model
+}
+
+// ModelAliases maps provider-neutral tier names to Claude CLI model names
+var ModelAliases = map[string]string{
+	"fast":     ModelHaiku,
+	"balanced": ModelSonnet,
+	"smart":    ModelOpus,
+}
+
+// ResolveModel expands a known alias to a Claude model name, returning unknown names unchanged
+func ResolveModel(model string) string {
+	if resolved, ok := ModelAliases[model]; ok {
+		return resolved
+	}
+	return model
+}
+
 // DefaultModel returns the default Claude model
 func DefaultModel() string {
 	return ModelHaiku
 }
+
+// Models returns the known Claude model names
+func Models() []string {
+	return []string{ModelHaiku, ModelSonnet, ModelOpus}
+}
@@ -2,37 +2,76 @@
 package claude
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/connorhough/smix/internal/llm"
 )
 
 const ProviderClaude = "claude"
 
-// Provider implements the llm.Provider interface for Claude CLI
+// cliWaitDelay is how long a cancelled CLI invocation may take to release its output before it is abandoned
+const cliWaitDelay = 2 * time.Second
+
+// Provider implements the llm.Provider interface for Claude.
+// Generate uses the Anthropic Messages API when an API key is configured and
+// falls back to the claude CLI otherwise. Interactive mode always uses the CLI.
 type Provider struct {
-	cliPath string
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	cliPath    string // Optional when an API key is set: path to claude CLI
+	extraArgs  []string
 }
 
+// reservedArgs are the claude CLI flags the provider sets itself
+var reservedArgs = []string{"--model", "-p", "--print"}
+
 // Verify interface compliance at compile time
 var (
 	_ llm.Provider            = (*Provider)(nil)
 	_ llm.InteractiveProvider = (*Provider)(nil)
+	_ llm.ModelLister         = (*Provider)(nil)
+	_ llm.ModelResolver       = (*Provider)(nil)
 )
 
-// NewProvider creates a new Claude provider
-func NewProvider() (*Provider, error) {
+// NewProvider creates a new Claude provider.
+// At least one of an API key or the claude CLI on PATH is required.
+func NewProvider(apiKey string) (*Provider, error) {
+	return NewProviderWithHTTP(apiKey, llm.HTTPConfig{})
+}
+
+// NewProviderWithHTTP creates a new Claude provider whose API requests use httpCfg's
+// base URL and HTTP client
+func NewProviderWithHTTP(apiKey string, httpCfg llm.HTTPConfig) (*Provider, error) {
+	// detect claude CLI (required only when no API key is given)
 	cliPath, err := exec.LookPath(ProviderClaude)
-	if err != nil {
-		return nil, llm.ErrProviderNotAvailable(ProviderClaude, err)
+	if err != nil && apiKey == "" {
+		return nil, llm.ErrProviderNotAvailable(ProviderClaude,
+			fmt.Errorf("%w (install the claude CLI or set %s)", err, APIKeyEnvVar))
+	}
+
+	p := &Provider{
+		apiKey:     apiKey,
+		baseURL:    DefaultBaseURL,
+		httpClient: http.DefaultClient,
+		cliPath:    cliPath,
+	}
+	if httpCfg.BaseURL != "" {
+		p.baseURL = strings.TrimSuffix(httpCfg.BaseURL, "/")
+	}
+	if httpCfg.Client != nil {
+		p.httpClient = httpCfg.Client
 	}
 
-	return &Provider{
-		cliPath: cliPath,
-	}, nil
+	return p, nil
 }
 
 // Name returns the provider name
@@ -45,6 +84,22 @@ func (p *Provider) DefaultModel() string {
 	return DefaultModel()
 }
 
+// ListModels returns the known Claude model names
+func (p *Provider) ListModels() []string {
+	return Models()
+}
+
+// ResolveModel expands known Claude model aliases
+func (p *Provider) ResolveModel(model string) string {
+	return ResolveModel(model)
+}
+
+// MatchesModelID accepts full Anthropic model IDs such as claude-sonnet-4-5-20250929,
+// which ListModels does not enumerate
+func (p *Provider) MatchesModelID(model string) bool {
+	return strings.HasPrefix(model, apiModelIDPrefix)
+}
+
 // ValidateModel checks if a model is valid
 func (p *Provider) ValidateModel(model string) error {
 	return nil // No pre-validation, let CLI handle it
@@ -54,20 +109,94 @@ func (p *Provider) ValidateModel(model string) error {
 func (p *Provider) Generate(ctx context.Context, prompt string, opts ...llm.Option) (string, error) {
 	options := llm.BuildOptions(opts)
 
-	model := options.Model
+	model := ResolveModel(options.Model)
 	if model == "" {
 		model = p.DefaultModel()
 	}
 
-	cmd := exec.CommandContext(ctx, p.cliPath, "--model", model, "-p", prompt)
-	output, err := cmd.CombinedOutput()
+	// Claude has no native JSON mode, so instruct the model and extract the object afterwards
+	if options.JSONMode {
+		prompt = llm.JSONPrompt(prompt, options.JSONSchema)
+	}
+
+	var result string
+	var err error
+	if p.apiKey != "" {
+		result, err = p.generateViaAPI(ctx, model, prompt, options.StopSequences, options.Retries())
+	} else {
+		// The claude CLI has no stop sequence flag, so StopSequences only apply to the API backend
+		result, err = p.generateViaCLI(ctx, model, prompt, options.ExtraArgs)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if options.JSONMode {
+		if extracted, err := llm.ExtractJSON(result); err == nil {
+			return extracted, nil
+		}
+	}
+
+	return result, nil
+}
+
+// SetExtraArgs sets arguments added to every claude CLI invocation, before any passed with
+// llm.WithExtraArgs
+func (p *Provider) SetExtraArgs(args []string) error {
+	if err := llm.CheckExtraArgs(ProviderClaude, args, reservedArgs); err != nil {
+		return err
+	}
+	p.extraArgs = args
+	return nil
+}
+
+// cliArgs builds the claude CLI arguments: the model, the configured and per-request extra
+// arguments, then the prompt arguments
+func (p *Provider) cliArgs(model string, extra []string, promptArgs ...string) ([]string, error) {
+	if err := llm.CheckExtraArgs(ProviderClaude, extra, reservedArgs); err != nil {
+		return nil, err
+	}
+	args := append([]string{"--model", model}, p.extraArgs...)
+	args = append(args, extra...)
+	return append(args, promptArgs...), nil
+}
+
+// generateViaCLI runs the claude CLI in print mode and returns the output
+func (p *Provider) generateViaCLI(ctx context.Context, model, prompt string, extra []string) (string, error) {
+	if p.cliPath == "" {
+		return "", fmt.Errorf("claude CLI not available")
+	}
+	args, err := p.cliArgs(model, extra, "-p", prompt)
 	if err != nil {
-		return "", fmt.Errorf("claude CLI failed: %w (output: %s)", err, output)
+		return "", err
 	}
 
-	result := strings.TrimSpace(string(output))
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.cliPath, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// The CLI is killed when ctx is done; WaitDelay bounds how long we wait for any
+	// subprocesses still holding its output pipes before giving up on them
+	cmd.WaitDelay = cliWaitDelay
+
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", fmt.Errorf("claude CLI interrupted: %w", ctxErr)
+		}
+		if diag := strings.TrimSpace(stderr.String()); diag != "" {
+			return "", fmt.Errorf("claude CLI failed: %w (stderr: %s)", err, diag)
+		}
+		return "", fmt.Errorf("claude CLI failed: %w", err)
+	}
+
+	// Warnings on stderr are diagnostics, not part of the response
+	if diag := strings.TrimSpace(stderr.String()); diag != "" {
+		slog.Debug("claude CLI stderr", "output", diag)
+	}
+
+	result := strings.TrimSpace(stdout.String())
 	if result == "" {
-		return "", fmt.Errorf("claude CLI returned empty response")
+		return "", llm.ErrEmptyResponse(ProviderClaude, errors.New("claude CLI returned empty response"))
 	}
 
 	return result, nil
@@ -86,25 +215,44 @@ func (p *Provider) Generate(ctx context.Context, prompt string, opts ...llm.Opti
 // the user needs to see formatted output and potentially interact with Claude.
 // It should NOT be used for commands that need clean, parseable output.
 func (p *Provider) RunInteractive(ctx context.Context, streams *llm.IOStreams, prompt string, opts ...llm.Option) error {
+	if p.cliPath == "" {
+		return fmt.Errorf("claude CLI not available for interactive mode; install it from https://claude.ai/code")
+	}
+
 	options := llm.BuildOptions(opts)
 
-	model := options.Model
+	model := ResolveModel(options.Model)
 	if model == "" {
 		model = p.DefaultModel()
 	}
 
-	// Build command with model and prompt
-	cmd := exec.CommandContext(ctx, p.cliPath, "--model", model, prompt)
+	// Build command with model, extra arguments and prompt
+	args, err := p.cliArgs(model, options.ExtraArgs, prompt)
+	if err != nil {
+		return err
+	}
+	cmd := llm.InteractiveCommand(ctx, p.cliPath, args...)
 
 	// Connect provided streams to allow interactive mode
 	// os.Stdin/Stdout/Stderr for production or buffers for testing
 	cmd.Stdin = streams.In
 	cmd.Stdout = streams.Out
-	cmd.Stderr = streams.ErrOut
+	stderr := llm.NewStderrTail(streams.ErrOut)
+	cmd.Stderr = stderr
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("claude CLI interactive mode failed: %w", err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("claude CLI interactive session interrupted: %w (%w)", ctxErr, err)
+		}
+		// The wrapped *exec.ExitError carries the CLI's exit status; a rate limit reported on
+		// stderr is typed so pr review can fail over to another provider
+		return llm.ClassifyCLIFailure(ProviderClaude, fmt.Errorf("claude CLI interactive mode failed: %w", err), stderr.String())
 	}
 
 	return nil
 }
+
+// HasInteractiveSupport returns true if the claude CLI is available for interactive sessions.
+func (p *Provider) HasInteractiveSupport() bool {
+	return p.cliPath != ""
+}
@@ -3,6 +3,7 @@ package claude
 import (
 	"context"
 	"errors"
+	"net/http"
 	"testing"
 
 	"github.com/connorhough/smix/internal/llm"
@@ -36,7 +37,7 @@ func TestNewProvider(t *testing.T) {
 	// This test validates the constructor checks for CLI availability
 	// We can't reliably test the error case without mocking exec.LookPath
 	// So we just verify the constructor exists and returns a provider
-	p, err := NewProvider()
+	p, err := NewProvider("")
 	if err != nil {
 		// If CLI not found, ensure we get the right error type
 		var providerErr *llm.ProviderError
@@ -58,7 +59,7 @@ func TestNewProvider(t *testing.T) {
 
 func TestClaudeProvider_Generate_Integration(t *testing.T) {
 	// Integration test - only runs if claude CLI is available
-	p, err := NewProvider()
+	p, err := NewProvider("")
 	if err != nil {
 		t.Skipf("claude CLI not available: %v", err)
 	}
@@ -75,3 +76,83 @@ func TestClaudeProvider_Generate_Integration(t *testing.T) {
 
 	t.Logf("Claude response: %s", result)
 }
+
+func TestResolveModel(t *testing.T) {
+	tests := []struct {
+		alias string
+		want  string
+	}{
+		{"fast", ModelHaiku},
+		{"balanced", ModelSonnet},
+		{"smart", ModelOpus},
+		{"sonnet", ModelSonnet},
+		{"claude-sonnet-4-5", "claude-sonnet-4-5"},
+		{"", ""},
+	}
+
+	p := &Provider{}
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			if got := p.ResolveModel(tt.alias); got != tt.want {
+				t.Errorf("ResolveModel(%q) = %q, want %q", tt.alias, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckKnownModel(t *testing.T) {
+	tests := []struct {
+		model   string
+		wantErr bool
+	}{
+		{model: "sonnet"},
+		{model: "balanced"},
+		{model: "claude-sonnet-4-5-20250929"},
+		{model: "claude-opus-4-1"},
+		{model: "sonnett", wantErr: true},
+		{model: "gemini-2.5-flash", wantErr: true},
+	}
+
+	p := &Provider{}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			if err := llm.CheckKnownModel(p, tt.model); (err != nil) != tt.wantErr {
+				t.Errorf("CheckKnownModel(%q) error = %v, wantErr %v", tt.model, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewProviderWithHTTP(t *testing.T) {
+	client := &http.Client{}
+
+	tests := []struct {
+		name        string
+		httpCfg     llm.HTTPConfig
+		wantBaseURL string
+		wantClient  *http.Client
+	}{
+		{name: "defaults", httpCfg: llm.HTTPConfig{}, wantBaseURL: DefaultBaseURL, wantClient: http.DefaultClient},
+		{
+			name:        "overrides",
+			httpCfg:     llm.HTTPConfig{BaseURL: "https://gateway.example/anthropic/", Client: client},
+			wantBaseURL: "https://gateway.example/anthropic",
+			wantClient:  client,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewProviderWithHTTP("test-key", tt.httpCfg)
+			if err != nil {
+				t.Fatalf("NewProviderWithHTTP() error = %v", err)
+			}
+			if p.baseURL != tt.wantBaseURL {
+				t.Errorf("baseURL = %q, want %q", p.baseURL, tt.wantBaseURL)
+			}
+			if p.httpClient != tt.wantClient {
+				t.Errorf("httpClient = %p, want %p", p.httpClient, tt.wantClient)
+			}
+		})
+	}
+}
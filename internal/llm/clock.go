@@ -0,0 +1,39 @@
+package llm
+
+import (
+	"context"
+	"time"
+)
+
+// Clock abstracts the current time and waiting so time-dependent behavior such as retry
+// backoff can be tested without real delays
+type Clock interface {
+	Now() time.Time
+	// Sleep waits for d, returning early with ctx.Err() when ctx is done
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// SystemClock is the Clock backed by the time package
+type SystemClock struct{}
+
+// Now returns the current time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Sleep waits for d or until ctx is done
+func (SystemClock) Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// DefaultClock is used by retries and other time-dependent code. Tests replace it
+// with a fake (see llmtest.FakeClock) to run deterministically.
+var DefaultClock Clock = SystemClock{}
@@ -0,0 +1,129 @@
+package llm_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/llm/llmtest"
+)
+
+func TestRetryWithBackoffN_FakeClock(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := llmtest.NewFakeClock(start)
+	llmtest.UseClock(t, clock)
+
+	calls := 0
+	_, err := llm.RetryWithBackoffN(context.Background(), 7, func(ctx context.Context) (string, error) {
+		calls++
+		return "", errors.New("transient error")
+	})
+	if err == nil {
+		t.Fatal("expected error after exhausting retries")
+	}
+	if calls != 8 {
+		t.Errorf("got %d calls, want 8", calls)
+	}
+
+	want := []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second}
+	if got := clock.Sleeps(); !reflect.DeepEqual(got, want) {
+		t.Errorf("sleeps = %v, want %v", got, want)
+	}
+	if got := clock.Now().Sub(start); got != 91*time.Second {
+		t.Errorf("clock advanced %v, want 1m31s", got)
+	}
+}
+
+func TestRetryWithBackoffN_CancelledDuringSleep(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	llmtest.UseClock(t, llmtest.NewFakeClock(time.Now()))
+
+	_, err := llm.RetryWithBackoffN(ctx, 3, func(ctx context.Context) (string, error) {
+		cancel()
+		return "", errors.New("transient error")
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestSystemClock_Sleep(t *testing.T) {
+	var clock llm.SystemClock
+
+	if err := clock.Sleep(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("Sleep() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := clock.Sleep(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("Sleep() with cancelled context = %v, want context.Canceled", err)
+	}
+}
+
+func TestRetryWithBackoffN_Stats(t *testing.T) {
+	llmtest.UseClock(t, llmtest.NewFakeClock(time.Now()))
+
+	tests := []struct {
+		name         string
+		failures     int
+		wantAttempts int
+		wantRetries  int
+		wantBackoff  time.Duration
+	}{
+		{name: "first attempt succeeds", failures: 0, wantAttempts: 1},
+		{name: "two failures", failures: 2, wantAttempts: 3, wantRetries: 2, wantBackoff: 3 * time.Second},
+		{name: "three failures", failures: 3, wantAttempts: 4, wantRetries: 3, wantBackoff: 7 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := &llm.RetryStats{}
+			calls := 0
+			_, err := llm.RetryWithBackoffN(llm.WithRetryStats(context.Background(), stats), 5, func(ctx context.Context) (string, error) {
+				calls++
+				if calls <= tt.failures {
+					return "", errors.New("transient error")
+				}
+				return "ok", nil
+			})
+			if err != nil {
+				t.Fatalf("RetryWithBackoffN() error = %v", err)
+			}
+			if stats.Attempts != tt.wantAttempts || stats.Retries != tt.wantRetries || stats.Backoff != tt.wantBackoff {
+				t.Errorf("stats = %d attempts, %d retries, %v backoff, want %d, %d, %v",
+					stats.Attempts, stats.Retries, stats.Backoff, tt.wantAttempts, tt.wantRetries, tt.wantBackoff)
+			}
+		})
+	}
+
+	t.Run("accumulates across requests", func(t *testing.T) {
+		stats := &llm.RetryStats{}
+		ctx := llm.WithRetryStats(context.Background(), stats)
+		for range 2 {
+			failed := false
+			_, _ = llm.RetryWithBackoffN(ctx, 2, func(ctx context.Context) (string, error) {
+				if !failed {
+					failed = true
+					return "", errors.New("transient error")
+				}
+				return "ok", nil
+			})
+		}
+		if stats.Retries != 2 || stats.Backoff != 2*time.Second {
+			t.Errorf("stats = %d retries, %v backoff, want 2, 2s", stats.Retries, stats.Backoff)
+		}
+		if got, want := stats.Summary(), "succeeded after 2 retries (2s of backoff)"; got != want {
+			t.Errorf("Summary() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("without stats", func(t *testing.T) {
+		if llm.RetryStatsFrom(context.Background()) != nil {
+			t.Error("RetryStatsFrom() on a bare context should be nil")
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package llm
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Color modes accepted by SetDefaultColorMode and the --color flag
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// defaultColorMode is applied to streams created by NewIOStreams
+var defaultColorMode = ColorAuto
+
+// SetDefaultColorMode sets the color mode for streams created by NewIOStreams afterwards
+func SetDefaultColorMode(mode string) error {
+	if err := validateColorMode(mode); err != nil {
+		return err
+	}
+	defaultColorMode = mode
+	return nil
+}
+
+func validateColorMode(mode string) error {
+	switch mode {
+	case ColorAuto, ColorAlways, ColorNever:
+		return nil
+	default:
+		return fmt.Errorf("invalid color mode %q (expected %s, %s, or %s)", mode, ColorAuto, ColorAlways, ColorNever)
+	}
+}
+
+// SetColorMode overrides the color mode for these streams
+func (s *IOStreams) SetColorMode(mode string) error {
+	if err := validateColorMode(mode); err != nil {
+		return err
+	}
+	s.colorMode = mode
+	return nil
+}
+
+// ColorEnabled reports whether styled output should be written to Out.
+// In auto mode color is used only when Out is a terminal and NO_COLOR is not set.
+func (s *IOStreams) ColorEnabled() bool {
+	switch s.colorMode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	getenv := s.getenv
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+	// https://no-color.org: any non-empty value disables color
+	if getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	return s.StdoutIsTerminal()
+}
+
+// Styler returns a Styler that colors text only when ColorEnabled is true
+func (s *IOStreams) Styler() Styler {
+	return Styler{Enabled: s.ColorEnabled()}
+}
+
+// Styler wraps text in ANSI styles. The zero value leaves text unstyled.
+type Styler struct {
+	Enabled bool
+}
+
+// ANSI SGR codes used by Styler
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+)
+
+func (st Styler) wrap(code, text string) string {
+	if !st.Enabled {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// Bold renders text in bold, for headings
+func (st Styler) Bold(text string) string { return st.wrap(ansiBold, text) }
+
+// Red renders text in red, for failures
+func (st Styler) Red(text string) string { return st.wrap(ansiRed, text) }
+
+// Green renders text in green, for success
+func (st Styler) Green(text string) string { return st.wrap(ansiGreen, text) }
+
+// Yellow renders text in yellow, for warnings
+func (st Styler) Yellow(text string) string { return st.wrap(ansiYellow, text) }
+
+// Cyan renders text in cyan, for separators and progress markers
+func (st Styler) Cyan(text string) string { return st.wrap(ansiCyan, text) }
+
+// Diff colors the lines of a unified diff: additions green, removals red, hunk headers cyan
+// and file headers bold. Other lines are left as they are.
+func (st Styler) Diff(diff string) string {
+	if !st.Enabled {
+		return diff
+	}
+
+	lines := strings.Split(diff, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			lines[i] = st.Bold(line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = st.Cyan(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = st.Green(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = st.Red(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
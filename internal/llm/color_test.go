@@ -0,0 +1,132 @@
+package llm
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestIOStreams_ColorEnabled(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      string
+		stdoutTTY bool
+		noColor   string
+		want      bool
+	}{
+		{"auto with terminal", ColorAuto, true, "", true},
+		{"auto without terminal", ColorAuto, false, "", false},
+		{"auto with NO_COLOR", ColorAuto, true, "1", false},
+		{"always ignores NO_COLOR and TTY", ColorAlways, false, "1", true},
+		{"never on a terminal", ColorNever, true, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streams := &IOStreams{
+				Out:            &bytes.Buffer{},
+				isTerminalFunc: func(fd int) bool { return fd == 1 && tt.stdoutTTY },
+				stdoutFd:       1,
+				colorMode:      tt.mode,
+				getenv: func(key string) string {
+					if key == "NO_COLOR" {
+						return tt.noColor
+					}
+					return ""
+				},
+			}
+
+			if got := streams.ColorEnabled(); got != tt.want {
+				t.Errorf("ColorEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTestIOStreams_ColorDisabled(t *testing.T) {
+	streams, _, _ := TestIOStreams()
+	if !streams.IsInteractive() {
+		t.Error("TestIOStreams should still simulate an interactive stdin")
+	}
+	if streams.ColorEnabled() {
+		t.Error("TestIOStreams should have color disabled by default")
+	}
+
+	if err := streams.SetColorMode(ColorAlways); err != nil {
+		t.Fatalf("SetColorMode() error = %v", err)
+	}
+	if !streams.ColorEnabled() {
+		t.Error("expected color after SetColorMode(always)")
+	}
+
+	if err := streams.SetColorMode("rainbow"); err == nil {
+		t.Error("expected error for invalid color mode")
+	}
+}
+
+func TestStyler(t *testing.T) {
+	plain := Styler{}
+	if got := plain.Red("FAIL"); got != "FAIL" {
+		t.Errorf("disabled Styler.Red() = %q, want plain text", got)
+	}
+
+	colored := Styler{Enabled: true}
+	if got := colored.Green("OK"); got != "\033[32mOK\033[0m" {
+		t.Errorf("Styler.Green() = %q", got)
+	}
+	if got := colored.Bold("Providers:"); got != "\033[1mProviders:\033[0m" {
+		t.Errorf("Styler.Bold() = %q", got)
+	}
+}
+
+func TestStyler_Diff(t *testing.T) {
+	diff := "--- a/main.go\n+++ b/main.go\n@@ -1,2 +1,2 @@\n context\n-old line\n+new line"
+
+	tests := []struct {
+		name   string
+		styler Styler
+		want   string
+	}{
+		{
+			name:   "color enabled",
+			styler: Styler{Enabled: true},
+			want: "\033[1m--- a/main.go\033[0m\n\033[1m+++ b/main.go\033[0m\n\033[36m@@ -1,2 +1,2 @@\033[0m\n context\n" +
+				"\033[31m-old line\033[0m\n\033[32m+new line\033[0m",
+		},
+		{name: "color disabled", styler: Styler{}, want: diff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.styler.Diff(diff); got != tt.want {
+				t.Errorf("Diff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("NO_COLOR disables it", func(t *testing.T) {
+		streams := &IOStreams{
+			Out:            &bytes.Buffer{},
+			isTerminalFunc: func(int) bool { return true },
+			stdoutFd:       1,
+			colorMode:      ColorAuto,
+			getenv:         func(string) string { return "1" },
+		}
+		if got := streams.Styler().Diff(diff); got != diff {
+			t.Errorf("Diff() with NO_COLOR = %q, want it unchanged", got)
+		}
+	})
+}
+
+func TestSetDefaultColorMode(t *testing.T) {
+	t.Cleanup(func() { defaultColorMode = ColorAuto })
+
+	if err := SetDefaultColorMode(ColorNever); err != nil {
+		t.Fatalf("SetDefaultColorMode() error = %v", err)
+	}
+	if NewIOStreams().ColorEnabled() {
+		t.Error("expected streams from NewIOStreams to follow the default color mode")
+	}
+	if err := SetDefaultColorMode("sometimes"); err == nil {
+		t.Error("expected error for invalid color mode")
+	}
+}
@@ -1,12 +1,30 @@
 package llm
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorKind categorizes provider errors so callers can react without string matching
+type ErrorKind string
+
+const (
+	KindUnknown        ErrorKind = "error"
+	KindNotAvailable   ErrorKind = "not available"
+	KindAuthentication ErrorKind = "authentication"
+	KindRateLimit      ErrorKind = "rate limit"
+	KindModelNotFound  ErrorKind = "model not found"
+	KindEmptyResponse  ErrorKind = "empty response"
+)
 
 // ProviderError represents a provider-specific error
 type ProviderError struct {
 	Provider string
+	Kind     ErrorKind
 	Msg      string
 	Err      error
+	// Hint is an optional suggestion for resolving the error, shown to users on its own line
+	Hint string
 }
 
 // Verify interface compliance at compile time
@@ -23,12 +41,71 @@ func (e *ProviderError) Unwrap() error {
 	return e.Err
 }
 
+// KindOf returns the kind of the first ProviderError in err's chain, or KindUnknown
+func KindOf(err error) ErrorKind {
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) && providerErr.Kind != "" {
+		return providerErr.Kind
+	}
+	return KindUnknown
+}
+
+// ProviderOf returns the provider of the first ProviderError in err's chain, or an empty string
+func ProviderOf(err error) string {
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		return providerErr.Provider
+	}
+	return ""
+}
+
+// HintOf returns the hint of the first ProviderError in err's chain, or an empty string
+func HintOf(err error) string {
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		return providerErr.Hint
+	}
+	return ""
+}
+
+// providerHints holds provider-specific suggestions by error kind. Environment variable names
+// mirror the APIKeyEnvVar constants in the provider packages, which cannot be imported here.
+var providerHints = map[string]map[ErrorKind]string{
+	"claude": {
+		KindNotAvailable:   "install the claude CLI (https://docs.anthropic.com/en/docs/claude-code) or set ANTHROPIC_API_KEY",
+		KindAuthentication: "check ANTHROPIC_API_KEY, or run 'claude' to log in with the CLI",
+	},
+	"gemini": {
+		KindNotAvailable:   "set SMIX_GEMINI_API_KEY or install the gemini CLI (https://github.com/google-gemini/gemini-cli)",
+		KindAuthentication: "set SMIX_GEMINI_API_KEY to a valid Gemini API key",
+	},
+}
+
+// defaultHints apply when a provider has no specific hint for the error kind
+var defaultHints = map[ErrorKind]string{
+	KindNotAvailable:   "run 'smix doctor' to check provider setup",
+	KindAuthentication: "run 'smix doctor' to check provider credentials",
+	KindRateLimit:      "wait a moment and retry, or switch providers with --provider",
+	KindModelNotFound:  "run 'smix providers' to list available models",
+	KindEmptyResponse:  "try rephrasing the request or a different model with --model",
+}
+
+// hintFor returns the suggestion for an error of kind from provider
+func hintFor(provider string, kind ErrorKind) string {
+	if hint, ok := providerHints[provider][kind]; ok {
+		return hint
+	}
+	return defaultHints[kind]
+}
+
 // ErrProviderNotAvailable indicates the provider is not available (CLI not found, SDK init failed)
 func ErrProviderNotAvailable(provider string, err error) error {
 	return &ProviderError{
 		Provider: provider,
+		Kind:     KindNotAvailable,
 		Msg:      fmt.Sprintf("provider '%s' not available", provider),
 		Err:      err,
+		Hint:     hintFor(provider, KindNotAvailable),
 	}
 }
 
@@ -36,8 +113,10 @@ func ErrProviderNotAvailable(provider string, err error) error {
 func ErrAuthenticationFailed(provider string, err error) error {
 	return &ProviderError{
 		Provider: provider,
+		Kind:     KindAuthentication,
 		Msg:      fmt.Sprintf("authentication failed for provider '%s'", provider),
 		Err:      err,
+		Hint:     hintFor(provider, KindAuthentication),
 	}
 }
 
@@ -45,8 +124,10 @@ func ErrAuthenticationFailed(provider string, err error) error {
 func ErrRateLimitExceeded(provider string, err error) error {
 	return &ProviderError{
 		Provider: provider,
+		Kind:     KindRateLimit,
 		Msg:      fmt.Sprintf("rate limit exceeded for provider '%s'", provider),
 		Err:      err,
+		Hint:     hintFor(provider, KindRateLimit),
 	}
 }
 
@@ -54,7 +135,21 @@ func ErrRateLimitExceeded(provider string, err error) error {
 func ErrModelNotFound(model, provider string, err error) error {
 	return &ProviderError{
 		Provider: provider,
+		Kind:     KindModelNotFound,
 		Msg:      fmt.Sprintf("model '%s' not found for provider '%s'", model, provider),
 		Err:      err,
+		Hint:     hintFor(provider, KindModelNotFound),
+	}
+}
+
+// ErrEmptyResponse indicates the provider answered without any content. err describes
+// where the empty answer came from (e.g. the CLI or the API).
+func ErrEmptyResponse(provider string, err error) error {
+	return &ProviderError{
+		Provider: provider,
+		Kind:     KindEmptyResponse,
+		Msg:      "the model returned no content",
+		Err:      err,
+		Hint:     hintFor(provider, KindEmptyResponse),
 	}
 }
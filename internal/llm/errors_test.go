@@ -2,6 +2,7 @@ package llm
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -31,6 +32,11 @@ func TestErrorTypes(t *testing.T) {
 			err:     ErrModelNotFound("invalid-model", "gemini", nil),
 			wantMsg: "model 'invalid-model' not found for provider 'gemini'",
 		},
+		{
+			name:    "empty response",
+			err:     ErrEmptyResponse("claude", errors.New("claude CLI returned empty response")),
+			wantMsg: "the model returned no content: claude CLI returned empty response",
+		},
 	}
 
 	for _, tt := range tests {
@@ -50,3 +56,94 @@ func TestErrorUnwrapping(t *testing.T) {
 		t.Error("error should unwrap to underlying error")
 	}
 }
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorKind
+	}{
+		{"not available", ErrProviderNotAvailable("claude", nil), KindNotAvailable},
+		{"authentication", ErrAuthenticationFailed("gemini", nil), KindAuthentication},
+		{"rate limit", ErrRateLimitExceeded("gemini", nil), KindRateLimit},
+		{"model not found", ErrModelNotFound("x", "gemini", nil), KindModelNotFound},
+		{"empty response", ErrEmptyResponse("gemini", nil), KindEmptyResponse},
+		{"wrapped", fmt.Errorf("outer: %w", ErrRateLimitExceeded("gemini", nil)), KindRateLimit},
+		{"plain error", errors.New("boom"), KindUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KindOf(tt.err); got != tt.want {
+				t.Errorf("KindOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorHints(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantHint string
+	}{
+		{
+			name:     "claude not available",
+			err:      ErrProviderNotAvailable("claude", errors.New("not found")),
+			wantHint: "install the claude CLI (https://docs.anthropic.com/en/docs/claude-code) or set ANTHROPIC_API_KEY",
+		},
+		{
+			name:     "gemini not available",
+			err:      ErrProviderNotAvailable("gemini", nil),
+			wantHint: "set SMIX_GEMINI_API_KEY or install the gemini CLI (https://github.com/google-gemini/gemini-cli)",
+		},
+		{
+			name:     "unknown provider not available",
+			err:      ErrProviderNotAvailable("other", nil),
+			wantHint: "run 'smix doctor' to check provider setup",
+		},
+		{
+			name:     "claude authentication",
+			err:      ErrAuthenticationFailed("claude", nil),
+			wantHint: "check ANTHROPIC_API_KEY, or run 'claude' to log in with the CLI",
+		},
+		{
+			name:     "gemini authentication",
+			err:      ErrAuthenticationFailed("gemini", errors.New("invalid key")),
+			wantHint: "set SMIX_GEMINI_API_KEY to a valid Gemini API key",
+		},
+		{
+			name:     "rate limit",
+			err:      ErrRateLimitExceeded("gemini", nil),
+			wantHint: "wait a moment and retry, or switch providers with --provider",
+		},
+		{
+			name:     "model not found",
+			err:      ErrModelNotFound("invalid-model", "claude", nil),
+			wantHint: "run 'smix providers' to list available models",
+		},
+		{
+			name:     "empty response",
+			err:      ErrEmptyResponse("gemini", nil),
+			wantHint: "try rephrasing the request or a different model with --model",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HintOf(tt.err); got != tt.wantHint {
+				t.Errorf("HintOf() = %q, want %q", got, tt.wantHint)
+			}
+
+			// Hints survive wrapping by callers
+			wrapped := fmt.Errorf("failed to get provider: %w", tt.err)
+			if got := HintOf(wrapped); got != tt.wantHint {
+				t.Errorf("HintOf(wrapped) = %q, want %q", got, tt.wantHint)
+			}
+		})
+	}
+
+	if got := HintOf(errors.New("plain")); got != "" {
+		t.Errorf("HintOf(plain error) = %q, want empty", got)
+	}
+}
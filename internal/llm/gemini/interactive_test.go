@@ -128,3 +128,39 @@ func TestGeminiProvider_HasInteractiveSupport(t *testing.T) {
 		})
 	}
 }
+
+func TestGeminiProvider_ExtraArgs(t *testing.T) {
+	p := &Provider{cliPath: "echo"}
+	if err := p.SetExtraArgs([]string{"--sandbox"}); err != nil {
+		t.Fatalf("SetExtraArgs() error = %v", err)
+	}
+
+	t.Run("generate", func(t *testing.T) {
+		got, err := p.Generate(context.Background(), "question", llm.WithModel(ModelFlash), llm.WithExtraArgs([]string{"--yolo"}))
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if want := "--model " + ModelFlash + " --sandbox --yolo question"; got != want {
+			t.Errorf("command line = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("interactive", func(t *testing.T) {
+		streams, _, out := llm.TestIOStreams()
+		if err := p.RunInteractive(context.Background(), streams, "question", llm.WithModel(ModelFlash), llm.WithExtraArgs([]string{"--yolo"})); err != nil {
+			t.Fatalf("RunInteractive() error = %v", err)
+		}
+		if got, want := strings.TrimSpace(out.String()), "--model "+ModelFlash+" --sandbox --yolo --prompt-interactive question"; got != want {
+			t.Errorf("command line = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("reserved flags are rejected", func(t *testing.T) {
+		if err := p.SetExtraArgs([]string{"-i"}); err == nil {
+			t.Error("SetExtraArgs() accepted -i")
+		}
+		if _, err := p.Generate(context.Background(), "question", llm.WithExtraArgs([]string{"--prompt=other"})); err == nil {
+			t.Error("Generate() accepted a second prompt")
+		}
+	})
+}
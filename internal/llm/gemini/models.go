@@ -10,7 +10,29 @@ const (
 	ModelPro   = "gemini-3-pro-preview"
 )
 
+// ModelAliases maps short names and provider-neutral tier names to full Gemini model IDs
+var ModelAliases = map[string]string{
+	"flash":    ModelFlash,
+	"pro":      ModelPro,
+	"fast":     ModelFlash,
+	"balanced": ModelFlash,
+	"smart":    ModelPro,
+}
+
+// ResolveModel expands a known alias to a full Gemini model ID, returning unknown names unchanged
+func ResolveModel(model string) string {
+	if resolved, ok := ModelAliases[model]; ok {
+		return resolved
+	}
+	return model
+}
+
 // DefaultModel returns the default Gemini model
 func DefaultModel() string {
 	return ModelFlash
 }
+
+// Models returns the known Gemini model names
+func Models() []string {
+	return []string{ModelFlash, ModelPro}
+}
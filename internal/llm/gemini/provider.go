@@ -15,21 +15,42 @@ import (
 
 const ProviderGemini = "gemini"
 
-// Provider implements the llm.Provider interface for Gemini API
+// Provider implements the llm.Provider interface for Gemini API.
+//
+// Generate backend precedence:
+//  1. WithBackend option on the call
+//  2. Preferred backend set with SetPreferredBackend (providers.gemini.prefer in config)
+//  3. The API when an API key is configured, otherwise the CLI
 type Provider struct {
-	client  *genai.Client
-	apiKey  string
-	cliPath string // Optional: path to gemini CLI for interactive mode
+	client    *genai.Client
+	apiKey    string
+	cliPath   string // Optional: path to gemini CLI for interactive mode
+	preferred string // Optional: llm.BackendAPI or llm.BackendCLI
+	extraArgs []string
 }
 
+// reservedArgs are the gemini CLI flags the provider sets itself
+var reservedArgs = []string{"--model", "-m", "--prompt", "-p", "--prompt-interactive", "-i"}
+
 // Verify interface compliance at compile time
 var (
 	_ llm.Provider            = (*Provider)(nil)
 	_ llm.InteractiveProvider = (*Provider)(nil)
+	_ llm.ModelLister         = (*Provider)(nil)
+	_ llm.ModelResolver       = (*Provider)(nil)
+	_ llm.CandidateGenerator  = (*Provider)(nil)
 )
 
-// NewProvider creates a new Gemini provider
+// NewProvider creates a new Gemini provider.
+// The API client outlives ctx: providers are cached by the factory and shared by later calls,
+// so only ctx's values are kept and each Generate call is bounded by its own context instead.
 func NewProvider(ctx context.Context, apiKey string) (*Provider, error) {
+	return NewProviderWithHTTP(ctx, apiKey, llm.HTTPConfig{})
+}
+
+// NewProviderWithHTTP creates a new Gemini provider whose API client uses httpCfg's
+// base URL and HTTP client
+func NewProviderWithHTTP(ctx context.Context, apiKey string, httpCfg llm.HTTPConfig) (*Provider, error) {
 	var client *genai.Client
 	var err error
 
@@ -42,9 +63,7 @@ func NewProvider(ctx context.Context, apiKey string) (*Provider, error) {
 	}
 
 	if apiKey != "" {
-		client, err = genai.NewClient(ctx, &genai.ClientConfig{
-			APIKey: apiKey,
-		})
+		client, err = genai.NewClient(context.WithoutCancel(ctx), clientConfig(apiKey, httpCfg))
 		if err != nil {
 			return nil, llm.ErrProviderNotAvailable("gemini", err)
 		}
@@ -57,6 +76,48 @@ func NewProvider(ctx context.Context, apiKey string) (*Provider, error) {
 	}, nil
 }
 
+// clientConfig builds the genai client configuration for apiKey and httpCfg
+func clientConfig(apiKey string, httpCfg llm.HTTPConfig) *genai.ClientConfig {
+	return &genai.ClientConfig{
+		APIKey:      apiKey,
+		HTTPClient:  httpCfg.Client,
+		HTTPOptions: genai.HTTPOptions{BaseURL: httpCfg.BaseURL},
+	}
+}
+
+// SetPreferredBackend sets the backend (llm.BackendAPI or llm.BackendCLI) used when a
+// call does not request one explicitly. An empty string restores the default.
+func (p *Provider) SetPreferredBackend(backend string) error {
+	switch backend {
+	case "", llm.BackendAPI, llm.BackendCLI:
+		p.preferred = backend
+		return nil
+	default:
+		return fmt.Errorf("invalid gemini backend %q: must be %q or %q", backend, llm.BackendAPI, llm.BackendCLI)
+	}
+}
+
+// SetExtraArgs sets arguments added to every gemini CLI invocation, before any passed with
+// llm.WithExtraArgs
+func (p *Provider) SetExtraArgs(args []string) error {
+	if err := llm.CheckExtraArgs(ProviderGemini, args, reservedArgs); err != nil {
+		return err
+	}
+	p.extraArgs = args
+	return nil
+}
+
+// cliArgs builds the gemini CLI arguments: the model, the configured and per-request extra
+// arguments, then the prompt arguments
+func (p *Provider) cliArgs(model string, extra []string, promptArgs ...string) ([]string, error) {
+	if err := llm.CheckExtraArgs(ProviderGemini, extra, reservedArgs); err != nil {
+		return nil, err
+	}
+	args := append([]string{"--model", model}, p.extraArgs...)
+	args = append(args, extra...)
+	return append(args, promptArgs...), nil
+}
+
 // Name returns the provider name
 func (p *Provider) Name() string {
 	return ProviderGemini
@@ -67,6 +128,16 @@ func (p *Provider) DefaultModel() string {
 	return DefaultModel()
 }
 
+// ListModels returns the known Gemini model names
+func (p *Provider) ListModels() []string {
+	return Models()
+}
+
+// ResolveModel expands known Gemini model aliases
+func (p *Provider) ResolveModel(model string) string {
+	return ResolveModel(model)
+}
+
 // ValidateModel checks if a model is valid
 func (p *Provider) ValidateModel(model string) error {
 	return nil // No pre-validation, let API handle it
@@ -77,56 +148,173 @@ func (p *Provider) Generate(ctx context.Context, prompt string, opts ...llm.Opti
 	options := llm.BuildOptions(opts)
 
 	// Use provided model or default
-	modelName := options.Model
+	modelName := ResolveModel(options.Model)
 	if modelName == "" {
 		modelName = p.DefaultModel()
 	}
 
-	// Use CLI if no API client available
-	if p.client == nil {
-		return p.generateViaCLI(ctx, modelName, prompt)
+	backend, err := p.selectBackend(options.Backend)
+	if err != nil {
+		return "", err
+	}
+	if backend == llm.BackendCLI {
+		if !options.JSONMode {
+			return p.generateViaCLI(ctx, modelName, prompt, options.ExtraArgs)
+		}
+		result, err := p.generateViaCLI(ctx, modelName, llm.JSONPrompt(prompt, options.JSONSchema), options.ExtraArgs)
+		if err != nil {
+			return "", err
+		}
+		if extracted, err := llm.ExtractJSON(result); err == nil {
+			return extracted, nil
+		}
+		return result, nil
 	}
 
 	// Execute with retry logic (API path)
-	return llm.RetryWithBackoff(ctx, func(ctx context.Context) (string, error) {
-		resp, err := p.client.Models.GenerateContent(ctx, modelName, genai.Text(prompt), nil)
+	config := generateConfig(options)
+	return llm.RetryWithBackoffN(ctx, options.Retries(), func(ctx context.Context) (string, error) {
+		resp, err := p.client.Models.GenerateContent(ctx, modelName, genai.Text(prompt), config)
 		if err != nil {
 			return "", p.wrapError(err, modelName)
 		}
 
 		// Extract text from response
 		if len(resp.Candidates) == 0 {
-			return "", fmt.Errorf("gemini API returned no candidates")
+			return "", llm.ErrEmptyResponse(ProviderGemini, errors.New("gemini API returned no candidates"))
 		}
 
 		if resp.Candidates[0].Content == nil {
-			return "", fmt.Errorf("gemini API returned nil content")
-		}
-
-		var result strings.Builder
-		for _, part := range resp.Candidates[0].Content.Parts {
-			if part.Text != "" {
-				result.WriteString(part.Text)
-			}
+			return "", llm.ErrEmptyResponse(ProviderGemini, errors.New("gemini API returned nil content"))
 		}
 
-		output := strings.TrimSpace(result.String())
+		output := candidateText(resp.Candidates[0])
 		if output == "" {
-			return "", fmt.Errorf("gemini API returned empty response")
+			return "", llm.ErrEmptyResponse(ProviderGemini, errors.New("gemini API returned empty response"))
 		}
 
 		return output, nil
 	})
 }
 
+// GenerateCandidates samples n answers to prompt. The API backend asks for n candidates in a
+// single request; the CLI backend has no candidate support and generates them one at a time.
+func (p *Provider) GenerateCandidates(ctx context.Context, prompt string, n int, opts ...llm.Option) ([]string, error) {
+	options := llm.BuildOptions(opts)
+
+	backend, err := p.selectBackend(options.Backend)
+	if err != nil {
+		return nil, err
+	}
+	if backend == llm.BackendCLI {
+		return llm.GenerateSequential(ctx, p, prompt, n, opts...)
+	}
+
+	modelName := ResolveModel(options.Model)
+	if modelName == "" {
+		modelName = p.DefaultModel()
+	}
+
+	config := generateConfig(options)
+	if config == nil {
+		config = &genai.GenerateContentConfig{}
+	}
+	config.CandidateCount = int32(n)
+
+	var answers []string
+	_, err = llm.RetryWithBackoffN(ctx, options.Retries(), func(ctx context.Context) (string, error) {
+		resp, err := p.client.Models.GenerateContent(ctx, modelName, genai.Text(prompt), config)
+		if err != nil {
+			return "", p.wrapError(err, modelName)
+		}
+
+		answers = answers[:0]
+		for _, candidate := range resp.Candidates {
+			if output := candidateText(candidate); output != "" {
+				answers = append(answers, output)
+			}
+		}
+		if len(answers) == 0 {
+			return "", llm.ErrEmptyResponse(ProviderGemini, errors.New("gemini API returned no candidates"))
+		}
+		return "", nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return answers, nil
+}
+
+// candidateText joins the text parts of a response candidate
+func candidateText(candidate *genai.Candidate) string {
+	if candidate == nil || candidate.Content == nil {
+		return ""
+	}
+
+	var result strings.Builder
+	for _, part := range candidate.Content.Parts {
+		if part.Text != "" {
+			result.WriteString(part.Text)
+		}
+	}
+	return strings.TrimSpace(result.String())
+}
+
+// generateConfig builds the API request config from options, or nil for defaults
+func generateConfig(options *llm.GenerateOptions) *genai.GenerateContentConfig {
+	if !options.JSONMode && len(options.StopSequences) == 0 {
+		return nil
+	}
+
+	config := &genai.GenerateContentConfig{StopSequences: options.StopSequences}
+	if options.JSONMode {
+		config.ResponseMIMEType = "application/json"
+		config.ResponseJsonSchema = options.JSONSchema
+	}
+	return config
+}
+
+// selectBackend picks the backend for a Generate call following the precedence documented on Provider
+func (p *Provider) selectBackend(requested string) (string, error) {
+	backend := requested
+	if backend == "" {
+		backend = p.preferred
+	}
+	if backend == "" {
+		if p.client != nil {
+			return llm.BackendAPI, nil
+		}
+		return llm.BackendCLI, nil
+	}
+
+	switch backend {
+	case llm.BackendAPI:
+		if p.client == nil {
+			return "", fmt.Errorf("gemini API not available (set %s environment variable)", APIKeyEnvVar)
+		}
+	case llm.BackendCLI:
+		if p.cliPath == "" {
+			return "", fmt.Errorf("gemini CLI not available")
+		}
+	default:
+		return "", fmt.Errorf("invalid gemini backend %q: must be %q or %q", backend, llm.BackendAPI, llm.BackendCLI)
+	}
+
+	return backend, nil
+}
+
 // generateViaCLI runs the gemini CLI in non-interactive mode and returns the output.
-// Command format: gemini --model {model} "{prompt}"
-func (p *Provider) generateViaCLI(ctx context.Context, modelName, prompt string) (string, error) {
+// Command format: gemini --model {model} [extra args...] "{prompt}"
+func (p *Provider) generateViaCLI(ctx context.Context, modelName, prompt string, extra []string) (string, error) {
 	if p.cliPath == "" {
 		return "", fmt.Errorf("gemini CLI not available")
 	}
+	args, err := p.cliArgs(modelName, extra, prompt)
+	if err != nil {
+		return "", err
+	}
 
-	cmd := exec.CommandContext(ctx, p.cliPath, "--model", modelName, prompt)
+	cmd := exec.CommandContext(ctx, p.cliPath, args...)
 	output, err := cmd.Output()
 	if err != nil {
 		var exitErr *exec.ExitError
@@ -138,7 +326,7 @@ func (p *Provider) generateViaCLI(ctx context.Context, modelName, prompt string)
 
 	result := strings.TrimSpace(string(output))
 	if result == "" {
-		return "", fmt.Errorf("gemini CLI returned empty response")
+		return "", llm.ErrEmptyResponse(ProviderGemini, errors.New("gemini CLI returned empty response"))
 	}
 
 	return result, nil
@@ -183,22 +371,32 @@ func (p *Provider) RunInteractive(ctx context.Context, streams *llm.IOStreams, p
 
 	options := llm.BuildOptions(opts)
 
-	model := options.Model
+	model := ResolveModel(options.Model)
 	if model == "" {
 		model = p.DefaultModel()
 	}
 
-	// Build command with model and prompt
-	// gemini CLI uses --model for model and accepts prompt as positional argument
-	cmd := exec.CommandContext(ctx, p.cliPath, "--model", model, "--prompt-interactive", prompt)
+	// Build command with model, extra arguments and prompt
+	// gemini CLI uses --model for model and --prompt-interactive for the initial prompt
+	args, err := p.cliArgs(model, options.ExtraArgs, "--prompt-interactive", prompt)
+	if err != nil {
+		return err
+	}
+	cmd := llm.InteractiveCommand(ctx, p.cliPath, args...)
 
 	// Connect provided streams to allow interactive mode
 	cmd.Stdin = streams.In
 	cmd.Stdout = streams.Out
-	cmd.Stderr = streams.ErrOut
+	stderr := llm.NewStderrTail(streams.ErrOut)
+	cmd.Stderr = stderr
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("gemini CLI interactive mode failed: %w", err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("gemini CLI interactive session interrupted: %w (%w)", ctxErr, err)
+		}
+		// The wrapped *exec.ExitError carries the CLI's exit status; a rate limit reported on
+		// stderr is typed so pr review can fail over to another provider
+		return llm.ClassifyCLIFailure(ProviderGemini, fmt.Errorf("gemini CLI interactive mode failed: %w", err), stderr.String())
 	}
 
 	return nil
@@ -2,10 +2,16 @@ package gemini
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
 
+	"google.golang.org/genai"
+
 	"github.com/connorhough/smix/internal/llm"
 )
 
@@ -119,3 +125,293 @@ func TestGeminiProvider_Generate_NoClientOrCLI(t *testing.T) {
 		t.Errorf("expected error to contain %q, got: %v", expectedMsg, err)
 	}
 }
+
+func TestResolveModel(t *testing.T) {
+	tests := []struct {
+		alias string
+		want  string
+	}{
+		{"flash", ModelFlash},
+		{"pro", ModelPro},
+		{"fast", ModelFlash},
+		{"smart", ModelPro},
+		{"gemini-2.0-flash", "gemini-2.0-flash"},
+		{"unknown-model", "unknown-model"},
+	}
+
+	p := &Provider{}
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			if got := p.ResolveModel(tt.alias); got != tt.want {
+				t.Errorf("ResolveModel(%q) = %q, want %q", tt.alias, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeminiProvider_Generate_ResolvesAlias(t *testing.T) {
+	p := &Provider{cliPath: "echo"}
+
+	result, err := p.Generate(context.Background(), "test-prompt", llm.WithModel("flash"))
+	if err != nil {
+		t.Fatalf("Generate via CLI failed: %v", err)
+	}
+
+	if !strings.Contains(result, "--model "+ModelFlash) {
+		t.Errorf("expected alias to expand to %q, got: %q", ModelFlash, result)
+	}
+}
+
+func TestGeminiProvider_SelectBackend(t *testing.T) {
+	client, err := genai.NewClient(context.Background(), &genai.ClientConfig{APIKey: "test-key"})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		client    *genai.Client
+		cliPath   string
+		preferred string
+		requested string
+		want      string
+		wantErr   bool
+	}{
+		{name: "api by default when key present", client: client, cliPath: "echo", want: llm.BackendAPI},
+		{name: "cli by default without key", cliPath: "echo", want: llm.BackendCLI},
+		{name: "preference forces cli", client: client, cliPath: "echo", preferred: llm.BackendCLI, want: llm.BackendCLI},
+		{name: "option overrides preference", client: client, cliPath: "echo", preferred: llm.BackendCLI, requested: llm.BackendAPI, want: llm.BackendAPI},
+		{name: "forced api without key", cliPath: "echo", requested: llm.BackendAPI, wantErr: true},
+		{name: "forced cli without cli", client: client, requested: llm.BackendCLI, wantErr: true},
+		{name: "invalid backend", client: client, requested: "grpc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Provider{client: tt.client, cliPath: tt.cliPath, preferred: tt.preferred}
+			got, err := p.selectBackend(tt.requested)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got backend %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("selectBackend() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeminiProvider_Generate_ForcedCLI(t *testing.T) {
+	client, err := genai.NewClient(context.Background(), &genai.ClientConfig{APIKey: "test-key"})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// With both backends available, forcing the CLI must not touch the API client
+	p := &Provider{client: client, cliPath: "echo"}
+	if err := p.SetPreferredBackend(llm.BackendCLI); err != nil {
+		t.Fatalf("SetPreferredBackend() error = %v", err)
+	}
+
+	result, err := p.Generate(context.Background(), "test-prompt", llm.WithModel("test-model"))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !strings.Contains(result, "test-prompt") {
+		t.Errorf("expected CLI (echo) output, got %q", result)
+	}
+}
+
+func TestGeminiProvider_SetPreferredBackend_Invalid(t *testing.T) {
+	p := &Provider{}
+	if err := p.SetPreferredBackend("grpc"); err == nil {
+		t.Error("expected error for invalid backend")
+	}
+}
+
+func TestGenerateConfig(t *testing.T) {
+	if cfg := generateConfig(llm.BuildOptions(nil)); cfg != nil {
+		t.Errorf("expected nil config by default, got %+v", cfg)
+	}
+
+	schema := map[string]any{"type": "object"}
+	cfg := generateConfig(llm.BuildOptions([]llm.Option{llm.WithJSONSchema(schema)}))
+	if cfg == nil {
+		t.Fatal("expected config in JSON mode")
+	}
+	if cfg.ResponseMIMEType != "application/json" {
+		t.Errorf("ResponseMIMEType = %q, want application/json", cfg.ResponseMIMEType)
+	}
+	if cfg.ResponseJsonSchema == nil {
+		t.Error("expected schema to be passed through")
+	}
+}
+
+func TestGenerateConfig_StopSequences(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []llm.Option
+		wantJSON bool
+	}{
+		{name: "stop only", opts: []llm.Option{llm.WithStopSequences([]string{"\n\n", "END"})}},
+		{name: "stop with JSON", opts: []llm.Option{llm.WithJSONMode(), llm.WithStopSequences([]string{"\n\n", "END"})}, wantJSON: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := generateConfig(llm.BuildOptions(tt.opts))
+			if cfg == nil {
+				t.Fatal("expected config with stop sequences")
+			}
+			if strings.Join(cfg.StopSequences, ",") != "\n\n,END" {
+				t.Errorf("StopSequences = %q, want [\"\\n\\n\" \"END\"]", cfg.StopSequences)
+			}
+			if got := cfg.ResponseMIMEType == "application/json"; got != tt.wantJSON {
+				t.Errorf("JSON response = %v, want %v", got, tt.wantJSON)
+			}
+		})
+	}
+}
+
+func TestGeminiProvider_GenerateAfterConstructionContextCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, ":generateContent") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"candidates": [{"content": {"role": "model", "parts": [{"text": "pong"}]}}]}`)
+	}))
+	defer server.Close()
+	t.Setenv("GOOGLE_GEMINI_BASE_URL", server.URL)
+
+	// The factory caches providers, so the context used to build one may be gone by the next call
+	buildCtx, cancel := context.WithCancel(context.Background())
+	p, err := NewProvider(buildCtx, "test-key")
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	cancel()
+
+	got, err := p.Generate(context.Background(), "ping", llm.WithBackend(llm.BackendAPI), llm.WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("Generate() after construction context was cancelled: %v", err)
+	}
+	if got != "pong" {
+		t.Errorf("Generate() = %q, want %q", got, "pong")
+	}
+
+	// The per-call context is still honored
+	callCtx, cancelCall := context.WithCancel(context.Background())
+	cancelCall()
+	if _, err := p.Generate(callCtx, "ping", llm.WithBackend(llm.BackendAPI), llm.WithMaxRetries(0)); err == nil {
+		t.Error("Generate() with a cancelled call context should fail")
+	}
+}
+
+func TestGeminiProvider_GenerateCandidates(t *testing.T) {
+	var candidateCount float64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			GenerationConfig struct {
+				CandidateCount float64 `json:"candidateCount"`
+			} `json:"generationConfig"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		candidateCount = body.GenerationConfig.CandidateCount
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"candidates": [
+			{"content": {"role": "model", "parts": [{"text": "first"}]}},
+			{"content": {"role": "model", "parts": [{"text": " "}]}},
+			{"content": {"role": "model", "parts": [{"text": "second"}]}}
+		]}`)
+	}))
+	defer server.Close()
+	t.Setenv("GOOGLE_GEMINI_BASE_URL", server.URL)
+
+	p, err := NewProvider(context.Background(), "test-key")
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	got, err := p.GenerateCandidates(context.Background(), "ideas", 3, llm.WithBackend(llm.BackendAPI), llm.WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("GenerateCandidates() error = %v", err)
+	}
+	if candidateCount != 3 {
+		t.Errorf("candidateCount = %v, want 3", candidateCount)
+	}
+	if strings.Join(got, "|") != "first|second" {
+		t.Errorf("GenerateCandidates() = %q, want empty candidates dropped", got)
+	}
+}
+
+func TestClientConfig(t *testing.T) {
+	client := &http.Client{}
+
+	tests := []struct {
+		name    string
+		httpCfg llm.HTTPConfig
+	}{
+		{name: "defaults", httpCfg: llm.HTTPConfig{}},
+		{name: "base URL and client", httpCfg: llm.HTTPConfig{BaseURL: "https://gemini.internal.example/", Client: client}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := clientConfig("test-key", tt.httpCfg)
+			if cc.APIKey != "test-key" {
+				t.Errorf("APIKey = %q, want %q", cc.APIKey, "test-key")
+			}
+			if cc.HTTPOptions.BaseURL != tt.httpCfg.BaseURL {
+				t.Errorf("HTTPOptions.BaseURL = %q, want %q", cc.HTTPOptions.BaseURL, tt.httpCfg.BaseURL)
+			}
+			if cc.HTTPClient != tt.httpCfg.Client {
+				t.Errorf("HTTPClient = %p, want %p", cc.HTTPClient, tt.httpCfg.Client)
+			}
+		})
+	}
+}
+
+func TestGeminiProvider_Generate_EmptyResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"candidates": [{"content": {"role": "model", "parts": [{"text": "  "}]}}]}`)
+	}))
+	defer server.Close()
+	t.Setenv("GOOGLE_GEMINI_BASE_URL", server.URL)
+
+	apiProvider, err := NewProvider(context.Background(), "test-key")
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		provider *Provider
+		backend  string
+	}{
+		{"api", apiProvider, llm.BackendAPI},
+		{"cli", &Provider{cliPath: "true"}, llm.BackendCLI},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.provider.Generate(context.Background(), "ping", llm.WithBackend(tt.backend), llm.WithMaxRetries(0))
+			if llm.KindOf(err) != llm.KindEmptyResponse {
+				t.Fatalf("Generate() error = %v, want an empty response error", err)
+			}
+			if llm.ProviderOf(err) != ProviderGemini {
+				t.Errorf("ProviderOf() = %q, want %q", llm.ProviderOf(err), ProviderGemini)
+			}
+		})
+	}
+}
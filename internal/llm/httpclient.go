@@ -0,0 +1,34 @@
+package llm
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// HTTPConfig customizes how API-backed providers reach their endpoint
+type HTTPConfig struct {
+	// BaseURL overrides the provider's default API endpoint (empty keeps the default)
+	BaseURL string
+	// Client sends API requests (nil uses the provider's default client)
+	Client *http.Client
+}
+
+// NewHTTPClient returns an HTTP client that sends requests through proxy.
+// An empty proxy returns nil so callers keep their default client, which honors
+// the standard HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
+func NewHTTPClient(proxy string) (*http.Client, error) {
+	if proxy == "" {
+		return nil, nil
+	}
+
+	proxyURL, err := url.Parse(proxy)
+	if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q", proxy)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxyURL)
+
+	return &http.Client{Transport: transport}, nil
+}
@@ -0,0 +1,52 @@
+package llm
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	tests := []struct {
+		name      string
+		proxy     string
+		wantNil   bool
+		wantProxy string
+		wantErr   bool
+	}{
+		{name: "empty keeps default", proxy: "", wantNil: true},
+		{name: "proxy URL", proxy: "http://proxy.example:8080", wantProxy: "http://proxy.example:8080"},
+		{name: "missing scheme", proxy: "proxy.example:8080", wantErr: true},
+		{name: "unparsable", proxy: "http://[::1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewHTTPClient(tt.proxy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewHTTPClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantNil {
+				if client != nil {
+					t.Errorf("NewHTTPClient() = %v, want nil", client)
+				}
+				return
+			}
+
+			transport, ok := client.Transport.(*http.Transport)
+			if !ok {
+				t.Fatalf("Transport = %T, want *http.Transport", client.Transport)
+			}
+			req, _ := http.NewRequest(http.MethodGet, "https://api.example.com", nil)
+			got, err := transport.Proxy(req)
+			if err != nil {
+				t.Fatalf("Proxy() error = %v", err)
+			}
+			if got.String() != tt.wantProxy {
+				t.Errorf("Proxy() = %q, want %q", got, tt.wantProxy)
+			}
+		})
+	}
+}
@@ -17,6 +17,13 @@ type IOStreams struct {
 	// isTerminalFunc allows lazy evaluation and mocking of TTY detection
 	isTerminalFunc func(fd int) bool
 	stdinFd        int
+	stdoutFd       int
+	stderrFd       int
+
+	// colorMode is one of ColorAuto, ColorAlways, or ColorNever
+	colorMode string
+	// getenv allows mocking of NO_COLOR lookups
+	getenv func(key string) string
 }
 
 // NewIOStreams creates IOStreams connected to os.Stdin/Stdout/Stderr.
@@ -27,6 +34,10 @@ func NewIOStreams() *IOStreams {
 		ErrOut:         os.Stderr,
 		isTerminalFunc: term.IsTerminal,
 		stdinFd:        int(os.Stdin.Fd()),
+		stdoutFd:       int(os.Stdout.Fd()),
+		stderrFd:       int(os.Stderr.Fd()),
+		colorMode:      defaultColorMode,
+		getenv:         os.Getenv,
 	}
 }
 
@@ -38,9 +49,19 @@ func (s *IOStreams) IsInteractive() bool {
 	return s.isTerminalFunc(s.stdinFd)
 }
 
+// StdoutIsTerminal returns true if stdout is a TTY.
+func (s *IOStreams) StdoutIsTerminal() bool {
+	return s.isTerminalFunc != nil && s.isTerminalFunc(s.stdoutFd)
+}
+
+// StderrIsTerminal returns true if stderr is a TTY.
+func (s *IOStreams) StderrIsTerminal() bool {
+	return s.isTerminalFunc != nil && s.isTerminalFunc(s.stderrFd)
+}
+
 // TestIOStreams creates IOStreams for testing with in-memory buffers.
 // Returns the streams and the input/output buffers for assertions.
-// Simulates a TTY by default (isTerminalFunc returns true).
+// Simulates a TTY on stdin; output is a buffer, so color is disabled.
 func TestIOStreams() (*IOStreams, *bytes.Buffer, *bytes.Buffer) {
 	in := &bytes.Buffer{}
 	out := &bytes.Buffer{}
@@ -48,8 +69,12 @@ func TestIOStreams() (*IOStreams, *bytes.Buffer, *bytes.Buffer) {
 		In:             in,
 		Out:            out,
 		ErrOut:         out,
-		isTerminalFunc: func(int) bool { return true }, // Simulate TTY for testing
+		isTerminalFunc: func(fd int) bool { return fd == 0 }, // Simulate TTY stdin for testing
 		stdinFd:        0,
+		stdoutFd:       1,
+		stderrFd:       2,
+		colorMode:      ColorAuto,
+		getenv:         func(string) string { return "" },
 	}, in, out
 }
 
@@ -64,5 +89,17 @@ func TestIOStreamsNonInteractive() (*IOStreams, *bytes.Buffer, *bytes.Buffer) {
 		ErrOut:         out,
 		isTerminalFunc: func(int) bool { return false }, // Simulate non-TTY
 		stdinFd:        0,
+		stdoutFd:       1,
+		stderrFd:       2,
+		colorMode:      ColorAuto,
+		getenv:         func(string) string { return "" },
 	}, in, out
 }
+
+// TestIOStreamsTerminal creates IOStreams for testing with every stream simulated as a TTY.
+// Output written to Out and ErrOut is captured in the returned buffer.
+func TestIOStreamsTerminal() (*IOStreams, *bytes.Buffer, *bytes.Buffer) {
+	streams, in, out := TestIOStreams()
+	streams.isTerminalFunc = func(int) bool { return true }
+	return streams, in, out
+}
@@ -0,0 +1,86 @@
+package llm
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// JSONInstruction is appended to prompts for providers without native JSON output support
+const JSONInstruction = "Respond with ONLY a single valid JSON object. Do not include any explanation, markdown, or text outside the JSON object."
+
+// ErrNoJSONObject is returned by ExtractJSON when the text contains no valid JSON object
+var ErrNoJSONObject = errors.New("no JSON object found in response")
+
+// ExtractJSON returns the first valid JSON object embedded in text, ignoring
+// surrounding prose or markdown fences.
+func ExtractJSON(text string) (string, error) {
+	for start := strings.IndexByte(text, '{'); start >= 0; {
+		if end := matchBrace(text[start:]); end > 0 {
+			candidate := text[start : start+end]
+			if json.Valid([]byte(candidate)) {
+				return candidate, nil
+			}
+		}
+
+		next := strings.IndexByte(text[start+1:], '{')
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+
+	return "", ErrNoJSONObject
+}
+
+// matchBrace returns the length of the balanced {...} block at the start of s, or -1
+func matchBrace(s string) int {
+	depth := 0
+	inString := false
+	escaped := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+
+	return -1
+}
+
+// JSONPrompt appends JSON output instructions (and the schema, when given) to prompt.
+// Used by providers that cannot enforce JSON output natively.
+func JSONPrompt(prompt string, schema any) string {
+	var b strings.Builder
+	b.WriteString(prompt)
+	b.WriteString("\n\n")
+	b.WriteString(JSONInstruction)
+	if schema != nil {
+		if encoded, err := json.Marshal(schema); err == nil {
+			b.WriteString("\nThe JSON object must match this JSON schema: ")
+			b.Write(encoded)
+		}
+	}
+	return b.String()
+}
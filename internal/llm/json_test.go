@@ -0,0 +1,58 @@
+package llm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExtractJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"bare object", `{"command":"ls"}`, `{"command":"ls"}`, false},
+		{"wrapped in prose", `Sure! Here you go: {"command":"ls -la"} Hope that helps.`, `{"command":"ls -la"}`, false},
+		{"markdown fence", "```json\n{\"command\": \"du -sh .\"}\n```", `{"command": "du -sh ."}`, false},
+		{"braces inside strings", `{"command":"find . -exec echo {} \\;"}`, `{"command":"find . -exec echo {} \\;"}`, false},
+		{"nested object", `result: {"a":{"b":1}} done`, `{"a":{"b":1}}`, false},
+		{"skips invalid candidate", `use {curly} braces then {"command":"pwd"}`, `{"command":"pwd"}`, false},
+		{"no object", "just text", "", true},
+		{"unbalanced", `{"command":"ls"`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractJSON(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrNoJSONObject) {
+					t.Errorf("expected ErrNoJSONObject, got %v (result %q)", err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExtractJSON() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPrompt(t *testing.T) {
+	schema := map[string]any{"type": "object"}
+	got := JSONPrompt("base prompt", schema)
+
+	if !strings.HasPrefix(got, "base prompt") {
+		t.Errorf("expected original prompt first, got %q", got)
+	}
+	if !strings.Contains(got, JSONInstruction) {
+		t.Error("expected JSON instruction")
+	}
+	if !strings.Contains(got, `{"type":"object"}`) {
+		t.Error("expected encoded schema")
+	}
+}
@@ -0,0 +1,68 @@
+package llmtest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/connorhough/smix/internal/llm"
+)
+
+// Verify interface compliance at compile time
+var _ llm.Clock = (*FakeClock)(nil)
+
+// FakeClock is an llm.Clock whose time only moves when Sleep or Advance is called.
+// Sleep returns immediately after advancing the clock and records the requested duration.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+// NewFakeClock returns a FakeClock set to now
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the fake current time
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Sleep advances the clock by d without waiting, or returns ctx.Err() when ctx is already done
+func (c *FakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	return nil
+}
+
+// Advance moves the clock forward by d
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Sleeps returns the durations passed to Sleep, in call order
+func (c *FakeClock) Sleeps() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.sleeps...)
+}
+
+// UseClock installs clock as llm.DefaultClock for the duration of the test
+func UseClock(t testing.TB, clock llm.Clock) {
+	t.Helper()
+	orig := llm.DefaultClock
+	llm.DefaultClock = clock
+	t.Cleanup(func() { llm.DefaultClock = orig })
+}
@@ -0,0 +1,136 @@
+// Package llmtest provides test doubles for code that depends on llm providers.
+package llmtest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/connorhough/smix/internal/llm"
+)
+
+// Verify interface compliance at compile time
+var (
+	_ llm.Provider            = (*FakeProvider)(nil)
+	_ llm.InteractiveProvider = (*FakeProvider)(nil)
+)
+
+// FakeProvider is a configurable llm.Provider and llm.InteractiveProvider for tests.
+// It returns canned responses in order, records every call, and can inject errors.
+// The zero value is ready to use and is safe for concurrent Generate calls.
+type FakeProvider struct {
+	// ProviderName is returned by Name (default "fake")
+	ProviderName string
+	// Model is returned by DefaultModel (default "fake-model")
+	Model string
+
+	// Responses are returned by successive Generate calls. The last response is repeated
+	// once the list is exhausted; with no responses Generate returns an empty string.
+	Responses []string
+	// Err is returned by Generate instead of a response when set
+	Err error
+	// ValidateErr is returned by ValidateModel
+	ValidateErr error
+	// InteractiveErr is returned by RunInteractive
+	InteractiveErr error
+
+	mu                 sync.Mutex
+	prompts            []string
+	options            []*llm.GenerateOptions
+	interactivePrompts []string
+}
+
+// Generate records the prompt and options and returns the next canned response or Err
+func (f *FakeProvider) Generate(ctx context.Context, prompt string, opts ...llm.Option) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.prompts = append(f.prompts, prompt)
+	f.options = append(f.options, llm.BuildOptions(opts))
+
+	if f.Err != nil {
+		return "", f.Err
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if len(f.Responses) == 0 {
+		return "", nil
+	}
+
+	idx := min(len(f.prompts), len(f.Responses)) - 1
+	return f.Responses[idx], nil
+}
+
+// RunInteractive records the prompt and returns InteractiveErr
+func (f *FakeProvider) RunInteractive(ctx context.Context, streams *llm.IOStreams, prompt string, opts ...llm.Option) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.interactivePrompts = append(f.interactivePrompts, prompt)
+	f.options = append(f.options, llm.BuildOptions(opts))
+	return f.InteractiveErr
+}
+
+// ValidateModel returns ValidateErr
+func (f *FakeProvider) ValidateModel(model string) error {
+	return f.ValidateErr
+}
+
+// DefaultModel returns Model, or "fake-model" when unset
+func (f *FakeProvider) DefaultModel() string {
+	if f.Model == "" {
+		return "fake-model"
+	}
+	return f.Model
+}
+
+// Name returns ProviderName, or "fake" when unset
+func (f *FakeProvider) Name() string {
+	if f.ProviderName == "" {
+		return "fake"
+	}
+	return f.ProviderName
+}
+
+// Prompts returns the prompts passed to Generate, in call order
+func (f *FakeProvider) Prompts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.prompts...)
+}
+
+// InteractivePrompts returns the prompts passed to RunInteractive, in call order
+func (f *FakeProvider) InteractivePrompts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.interactivePrompts...)
+}
+
+// LastOptions returns the options of the most recent call, or nil if there were none
+func (f *FakeProvider) LastOptions() *llm.GenerateOptions {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.options) == 0 {
+		return nil
+	}
+	return f.options[len(f.options)-1]
+}
+
+// Basic returns a view of f that implements only llm.Provider, for exercising
+// code paths taken when a provider has no interactive support
+func (f *FakeProvider) Basic() llm.Provider {
+	return basicProvider{f}
+}
+
+// basicProvider hides the optional interfaces implemented by the wrapped FakeProvider
+type basicProvider struct {
+	fake *FakeProvider
+}
+
+func (b basicProvider) Generate(ctx context.Context, prompt string, opts ...llm.Option) (string, error) {
+	return b.fake.Generate(ctx, prompt, opts...)
+}
+
+func (b basicProvider) ValidateModel(model string) error { return b.fake.ValidateModel(model) }
+func (b basicProvider) DefaultModel() string             { return b.fake.DefaultModel() }
+func (b basicProvider) Name() string                     { return b.fake.Name() }
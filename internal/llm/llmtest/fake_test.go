@@ -0,0 +1,76 @@
+package llmtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/connorhough/smix/internal/llm"
+)
+
+func TestFakeProvider_Responses(t *testing.T) {
+	fake := &FakeProvider{Responses: []string{"first", "second"}}
+
+	for _, want := range []string{"first", "second", "second"} {
+		got, err := fake.Generate(context.Background(), "prompt "+want)
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if got != want {
+			t.Errorf("Generate() = %q, want %q", got, want)
+		}
+	}
+
+	prompts := fake.Prompts()
+	if len(prompts) != 3 || prompts[0] != "prompt first" {
+		t.Errorf("Prompts() = %v", prompts)
+	}
+}
+
+func TestFakeProvider_RecordsOptions(t *testing.T) {
+	fake := &FakeProvider{}
+
+	if _, err := fake.Generate(context.Background(), "p", llm.WithModel("haiku"), llm.WithJSONMode()); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	opts := fake.LastOptions()
+	if opts == nil || opts.Model != "haiku" || !opts.JSONMode {
+		t.Errorf("LastOptions() = %+v", opts)
+	}
+}
+
+func TestFakeProvider_Errors(t *testing.T) {
+	wantErr := llm.ErrRateLimitExceeded("fake", nil)
+	fake := &FakeProvider{Err: wantErr, InteractiveErr: errors.New("session failed")}
+
+	if _, err := fake.Generate(context.Background(), "p"); !errors.Is(err, wantErr) {
+		t.Errorf("Generate() error = %v, want %v", err, wantErr)
+	}
+	if llm.KindOf(wantErr) != llm.KindRateLimit {
+		t.Error("injected errors keep their kind")
+	}
+
+	streams, _, _ := llm.TestIOStreams()
+	if err := fake.RunInteractive(context.Background(), streams, "start"); err == nil {
+		t.Error("expected InteractiveErr from RunInteractive")
+	}
+	if got := fake.InteractivePrompts(); len(got) != 1 || got[0] != "start" {
+		t.Errorf("InteractivePrompts() = %v", got)
+	}
+}
+
+func TestFakeProvider_Basic(t *testing.T) {
+	var provider llm.Provider = &FakeProvider{ProviderName: "claude"}
+	if _, ok := provider.(llm.InteractiveProvider); !ok {
+		t.Error("FakeProvider should implement InteractiveProvider")
+	}
+
+	basic := provider.(*FakeProvider).Basic()
+	if _, ok := basic.(llm.InteractiveProvider); ok {
+		t.Error("Basic() should hide InteractiveProvider")
+	}
+	if basic.Name() != "claude" || basic.DefaultModel() != "fake-model" {
+		t.Errorf("Basic() delegates Name/DefaultModel, got %q/%q", basic.Name(), basic.DefaultModel())
+	}
+}
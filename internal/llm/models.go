@@ -0,0 +1,85 @@
+package llm
+
+import "fmt"
+
+// CheckKnownModel reports whether model is one of the names provider lists through ModelLister.
+// Aliases are expanded with ModelResolver first, and full IDs accepted by ModelIDMatcher pass.
+// Providers without a model list, and empty model names, always pass. Unknown names return ErrModelNotFound with a "did you mean" hint.
+func CheckKnownModel(provider Provider, model string) error {
+	if model == "" {
+		return nil
+	}
+	lister, ok := provider.(ModelLister)
+	if !ok {
+		return nil
+	}
+	known := lister.ListModels()
+	if len(known) == 0 {
+		return nil
+	}
+
+	resolved := model
+	if resolver, ok := provider.(ModelResolver); ok {
+		resolved = resolver.ResolveModel(model)
+	}
+	for _, name := range known {
+		if name == model || name == resolved {
+			return nil
+		}
+	}
+	if matcher, ok := provider.(ModelIDMatcher); ok && matcher.MatchesModelID(resolved) {
+		return nil
+	}
+
+	hint := hintFor(provider.Name(), KindModelNotFound)
+	if suggestion := ClosestModel(model, known); suggestion != "" {
+		hint = fmt.Sprintf("did you mean '%s'? %s", suggestion, hint)
+	}
+	return &ProviderError{
+		Provider: provider.Name(),
+		Kind:     KindModelNotFound,
+		Msg:      fmt.Sprintf("model '%s' not found for provider '%s'", model, provider.Name()),
+		Hint:     hint,
+	}
+}
+
+// ClosestModel returns the name in known with the smallest edit distance to model,
+// or an empty string when no name is close enough to be a likely typo
+func ClosestModel(model string, known []string) string {
+	best, bestDist := "", -1
+	for _, name := range known {
+		if d := levenshtein(model, name); bestDist < 0 || d < bestDist {
+			best, bestDist = name, d
+		}
+	}
+
+	// Allow roughly one edit per three characters, and always at least two
+	maxDist := max(2, len([]rune(model))/3)
+	if bestDist < 0 || bestDist > maxDist {
+		return ""
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,103 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// listingProvider is a Provider with a known model list and aliases
+type listingProvider struct {
+	models  []string
+	aliases map[string]string
+}
+
+func (p *listingProvider) Generate(ctx context.Context, prompt string, opts ...Option) (string, error) {
+	return "", nil
+}
+func (p *listingProvider) ValidateModel(model string) error { return nil }
+func (p *listingProvider) DefaultModel() string             { return p.models[0] }
+func (p *listingProvider) Name() string                     { return "claude" }
+func (p *listingProvider) ListModels() []string             { return p.models }
+func (p *listingProvider) ResolveModel(model string) string {
+	if resolved, ok := p.aliases[model]; ok {
+		return resolved
+	}
+	return model
+}
+
+// matchingProvider also accepts full IDs with a "claude-" prefix
+type matchingProvider struct {
+	listingProvider
+}
+
+func (p *matchingProvider) MatchesModelID(model string) bool {
+	return strings.HasPrefix(model, "claude-")
+}
+
+func TestCheckKnownModel(t *testing.T) {
+	provider := &listingProvider{
+		models:  []string{"haiku", "sonnet", "opus"},
+		aliases: map[string]string{"fast": "haiku"},
+	}
+
+	tests := []struct {
+		name     string
+		provider Provider
+		model    string
+		wantErr  bool
+		wantHint string
+	}{
+		{name: "empty model", provider: provider, model: ""},
+		{name: "known model", provider: provider, model: "sonnet"},
+		{name: "alias", provider: provider, model: "fast"},
+		{name: "typo suggests closest", provider: provider, model: "sonnett", wantErr: true, wantHint: "did you mean 'sonnet'?"},
+		{name: "transposition", provider: provider, model: "hiaku", wantErr: true, wantHint: "did you mean 'haiku'?"},
+		{name: "unrelated name has no suggestion", provider: provider, model: "gpt-4o-mini", wantErr: true},
+		{name: "provider without model list is permissive", provider: &mockInteractiveProvider{}, model: "anything"},
+		{name: "full ID without matcher", provider: provider, model: "claude-sonnet-4-5-20250929", wantErr: true},
+		{name: "full ID accepted by matcher", provider: &matchingProvider{*provider}, model: "claude-sonnet-4-5-20250929"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckKnownModel(tt.provider, tt.model)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckKnownModel(%q) error = %v, wantErr %v", tt.model, err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			if KindOf(err) != KindModelNotFound {
+				t.Errorf("KindOf() = %q, want %q", KindOf(err), KindModelNotFound)
+			}
+			hint := HintOf(err)
+			if tt.wantHint != "" && !strings.HasPrefix(hint, tt.wantHint) {
+				t.Errorf("hint = %q, want prefix %q", hint, tt.wantHint)
+			}
+			if tt.wantHint == "" && strings.Contains(hint, "did you mean") {
+				t.Errorf("hint = %q, want no suggestion", hint)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"sonnet", "sonnet", 0},
+		{"sonnett", "sonnet", 1},
+		{"kitten", "sitting", 3},
+		{"flsh", "flash", 1},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -1,11 +1,43 @@
 package llm
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
 // Option configures provider behavior
 type Option func(*GenerateOptions)
 
+// Backends a provider may use to serve a request
+const (
+	BackendAPI = "api"
+	BackendCLI = "cli"
+)
+
 // GenerateOptions holds configuration for Generate calls
 type GenerateOptions struct {
 	Model string
+	// Backend forces a specific backend (BackendAPI or BackendCLI) for providers that support both
+	Backend string
+	// JSONMode requests a single JSON object as the response
+	JSONMode bool
+	// JSONSchema optionally describes the expected JSON object (a JSON Schema document)
+	JSONSchema any
+	// MaxRetries overrides the number of retries for transient API failures (nil uses DefaultRetries)
+	MaxRetries *int
+	// StopSequences end generation when the model emits any of them. Providers without support ignore them.
+	StopSequences []string
+	// ExtraArgs are passed to CLI-backed providers' command lines before the prompt
+	ExtraArgs []string
+}
+
+// Retries returns the configured retry count, or DefaultRetries when none was set
+func (o *GenerateOptions) Retries() int {
+	if o.MaxRetries == nil {
+		return DefaultRetries
+	}
+	return *o.MaxRetries
 }
 
 // WithModel overrides the model for this generation
@@ -15,6 +47,63 @@ func WithModel(model string) Option {
 	}
 }
 
+// WithBackend forces the backend used by providers that support both an API and a CLI
+func WithBackend(backend string) Option {
+	return func(opts *GenerateOptions) {
+		opts.Backend = backend
+	}
+}
+
+// WithJSONMode requests that the provider respond with a single JSON object
+func WithJSONMode() Option {
+	return func(opts *GenerateOptions) {
+		opts.JSONMode = true
+	}
+}
+
+// WithJSONSchema requests JSON output matching the given JSON Schema document. Implies WithJSONMode.
+func WithJSONSchema(schema any) Option {
+	return func(opts *GenerateOptions) {
+		opts.JSONMode = true
+		opts.JSONSchema = schema
+	}
+}
+
+// WithMaxRetries sets how many times a failed request is retried. 0 disables retries.
+func WithMaxRetries(n int) Option {
+	return func(opts *GenerateOptions) {
+		opts.MaxRetries = &n
+	}
+}
+
+// WithStopSequences stops generation at the first of the given delimiters. The delimiter itself
+// is not included in the response. Providers that cannot stop early ignore this option.
+func WithStopSequences(stop []string) Option {
+	return func(opts *GenerateOptions) {
+		opts.StopSequences = stop
+	}
+}
+
+// WithExtraArgs passes provider-specific flags (e.g. claude's --allowedTools) to CLI-backed
+// providers. Repeated options accumulate; API-backed requests ignore them.
+func WithExtraArgs(args []string) Option {
+	return func(opts *GenerateOptions) {
+		opts.ExtraArgs = append(opts.ExtraArgs, args...)
+	}
+}
+
+// CheckExtraArgs rejects extra CLI arguments that repeat one of the reserved flags a provider
+// sets itself, such as the model or prompt flag. Both "--flag value" and "--flag=value" match.
+func CheckExtraArgs(provider string, args, reserved []string) error {
+	for _, arg := range args {
+		name, _, _ := strings.Cut(arg, "=")
+		if slices.Contains(reserved, name) {
+			return fmt.Errorf("extra argument %q for %s repeats a flag smix sets itself (check providers.%s.extra_args)", arg, provider, provider)
+		}
+	}
+	return nil
+}
+
 // BuildOptions constructs GenerateOptions from Option functions
 // Exported for use by provider implementations
 func BuildOptions(opts []Option) *GenerateOptions {
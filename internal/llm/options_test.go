@@ -0,0 +1,57 @@
+package llm
+
+import "testing"
+
+func TestBuildOptions_StopSequences(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{name: "unset", opts: nil, want: nil},
+		{name: "set", opts: []Option{WithStopSequences([]string{"\n\n", "END"})}, want: []string{"\n\n", "END"}},
+		{name: "last wins", opts: []Option{WithStopSequences([]string{"a"}), WithStopSequences([]string{"b"})}, want: []string{"b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildOptions(tt.opts).StopSequences
+			if len(got) != len(tt.want) {
+				t.Fatalf("StopSequences = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("StopSequences[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckExtraArgs(t *testing.T) {
+	reserved := []string{"--model", "-p"}
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "none", args: nil},
+		{name: "unrelated flags", args: []string{"--allowedTools", "Bash", "--verbose"}},
+		{name: "reserved flag", args: []string{"-p", "prompt"}, wantErr: true},
+		{name: "reserved flag with value", args: []string{"--model=opus"}, wantErr: true},
+		{name: "reserved name as a value", args: []string{"--append-system-prompt", "--model is fixed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckExtraArgs("claude", tt.args, reserved); (err != nil) != tt.wantErr {
+				t.Errorf("CheckExtraArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	got := BuildOptions([]Option{WithExtraArgs([]string{"--a"}), WithExtraArgs([]string{"--b", "1"})}).ExtraArgs
+	if len(got) != 3 || got[0] != "--a" || got[2] != "1" {
+		t.Errorf("ExtraArgs = %q, want the options accumulated", got)
+	}
+}
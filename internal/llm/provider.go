@@ -60,3 +60,25 @@ type InteractiveProvider interface {
 	//   }
 	RunInteractive(ctx context.Context, streams *IOStreams, prompt string, opts ...Option) error
 }
+
+// ModelLister is an optional interface for providers that can report the model
+// names they are known to accept.
+type ModelLister interface {
+	// ListModels returns the known model names for this provider
+	ListModels() []string
+}
+
+// ModelResolver is an optional interface for providers that accept short model
+// aliases (e.g. "flash") and expand them to full model IDs.
+type ModelResolver interface {
+	// ResolveModel expands a known alias to its full model ID.
+	// Unknown names are returned unchanged so full IDs pass through.
+	ResolveModel(model string) string
+}
+
+// ModelIDMatcher is an optional interface for providers that accept full model IDs
+// beyond the names ModelLister reports (e.g. dated Claude snapshots).
+type ModelIDMatcher interface {
+	// MatchesModelID reports whether model is a full model ID the provider accepts
+	MatchesModelID(model string) bool
+}
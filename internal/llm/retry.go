@@ -2,19 +2,96 @@ package llm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 )
 
+// DefaultRetries is the number of retries after the first attempt when no retry count is configured
+const DefaultRetries = 2
+
 const (
-	maxRetries   = 3
 	initialDelay = 1 * time.Second
 	maxDelay     = 30 * time.Second
 	backoffRate  = 2.0
 )
 
+// RetryStats records how much retrying requests needed. Attach one to a context with
+// WithRetryStats and every RetryWithBackoffN call made with that context adds to it.
+// Read the fields once the requests have returned.
+type RetryStats struct {
+	mu sync.Mutex
+	// Attempts counts every call of the retried function
+	Attempts int
+	// Retries counts the attempts made after a failure
+	Retries int
+	// Backoff is the total time spent waiting between attempts
+	Backoff time.Duration
+}
+
+// Summary describes the retries, e.g. "succeeded after 2 retries (3s of backoff)"
+func (s *RetryStats) Summary() string {
+	retries := "retries"
+	if s.Retries == 1 {
+		retries = "retry"
+	}
+	return fmt.Sprintf("succeeded after %d %s (%s of backoff)", s.Retries, retries, s.Backoff)
+}
+
+func (s *RetryStats) recordAttempt(retry bool) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Attempts++
+	if retry {
+		s.Retries++
+	}
+}
+
+func (s *RetryStats) recordBackoff(d time.Duration) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Backoff += d
+}
+
+type retryStatsKey struct{}
+
+// WithRetryStats returns a context whose retried requests record into stats
+func WithRetryStats(ctx context.Context, stats *RetryStats) context.Context {
+	return context.WithValue(ctx, retryStatsKey{}, stats)
+}
+
+// RetryStatsFrom returns the stats attached by WithRetryStats, or nil
+func RetryStatsFrom(ctx context.Context) *RetryStats {
+	stats, _ := ctx.Value(retryStatsKey{}).(*RetryStats)
+	return stats
+}
+
+// permanentError marks an error that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying: RetryWithBackoffN returns it (unwrapped)
+// immediately instead of backing off. Permanent(nil) returns nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
 // RetryWithBackoff executes a function with exponential backoff retry logic.
-// It retries up to maxRetries times with exponential backoff starting
+// It retries up to DefaultRetries times with exponential backoff starting
 // at initialDelay and capping at maxDelay. The delay increases by a factor of
 // backoffRate after each failed attempt
 //
@@ -22,36 +99,53 @@ const (
 // 1. Before each attempt
 // 2. During the sleep delay between attempts
 //
+// Errors marked with Permanent are returned at once without retrying.
 // Returns the last error wrapped with retry count if all attempts fail.
 func RetryWithBackoff(ctx context.Context, fn func(context.Context) (string, error)) (string, error) {
+	return RetryWithBackoffN(ctx, DefaultRetries, fn)
+}
+
+// RetryWithBackoffN behaves like RetryWithBackoff but retries up to retries times after the
+// first attempt. A value of 0 (or less) makes a single attempt with no retry. Attempts and
+// backoff are recorded into the context's RetryStats, if any.
+func RetryWithBackoffN(ctx context.Context, retries int, fn func(context.Context) (string, error)) (string, error) {
+	stats := RetryStatsFrom(ctx)
 	var lastErr error
 	delay := initialDelay
+	attempts := max(retries, 0) + 1
 
-	for attempt := range maxRetries {
+	for attempt := range attempts {
 		if err := ctx.Err(); err != nil {
 			return "", err
 		}
 
+		stats.recordAttempt(attempt > 0)
 		result, err := fn(ctx)
 		if err == nil {
 			return result, nil
 		}
 
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return "", permanent.err
+		}
 		lastErr = err
 
 		// Don't sleep after last attempt
-		if attempt < maxRetries-1 {
-			select {
-			case <-time.After(delay):
-				delay = min(
-					time.Duration(float64(delay)*backoffRate),
-					maxDelay,
-				)
-			case <-ctx.Done():
-				return "", ctx.Err()
+		if attempt < attempts-1 {
+			if err := DefaultClock.Sleep(ctx, delay); err != nil {
+				return "", err
 			}
+			stats.recordBackoff(delay)
+			delay = min(
+				time.Duration(float64(delay)*backoffRate),
+				maxDelay,
+			)
 		}
 	}
 
-	return "", fmt.Errorf("failed after %d retries: %w", maxRetries, lastErr)
+	if attempts == 1 {
+		return "", lastErr
+	}
+	return "", fmt.Errorf("failed after %d attempts: %w", attempts, lastErr)
 }
@@ -85,3 +85,72 @@ func TestRetryWithBackoff(t *testing.T) {
 		}
 	})
 }
+
+func TestRetryWithBackoffN(t *testing.T) {
+	tests := []struct {
+		name      string
+		retries   int
+		failures  int
+		wantCalls int
+		wantErr   bool
+	}{
+		{"zero retries makes a single attempt", 0, 5, 1, true},
+		{"negative retries treated as zero", -1, 5, 1, true},
+		{"one retry recovers from one failure", 1, 1, 2, false},
+		{"one retry gives up after two failures", 1, 5, 2, true},
+		{"success needs no retries", 3, 0, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callCount := 0
+			fn := func(ctx context.Context) (string, error) {
+				callCount++
+				if callCount <= tt.failures {
+					return "", errors.New("transient error")
+				}
+				return "success", nil
+			}
+
+			_, err := RetryWithBackoffN(context.Background(), tt.retries, fn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RetryWithBackoffN() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if callCount != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", callCount, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryWithBackoffN_Permanent(t *testing.T) {
+	callCount := 0
+	testErr := errors.New("unauthorized")
+	fn := func(ctx context.Context) (string, error) {
+		callCount++
+		return "", Permanent(testErr)
+	}
+
+	_, err := RetryWithBackoffN(context.Background(), 3, fn)
+	if err != testErr {
+		t.Errorf("RetryWithBackoffN() error = %v, want the unwrapped permanent error", err)
+	}
+	if callCount != 1 {
+		t.Errorf("got %d calls, want 1", callCount)
+	}
+	if Permanent(nil) != nil {
+		t.Error("Permanent(nil) should be nil")
+	}
+}
+
+func TestGenerateOptionsRetries(t *testing.T) {
+	if got := BuildOptions(nil).Retries(); got != DefaultRetries {
+		t.Errorf("default Retries() = %d, want %d", got, DefaultRetries)
+	}
+	if got := BuildOptions([]Option{WithMaxRetries(0)}).Retries(); got != 0 {
+		t.Errorf("WithMaxRetries(0).Retries() = %d, want 0", got)
+	}
+	if got := BuildOptions([]Option{WithMaxRetries(5)}).Retries(); got != 5 {
+		t.Errorf("WithMaxRetries(5).Retries() = %d, want 5", got)
+	}
+}
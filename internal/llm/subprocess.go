@@ -0,0 +1,79 @@
+package llm
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// InterruptWaitDelay is how long an interactive CLI may take to exit after being interrupted before it is killed
+const InterruptWaitDelay = 5 * time.Second
+
+// InteractiveCommand returns an exec.Cmd for an interactive CLI session bound to ctx.
+// When ctx is cancelled (e.g. by Ctrl-C through main's signal context) the child is sent an
+// interrupt so it can shut down cleanly, and is killed if it is still running after
+// InterruptWaitDelay, so no orphaned CLI outlives smix.
+func InteractiveCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Cancel = func() error {
+		// Windows cannot deliver os.Interrupt to another process
+		if runtime.GOOS == "windows" {
+			return cmd.Process.Kill()
+		}
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = InterruptWaitDelay
+	return cmd
+}
+
+// stderrTailSize bounds how much of an interactive CLI's stderr is kept for classifying its exit
+const stderrTailSize = 4096
+
+// StderrTail passes an interactive CLI's stderr through to its destination while keeping the
+// last few KiB, so a failed session can be classified with ClassifyCLIFailure. Using it makes
+// the CLI's stderr a pipe rather than the terminal; its stdout and stdin are unaffected.
+type StderrTail struct {
+	w   io.Writer
+	mu  sync.Mutex
+	buf []byte
+}
+
+// NewStderrTail returns a StderrTail that forwards writes to w
+func NewStderrTail(w io.Writer) *StderrTail {
+	return &StderrTail{w: w}
+}
+
+func (t *StderrTail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > stderrTailSize {
+		t.buf = t.buf[len(t.buf)-stderrTailSize:]
+	}
+	t.mu.Unlock()
+	return t.w.Write(p)
+}
+
+// String returns the retained end of the stderr output
+func (t *StderrTail) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return string(t.buf)
+}
+
+// cliRateLimitPattern matches the rate limit and quota messages the claude and gemini CLIs
+// print before exiting
+var cliRateLimitPattern = regexp.MustCompile(`(?i)rate[ _-]?limit|too many requests|\b429\b|resource[ _]exhausted|quota exceeded|usage limit`)
+
+// ClassifyCLIFailure returns err as ErrRateLimitExceeded for provider when the CLI's stderr
+// reports a rate limit, and err unchanged otherwise
+func ClassifyCLIFailure(provider string, err error, stderr string) error {
+	if err == nil || !cliRateLimitPattern.MatchString(stderr) {
+		return err
+	}
+	return ErrRateLimitExceeded(provider, err)
+}
@@ -0,0 +1,118 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeScript writes an executable shell script to a temp dir and returns its path
+func writeScript(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not supported on windows")
+	}
+	path := filepath.Join(t.TempDir(), "cli")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestInteractiveCommand_InterruptsChildOnCancel(t *testing.T) {
+	// The script exits with 130 on SIGINT, as interactive CLIs do on Ctrl-C
+	script := writeScript(t, `trap 'exit 130' INT
+sleep 5 >/dev/null 2>&1 </dev/null &
+wait`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := InteractiveCommand(ctx, script)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := cmd.Wait()
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Fatalf("child took %v to exit after cancellation", elapsed)
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 130 {
+		t.Errorf("Wait() error = %v, want the child's exit status 130", err)
+	}
+}
+
+func TestInteractiveCommand_KillsChildIgnoringInterrupt(t *testing.T) {
+	script := writeScript(t, `trap '' INT
+sleep 5`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := InteractiveCommand(ctx, script)
+	cmd.WaitDelay = 200 * time.Millisecond
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	if err := cmd.Wait(); err == nil {
+		t.Error("expected an error for a killed child")
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("child took %v to be killed after WaitDelay", elapsed)
+	}
+}
+
+func TestClassifyCLIFailure(t *testing.T) {
+	exitErr := errors.New("exit status 1")
+
+	tests := []struct {
+		name   string
+		err    error
+		stderr string
+		want   ErrorKind
+	}{
+		{name: "claude usage limit", err: exitErr, stderr: "Claude usage limit reached. Your limit will reset at 5pm.", want: KindRateLimit},
+		{name: "gemini quota", err: exitErr, stderr: "[API Error: got status: 429 Too Many Requests. RESOURCE_EXHAUSTED]", want: KindRateLimit},
+		{name: "rate_limit_error", err: exitErr, stderr: `{"type":"rate_limit_error"}`, want: KindRateLimit},
+		{name: "other failure", err: exitErr, stderr: "error: unknown option --foo", want: KindUnknown},
+		{name: "no error", err: nil, stderr: "rate limit", want: KindUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyCLIFailure("claude", tt.err, tt.stderr)
+			if KindOf(got) != tt.want {
+				t.Errorf("KindOf(ClassifyCLIFailure()) = %q, want %q", KindOf(got), tt.want)
+			}
+			if tt.err != nil && !errors.Is(got, tt.err) {
+				t.Errorf("ClassifyCLIFailure() = %v, want it to wrap %v", got, tt.err)
+			}
+		})
+	}
+}
+
+func TestStderrTail(t *testing.T) {
+	var out bytes.Buffer
+	tail := NewStderrTail(&out)
+	long := strings.Repeat("x", stderrTailSize)
+	fmt.Fprint(tail, long)
+	fmt.Fprint(tail, "rate limit")
+
+	if out.String() != long+"rate limit" {
+		t.Error("stderr was not passed through unchanged")
+	}
+	if got := tail.String(); len(got) != stderrTailSize || !strings.HasSuffix(got, "rate limit") {
+		t.Errorf("tail has %d bytes ending %q, want the last %d bytes", len(got), got[len(got)-10:], stderrTailSize)
+	}
+}
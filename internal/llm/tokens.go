@@ -0,0 +1,59 @@
+package llm
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultContextTokens is the context window assumed for models missing from contextLimits
+const DefaultContextTokens = 200_000
+
+// contextLimits maps model name prefixes to context windows in tokens. The longest matching
+// prefix wins; aliases such as "sonnet" fall back to DefaultContextTokens.
+var contextLimits = map[string]int{
+	"claude-":          200_000,
+	"gemini-1.5-flash": 1_048_576,
+	"gemini-1.5-pro":   2_097_152,
+	"gemini-2":         1_048_576,
+	"gemini-3":         1_048_576,
+}
+
+// EstimateTokens roughly estimates the tokens in text using the four characters per token
+// heuristic. It is meant for size warnings, not billing.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// ContextLimit returns the context window of model in tokens
+func ContextLimit(model string) int {
+	limit, matched := DefaultContextTokens, 0
+	for prefix, tokens := range contextLimits {
+		if strings.HasPrefix(model, prefix) && len(prefix) > matched {
+			limit, matched = tokens, len(prefix)
+		}
+	}
+	return limit
+}
+
+// CheckPromptSize estimates the tokens in prompt and reports whether they fit in model's
+// context window
+func CheckPromptSize(prompt, model string) (tokens, limit int, fits bool) {
+	tokens, limit = EstimateTokens(prompt), ContextLimit(model)
+	return tokens, limit, tokens <= limit
+}
+
+// TruncateToTokens cuts text to about maxTokens estimated tokens at a character boundary.
+// truncated reports whether anything was removed.
+func TruncateToTokens(text string, maxTokens int) (string, bool) {
+	maxRunes := max(maxTokens, 0) * 4
+	if utf8.RuneCountInString(text) <= maxRunes {
+		return text, false
+	}
+
+	cut := 0
+	for range maxRunes {
+		_, size := utf8.DecodeRuneInString(text[cut:])
+		cut += size
+	}
+	return text[:cut], true
+}
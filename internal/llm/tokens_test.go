@@ -0,0 +1,89 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"a", 1},
+		{"abcd", 1},
+		{"abcde", 2},
+		{strings.Repeat("x", 4000), 1000},
+		{strings.Repeat("é", 8), 2},
+	}
+
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.text); got != tt.want {
+			t.Errorf("EstimateTokens(%d chars) = %d, want %d", len(tt.text), got, tt.want)
+		}
+	}
+}
+
+func TestContextLimit(t *testing.T) {
+	tests := []struct {
+		model string
+		want  int
+	}{
+		{"claude-sonnet-4-5", 200_000},
+		{"gemini-2.5-pro", 1_048_576},
+		{"gemini-1.5-pro-002", 2_097_152},
+		{"sonnet", DefaultContextTokens},
+		{"", DefaultContextTokens},
+	}
+
+	for _, tt := range tests {
+		if got := ContextLimit(tt.model); got != tt.want {
+			t.Errorf("ContextLimit(%q) = %d, want %d", tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestCheckPromptSize(t *testing.T) {
+	if tokens, limit, fits := CheckPromptSize("short prompt", "claude-sonnet-4-5"); !fits || tokens != 3 || limit != 200_000 {
+		t.Errorf("CheckPromptSize(short) = %d, %d, %v", tokens, limit, fits)
+	}
+
+	large := strings.Repeat("word ", 200_000)
+	if tokens, _, fits := CheckPromptSize(large, "claude-sonnet-4-5"); fits || tokens != 250_000 {
+		t.Errorf("CheckPromptSize(large) = %d tokens, fits %v, want 250000 and not fitting", tokens, fits)
+	}
+	if _, _, fits := CheckPromptSize(large, "gemini-2.5-pro"); !fits {
+		t.Error("CheckPromptSize(large) should fit a 1M token window")
+	}
+}
+
+func TestTruncateToTokens(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		maxTokens     int
+		want          string
+		wantTruncated bool
+	}{
+		{name: "fits", text: "abcdefgh", maxTokens: 2, want: "abcdefgh"},
+		{name: "cut", text: "abcdefghij", maxTokens: 2, want: "abcdefgh", wantTruncated: true},
+		{name: "multi-byte characters stay whole", text: strings.Repeat("é", 6), maxTokens: 1, want: "éééé", wantTruncated: true},
+		{name: "negative budget", text: "abc", maxTokens: -1, want: "", wantTruncated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := TruncateToTokens(tt.text, tt.maxTokens)
+			if got != tt.want || truncated != tt.wantTruncated {
+				t.Errorf("TruncateToTokens() = %q, %v, want %q, %v", got, truncated, tt.want, tt.wantTruncated)
+			}
+		})
+	}
+
+	large := strings.Repeat("line of context\n", 100_000)
+	got, truncated := TruncateToTokens(large, 1000)
+	if !truncated || EstimateTokens(got) != 1000 {
+		t.Errorf("TruncateToTokens(large) kept %d tokens, truncated %v, want 1000", EstimateTokens(got), truncated)
+	}
+}
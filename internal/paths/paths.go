@@ -0,0 +1,49 @@
+// Package paths resolves the XDG base directories smix keeps its files in.
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// appDir is the directory name used under each base directory
+const appDir = "smix"
+
+// ConfigDir returns $XDG_CONFIG_HOME/smix, falling back to ~/.config/smix
+func ConfigDir() (string, error) {
+	return appPath("XDG_CONFIG_HOME", ".config")
+}
+
+// DataDir returns $XDG_DATA_HOME/smix, falling back to ~/.local/share/smix
+func DataDir() (string, error) {
+	return appPath("XDG_DATA_HOME", filepath.Join(".local", "share"))
+}
+
+// CacheDir returns $XDG_CACHE_HOME/smix, falling back to ~/.cache/smix
+func CacheDir() (string, error) {
+	return appPath("XDG_CACHE_HOME", ".cache")
+}
+
+// EnsureParent creates the directory containing path, readable only by the user when new
+func EnsureParent(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	return nil
+}
+
+// appPath returns the smix directory under the base named by envVar, or under fallback in the
+// home directory when the variable is unset. Relative values are ignored, as the XDG spec requires.
+func appPath(envVar, fallback string) (string, error) {
+	base := os.Getenv(envVar)
+	if !filepath.IsAbs(base) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		base = filepath.Join(home, fallback)
+	}
+	return filepath.Join(base, appDir), nil
+}
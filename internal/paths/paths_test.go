@@ -0,0 +1,67 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	dirs := []struct {
+		name     string
+		envVar   string
+		resolve  func() (string, error)
+		fallback string
+	}{
+		{name: "config", envVar: "XDG_CONFIG_HOME", resolve: ConfigDir, fallback: filepath.Join(home, ".config", "smix")},
+		{name: "data", envVar: "XDG_DATA_HOME", resolve: DataDir, fallback: filepath.Join(home, ".local", "share", "smix")},
+		{name: "cache", envVar: "XDG_CACHE_HOME", resolve: CacheDir, fallback: filepath.Join(home, ".cache", "smix")},
+	}
+
+	for _, dir := range dirs {
+		tests := []struct {
+			name  string
+			value string
+			want  string
+		}{
+			{name: "set", value: "/xdg/base", want: filepath.Join("/xdg/base", "smix")},
+			{name: "unset", value: "", want: dir.fallback},
+			{name: "relative ignored", value: "relative/base", want: dir.fallback},
+		}
+
+		for _, tt := range tests {
+			t.Run(dir.name+"/"+tt.name, func(t *testing.T) {
+				t.Setenv(dir.envVar, tt.value)
+
+				got, err := dir.resolve()
+				if err != nil {
+					t.Fatalf("error = %v", err)
+				}
+				if got != tt.want {
+					t.Errorf("%s = %q, want %q", dir.envVar, got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestEnsureParent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a", "b", "file.jsonl")
+
+	if err := EnsureParent(path); err != nil {
+		t.Fatalf("EnsureParent() error = %v", err)
+	}
+	info, err := os.Stat(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("parent not created: %v", err)
+	}
+	if !info.IsDir() || info.Mode().Perm() != 0o700 {
+		t.Errorf("parent mode = %v, want a 0700 directory", info.Mode())
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("EnsureParent() should not create the file itself, stat error = %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package pr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/google/go-github/github"
+
+	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/providers"
+)
+
+// CommentPrompt is the feedback prompt built for a single review comment by FetchCommentPrompt
+type CommentPrompt struct {
+	Item FeedbackItem
+	// Content is the prompt in the same format as the files FetchReviews writes
+	Content string
+	// URL links to the review comment on GitHub
+	URL string
+}
+
+// batchInstructions follow the feedback prompt when a comment is applied with a single
+// Generate call, since the provider cannot edit files itself. The closing report is the one
+// ParseDecision reads.
+const batchInstructions = `
+## Batch Mode
+
+You cannot edit files in this mode. If you decide to APPLY, give the change as a unified diff
+against the target file under "Changes Made" instead of editing it, then document your decision
+in the format above.
+
+End your response with this report:
+
+**STATUS:** [APPLIED | REJECTED | SKIPPED]
+**FILE:** [File Path]
+**ACTION TAKEN:** [One sentence summary, e.g., "Proposed a diff that checks the returned error."]
+**REASONING:** [Brief explanation of why you made this decision.]
+`
+
+// FetchCommentPrompt fetches the gemini-code-assist review comment commentID on a pull request
+// and builds its prompt without writing any files. opts supplies the snippet, conventions and
+// rate limit settings; its filters other than Since are ignored.
+func FetchCommentPrompt(ctx context.Context, client *github.Client, repoOwner, repoName string, prNumber int, commentID int64, opts FetchOptions) (*CommentPrompt, error) {
+	progress := opts.Progress
+	if progress == nil {
+		progress = io.Discard
+	}
+	opts.CommentIDs = []int64{commentID}
+	opts.PathFilters = nil
+	opts.NoGeneral = false
+
+	pr, items, err := collectFeedback(ctx, client, repoOwner, repoName, prNumber, opts, progress)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("comment %d is not a gemini-code-assist review comment on PR #%d in %s/%s", commentID, prNumber, repoOwner, repoName)
+	}
+	item := items[0]
+
+	ref := pr.GetHead().GetSHA()
+	var content string
+	if item.File != "" {
+		contents, err := fetchFileContents(ctx, client.Repositories, progress, repoOwner, repoName, ref, []string{item.File}, 1)
+		if err != nil {
+			return nil, err
+		}
+		content = contents[item.File]
+	}
+
+	snippet, startLine := promptSnippet(content, item.Line, opts)
+	prompt := generatePatchPrompt(
+		repoOwner, repoName, prNumber,
+		item.File, item.Body, snippet,
+		startLine, item.DiffHunk, commentURL(repoOwner, repoName, prNumber, item), item.Lines, item.Replies,
+	)
+	if opts.Conventions {
+		prompt += conventionsSection(fetchConventions(ctx, client.Repositories, repoOwner, repoName, ref))
+	}
+
+	warnPromptSize(fmt.Sprintf("comment %d", commentID), prompt)
+
+	return &CommentPrompt{Item: item, Content: prompt, URL: commentURL(repoOwner, repoName, prNumber, item)}, nil
+}
+
+// PreviewPrompt renders prompt for reading in a terminal, coloring the lines of its diff
+// blocks with st. The rest of the prompt is returned unchanged.
+func PreviewPrompt(prompt string, st llm.Styler) string {
+	lines := strings.Split(prompt, "\n")
+	inDiff := false
+	for i, line := range lines {
+		switch {
+		case !inDiff && strings.TrimSpace(line) == "```diff":
+			inDiff = true
+		case inDiff && strings.TrimSpace(line) == "```":
+			inDiff = false
+		case inDiff:
+			lines[i] = st.Diff(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ApplyOptions controls how ApplyComment acts on a comment prompt
+type ApplyOptions struct {
+	// Batch sends the prompt in a single Generate call and prints the response instead of
+	// launching an interactive session
+	Batch bool
+	// PromptTemplate is the path of a custom session prompt template (empty uses the built-in one)
+	PromptTemplate string
+	// Output receives the batch response (nil uses os.Stdout)
+	Output io.Writer
+	// Dir is the review directory whose decisions.json records the batch decision (empty skips
+	// recording)
+	Dir string
+}
+
+// ApplyComment acts on a single comment prompt with the configured provider (claude by default),
+// either in an interactive session or, with opts.Batch, a single Generate call
+func ApplyComment(ctx context.Context, prompt *CommentPrompt, cfg *config.ProviderConfig, opts ApplyOptions) error {
+	providerName := cfg.Provider
+	if providerName == "" {
+		providerName = "claude"
+	}
+	provider, err := providers.GetProvider(ctx, providerName)
+	if err != nil {
+		return fmt.Errorf("failed to get %s provider: %w", providerName, err)
+	}
+	if err := providers.CheckModel(provider, cfg); err != nil {
+		return err
+	}
+
+	if opts.Batch {
+		out := opts.Output
+		if out == nil {
+			out = os.Stdout
+		}
+		return applyBatch(ctx, provider, prompt, cfg, out, opts.Dir)
+	}
+
+	promptTmpl, err := LoadPromptTemplate(opts.PromptTemplate)
+	if err != nil {
+		return err
+	}
+	return applyInteractive(ctx, provider, llm.NewIOStreams(), prompt, cfg, promptTmpl)
+}
+
+// applyBatch sends the comment prompt to provider in a single Generate call and writes the
+// response, which documents the decision and any proposed diff, to out. When dir is set the
+// decision is recorded in its decisions.json under the comment's prompt file name; a response
+// without a decision is recorded as FAILED.
+func applyBatch(ctx context.Context, provider llm.Provider, prompt *CommentPrompt, cfg *config.ProviderConfig, out io.Writer, dir string) error {
+	var opts []llm.Option
+	if cfg.Model != "" {
+		opts = append(opts, llm.WithModel(cfg.Model))
+	}
+	if cfg.Retries != nil {
+		opts = append(opts, llm.WithMaxRetries(*cfg.Retries))
+	}
+
+	response, err := provider.Generate(ctx, prompt.Content+batchInstructions, opts...)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, response)
+
+	if dir == "" {
+		return nil
+	}
+	decision, err := ParseDecision(response)
+	if err != nil {
+		decision = Decision{Status: StatusFailed, File: prompt.Item.File, Reasoning: err.Error()}
+	}
+	decision.FeedbackFile = promptFileFor(dir, prompt)
+	if err := RecordDecision(dir, decision); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Recorded %s for %s in %s\n", decision.Status, decision.FeedbackFile, filepath.Join(dir, DecisionsFile))
+	return nil
+}
+
+// promptFileFor returns the name of the comment's prompt file in the review directory dir, as
+// listed in its index.json, or the name a review of only this comment would give it
+func promptFileFor(dir string, prompt *CommentPrompt) string {
+	var entries []IndexEntry
+	if data, err := os.ReadFile(filepath.Join(dir, IndexFileJSON)); err == nil && json.Unmarshal(data, &entries) == nil {
+		for _, entry := range entries {
+			if prompt.URL != "" && entry.CommentURL == prompt.URL {
+				return entry.PromptFile
+			}
+		}
+	}
+	return promptFileName(0, prompt.Item)
+}
+
+// applyInteractive runs one interactive session for the comment prompt. The prompt is written
+// to a temporary file for the session to read and removed afterwards.
+func applyInteractive(ctx context.Context, provider llm.Provider, streams *llm.IOStreams, prompt *CommentPrompt, cfg *config.ProviderConfig, promptTmpl *template.Template) error {
+	file, err := os.CreateTemp("", fmt.Sprintf("smix-pr-comment-%d-*.md", prompt.Item.CommentID))
+	if err != nil {
+		return fmt.Errorf("failed to create prompt file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(prompt.Content); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write prompt file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write prompt file: %w", err)
+	}
+
+	return LaunchClaudeCode(ctx, provider, streams, file.Name(), prompt.Item.File, 1, 1, cfg, promptTmpl)
+}
@@ -0,0 +1,133 @@
+package pr
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/llm/llmtest"
+)
+
+func TestFetchCommentPrompt(t *testing.T) {
+	client := newTestGitHubClient(t, fakePRHandler())
+
+	t.Run("matching comment", func(t *testing.T) {
+		prompt, err := FetchCommentPrompt(context.Background(), client, "o", "r", 1, 7, FetchOptions{FullFileUnder: DefaultFullFileUnder})
+		if err != nil {
+			t.Fatalf("FetchCommentPrompt() error = %v", err)
+		}
+		if prompt.Item.CommentID != 7 || prompt.Item.File != "main.go" {
+			t.Errorf("item = %+v, want comment 7 on main.go", prompt.Item)
+		}
+		for _, want := range []string{"Check the error.", "`main.go`", "package main"} {
+			if !strings.Contains(prompt.Content, want) {
+				t.Errorf("prompt missing %q:\n%s", want, prompt.Content)
+			}
+		}
+	})
+
+	t.Run("unknown comment", func(t *testing.T) {
+		_, err := FetchCommentPrompt(context.Background(), client, "o", "r", 1, 8, FetchOptions{})
+		if err == nil || !strings.Contains(err.Error(), "comment 8") {
+			t.Errorf("FetchCommentPrompt() error = %v, want comment 8 not found", err)
+		}
+	})
+}
+
+func TestApplyBatch(t *testing.T) {
+	fake := &llmtest.FakeProvider{Responses: []string{"## Decision: APPLY"}}
+	prompt := &CommentPrompt{Item: FeedbackItem{File: "main.go", CommentID: 7}, Content: "Check the error."}
+
+	var out bytes.Buffer
+	if err := applyBatch(context.Background(), fake, prompt, &config.ProviderConfig{Model: "m"}, &out, ""); err != nil {
+		t.Fatalf("applyBatch() error = %v", err)
+	}
+
+	prompts := fake.Prompts()
+	if len(prompts) != 1 || !strings.HasPrefix(prompts[0], "Check the error.") || !strings.Contains(prompts[0], "## Batch Mode") {
+		t.Errorf("prompts = %q, want the comment prompt followed by the batch instructions", prompts)
+	}
+	if got := fake.LastOptions().Model; got != "m" {
+		t.Errorf("model = %q, want m", got)
+	}
+	if out.String() != "## Decision: APPLY\n" {
+		t.Errorf("output = %q", out.String())
+	}
+	if len(fake.InteractivePrompts()) != 0 {
+		t.Error("batch mode launched an interactive session")
+	}
+}
+
+func TestApplyBatchRecordsDecision(t *testing.T) {
+	const url = "https://github.com/o/r/pull/1#discussion_r7"
+	report := "```diff\n-old\n+new\n```\n\n**STATUS:** APPLIED\n**FILE:** main.go\n**ACTION TAKEN:** Proposed a diff.\n**REASONING:** The error was ignored."
+
+	tests := []struct {
+		name     string
+		response string
+		index    string
+		want     Decision
+	}{
+		{
+			name:     "decision without index.json",
+			response: report,
+			want:     Decision{FeedbackFile: "1_main_go_line2.md", Status: StatusApplied, File: "main.go", Action: "Proposed a diff.", Reasoning: "The error was ignored."},
+		},
+		{
+			name:     "prompt file from index.json",
+			response: report,
+			index:    `[{"index": 3, "file": "main.go", "line": 2, "type": "review_comment", "prompt_file": "3_main_go_line2.md", "comment_url": "` + url + `"}]`,
+			want:     Decision{FeedbackFile: "3_main_go_line2.md", Status: StatusApplied, File: "main.go", Action: "Proposed a diff.", Reasoning: "The error was ignored."},
+		},
+		{
+			name:     "response without decision",
+			response: "I would change the error handling.",
+			want:     Decision{FeedbackFile: "1_main_go_line2.md", Status: StatusFailed, File: "main.go", Reasoning: ErrNoDecision.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.index != "" {
+				if err := os.WriteFile(filepath.Join(dir, IndexFileJSON), []byte(tt.index), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			fake := &llmtest.FakeProvider{Responses: []string{tt.response}}
+			prompt := &CommentPrompt{Item: FeedbackItem{File: "main.go", Line: 2, CommentID: 7}, Content: "Check the error.", URL: url}
+
+			var out bytes.Buffer
+			if err := applyBatch(context.Background(), fake, prompt, &config.ProviderConfig{}, &out, dir); err != nil {
+				t.Fatalf("applyBatch() error = %v", err)
+			}
+
+			decisions, err := LoadDecisions(dir)
+			if err != nil {
+				t.Fatalf("LoadDecisions() error = %v", err)
+			}
+			if len(decisions) != 1 || decisions[0] != tt.want {
+				t.Errorf("decisions = %+v, want [%+v]", decisions, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreviewPrompt(t *testing.T) {
+	prompt := "## Reviewer Feedback\n\n- remove this\n\n```diff\n@@ -1 +1 @@\n-old\n+new\n```\n\n- **Target File:** `main.go`"
+
+	if got := PreviewPrompt(prompt, llm.Styler{}); got != prompt {
+		t.Errorf("PreviewPrompt() without color = %q, want it unchanged", got)
+	}
+
+	got := PreviewPrompt(prompt, llm.Styler{Enabled: true})
+	want := "## Reviewer Feedback\n\n- remove this\n\n```diff\n\033[36m@@ -1 +1 @@\033[0m\n\033[31m-old\033[0m\n\033[32m+new\033[0m\n```\n\n- **Target File:** `main.go`"
+	if got != want {
+		t.Errorf("PreviewPrompt() = %q, want %q", got, want)
+	}
+}
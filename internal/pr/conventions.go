@@ -0,0 +1,87 @@
+package pr
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/github"
+)
+
+// ConventionsFile is the repository file embedded in prompts when FetchOptions.Conventions is set
+const ConventionsFile = "CONVENTIONS.md"
+
+// MaxConventionsBytes bounds how much of ConventionsFile is embedded in each prompt
+const MaxConventionsBytes = 8 * 1024
+
+// htmlCommentPattern matches HTML comments, which carry nothing for the agent
+var htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+// fetchConventions returns the condensed ConventionsFile at ref, or "" when the repository has
+// none. Other failures are reported as warnings, since the conventions are only extra context.
+func fetchConventions(ctx context.Context, getter contentsGetter, repoOwner, repoName, ref string) string {
+	file, _, _, err := getter.GetContents(ctx, repoOwner, repoName, ConventionsFile, &github.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		if !isNotFound(err) && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to fetch %s: %v\n", ConventionsFile, err)
+		}
+		return ""
+	}
+	if file == nil {
+		return ""
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to decode %s: %v\n", ConventionsFile, err)
+		return ""
+	}
+	return condenseConventions(content, MaxConventionsBytes)
+}
+
+// condenseConventions drops HTML comments and repeated blank lines, then truncates the result
+// at a line boundary to at most maxBytes, noting the truncation
+func condenseConventions(content string, maxBytes int) string {
+	content = htmlCommentPattern.ReplaceAllString(content, "")
+
+	var lines []string
+	blank := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			if blank || len(lines) == 0 {
+				continue
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		lines = append(lines, line)
+	}
+	condensed := strings.TrimSpace(strings.Join(lines, "\n"))
+
+	if len(condensed) <= maxBytes {
+		return condensed
+	}
+	cut := condensed[:maxBytes]
+	if i := strings.LastIndex(cut, "\n"); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + fmt.Sprintf("\n\n_(truncated; read the full %s in the repository)_", ConventionsFile)
+}
+
+// conventionsSection renders the conventions appended to each prompt, or "" when there are none
+func conventionsSection(conventions string) string {
+	if conventions == "" {
+		return ""
+	}
+	return fmt.Sprintf(`
+## Project Conventions (%s)
+
+> Condensed from the repository at the PR head. Follow these when deciding and applying changes.
+
+%s
+`, ConventionsFile, conventions)
+}
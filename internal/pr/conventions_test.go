@@ -0,0 +1,98 @@
+package pr
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCondenseConventions(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		maxBytes int
+		want     string
+	}{
+		{
+			name:     "drops comments and repeated blank lines",
+			content:  "\n# Conventions\n<!-- maintainers only -->\n\n\n- Wrap errors   \n\n- No os.Exit\n",
+			maxBytes: 100,
+			want:     "# Conventions\n\n- Wrap errors\n\n- No os.Exit",
+		},
+		{
+			name:     "truncates at a line boundary",
+			content:  "line one\nline two\nline three",
+			maxBytes: 12,
+			want:     "line one\n\n_(truncated; read the full CONVENTIONS.md in the repository)_",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := condenseConventions(tt.content, tt.maxBytes); got != tt.want {
+				t.Errorf("condenseConventions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchReviews_Conventions(t *testing.T) {
+	conventions := "# Conventions\n\nReturn errors instead of calling os.Exit.\n"
+	mux := http.NewServeMux()
+	mux.Handle("/", fakePRHandler())
+	mux.HandleFunc("/repos/o/r/contents/CONVENTIONS.md", func(w http.ResponseWriter, r *http.Request) {
+		if ref := r.URL.Query().Get("ref"); ref != "abc123" {
+			t.Errorf("ref = %q, want the PR head", ref)
+		}
+		fmt.Fprintf(w, `{"type": "file", "encoding": "base64", "content": %q}`, base64.StdEncoding.EncodeToString([]byte(conventions)))
+	})
+	client := newTestGitHubClient(t, mux)
+
+	tests := []struct {
+		name    string
+		enabled bool
+	}{
+		{"enabled", true},
+		{"disabled", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputDir := t.TempDir()
+			if err := FetchReviews(context.Background(), client, "o", "r", 1, outputDir, FetchOptions{Conventions: tt.enabled}); err != nil {
+				t.Fatalf("FetchReviews() error = %v", err)
+			}
+
+			content, err := os.ReadFile(filepath.Join(outputDir, "1_main_go_line2.md"))
+			if err != nil {
+				t.Fatalf("prompt file not written: %v", err)
+			}
+			got := strings.Contains(string(content), "## Project Conventions (CONVENTIONS.md)") &&
+				strings.Contains(string(content), "Return errors instead of calling os.Exit.")
+			if got != tt.enabled {
+				t.Errorf("conventions embedded = %v, want %v:\n%s", got, tt.enabled, content)
+			}
+		})
+	}
+}
+
+func TestFetchReviews_ConventionsMissing(t *testing.T) {
+	client := newTestGitHubClient(t, fakePRHandler())
+	outputDir := t.TempDir()
+
+	if err := FetchReviews(context.Background(), client, "o", "r", 1, outputDir, FetchOptions{Conventions: true}); err != nil {
+		t.Fatalf("FetchReviews() error = %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(outputDir, "1_main_go_line2.md"))
+	if err != nil {
+		t.Fatalf("prompt file not written: %v", err)
+	}
+	if strings.Contains(string(content), "## Project Conventions (") {
+		t.Errorf("expected no conventions section without %s:\n%s", ConventionsFile, content)
+	}
+}
@@ -0,0 +1,95 @@
+package pr
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrNoDecision is returned by ParseDecision when a response contains no STATUS line
+var ErrNoDecision = errors.New("no decision block found in response")
+
+// decisionFieldPattern matches one field of the final report requested by the review prompt.
+// Bullets, bold or italic markers around the label, and letter case are tolerated, so
+// "**STATUS:** APPLIED", "- Status: applied" and "**Action Taken**: ..." all match.
+var decisionFieldPattern = regexp.MustCompile(`(?i)^\s*(?:[-*>]\s+)?[*_]*\s*(status|file|action taken|action|reasoning)\s*[*_]*\s*:\s*[*_]*\s*(.*?)\s*$`)
+
+// statusAliases maps the status words an agent may use to the Decision status constants
+var statusAliases = map[string]string{
+	"APPLIED":  StatusApplied,
+	"APPLY":    StatusApplied,
+	"REJECTED": StatusRejected,
+	"REJECT":   StatusRejected,
+	"FAILED":   StatusFailed,
+	"FAIL":     StatusFailed,
+	"SKIPPED":  StatusSkipped,
+	"SKIP":     StatusSkipped,
+}
+
+// ParseDecision extracts the STATUS/FILE/ACTION TAKEN/REASONING report from an agent response.
+// When the response contains several reports, the last one wins. Only STATUS is required;
+// reasoning may continue over the following lines up to a blank line. The returned
+// Decision's FeedbackFile is left for the caller to fill in.
+func ParseDecision(response string) (Decision, error) {
+	var (
+		decision Decision
+		found    bool
+		field    string
+	)
+
+	for _, line := range strings.Split(response, "\n") {
+		match := decisionFieldPattern.FindStringSubmatch(line)
+		if match == nil {
+			// Continue a multi-line reasoning until a blank line
+			if field == "reasoning" && strings.TrimSpace(line) != "" {
+				decision.Reasoning = strings.TrimSpace(decision.Reasoning + " " + strings.TrimSpace(line))
+				continue
+			}
+			field = ""
+			continue
+		}
+
+		field = strings.ToLower(match[1])
+		value := cleanDecisionValue(match[2])
+		switch field {
+		case "status":
+			// A new report starts; earlier ones (e.g. an echoed prompt) are discarded
+			decision = Decision{Status: value}
+			found = true
+		case "file":
+			decision.File = value
+		case "action", "action taken":
+			decision.Action = value
+		case "reasoning":
+			decision.Reasoning = value
+		}
+	}
+
+	if !found {
+		return Decision{}, ErrNoDecision
+	}
+
+	status, ok := statusAliases[strings.ToUpper(decision.Status)]
+	if !ok {
+		return Decision{}, fmt.Errorf("unrecognized decision status %q", decision.Status)
+	}
+	decision.Status = status
+
+	return decision, nil
+}
+
+// cleanDecisionValue strips the brackets, backticks and emphasis agents put around report values
+func cleanDecisionValue(value string) string {
+	value = strings.TrimSpace(value)
+	// "**STATUS: APPLIED**" leaves the closing marker on the value
+	if !strings.HasPrefix(value, "**") {
+		value = strings.TrimSpace(strings.TrimSuffix(value, "**"))
+	}
+	for _, pair := range [][2]string{{"[", "]"}, {"`", "`"}, {"**", "**"}, {"*", "*"}, {"\"", "\""}} {
+		if len(value) >= len(pair[0])+len(pair[1]) && strings.HasPrefix(value, pair[0]) && strings.HasSuffix(value, pair[1]) {
+			value = strings.TrimSpace(value[len(pair[0]) : len(value)-len(pair[1])])
+		}
+	}
+	return value
+}
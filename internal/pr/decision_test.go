@@ -0,0 +1,109 @@
+package pr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseDecision(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     Decision
+		wantErr  error
+	}{
+		{
+			name: "prompt format",
+			response: `I read the file and applied the fix.
+
+**STATUS:** APPLIED
+**FILE:** internal/pr/fetch.go
+**ACTION TAKEN:** Replaced the regex with a bounded one and ran gofmt.
+**REASONING:** The original pattern was vulnerable to catastrophic backtracking.`,
+			want: Decision{
+				Status:    StatusApplied,
+				File:      "internal/pr/fetch.go",
+				Action:    "Replaced the regex with a bounded one and ran gofmt.",
+				Reasoning: "The original pattern was vulnerable to catastrophic backtracking.",
+			},
+		},
+		{
+			name: "bullets, lower case and bracketed values",
+			response: `## Final Report
+- Status: [rejected]
+- File: ` + "`cmd/root.go`" + `
+- Action: No changes made.
+- Reasoning: The suggestion conflicts with the existing flag handling.`,
+			want: Decision{
+				Status:    StatusRejected,
+				File:      "cmd/root.go",
+				Action:    "No changes made.",
+				Reasoning: "The suggestion conflicts with the existing flag handling.",
+			},
+		},
+		{
+			name: "bold wrapping whole line and multi-line reasoning",
+			response: `**STATUS: FAILED**
+**Action Taken**: Tried to update the test but it no longer compiles.
+**Reasoning**: The referenced helper was removed
+in a later commit, so the feedback no longer applies.
+
+Let me know if you want me to try again.`,
+			want: Decision{
+				Status:    StatusFailed,
+				Action:    "Tried to update the test but it no longer compiles.",
+				Reasoning: "The referenced helper was removed in a later commit, so the feedback no longer applies.",
+			},
+		},
+		{
+			name: "last report wins over echoed template",
+			response: `**STATUS:** [APPLIED | REJECTED | FAILED]
+**FILE:** [File Path]
+
+**STATUS:** apply
+**FILE:** main.go`,
+			want: Decision{Status: StatusApplied, File: "main.go"},
+		},
+		{
+			name:     "missing optional fields",
+			response: "STATUS: SKIPPED",
+			want:     Decision{Status: StatusSkipped},
+		},
+		{
+			name: "missing status",
+			response: `**FILE:** main.go
+**ACTION TAKEN:** Nothing.`,
+			wantErr: ErrNoDecision,
+		},
+		{
+			name:     "no report",
+			response: "I could not find the file.",
+			wantErr:  ErrNoDecision,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDecision(tt.response)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseDecision() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDecision() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDecision() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDecision_UnknownStatus(t *testing.T) {
+	_, err := ParseDecision("**STATUS:** MAYBE")
+	if err == nil || errors.Is(err, ErrNoDecision) {
+		t.Fatalf("ParseDecision() error = %v, want an unrecognized status error", err)
+	}
+}
@@ -0,0 +1,43 @@
+package pr
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/connorhough/smix/internal/llm"
+)
+
+// dispatcher distributes feedback items across providers in round-robin order,
+// failing over to the next provider when one reports a rate limit
+type dispatcher struct {
+	providers []llm.Provider
+	next      int
+}
+
+func newDispatcher(providers []llm.Provider) *dispatcher {
+	return &dispatcher{providers: providers}
+}
+
+// Do runs fn with the next provider in the rotation. If fn fails with a rate limit error,
+// the remaining providers are tried in order. It returns the provider that handled the call
+// and fn's error; when every provider is rate limited the last rate limit error is returned.
+func (d *dispatcher) Do(fn func(llm.Provider) error) (llm.Provider, error) {
+	if len(d.providers) == 0 {
+		return nil, errors.New("no providers configured")
+	}
+
+	start := d.next
+	d.next = (d.next + 1) % len(d.providers)
+
+	var err error
+	for i := range d.providers {
+		provider := d.providers[(start+i)%len(d.providers)]
+		err = fn(provider)
+		if llm.KindOf(err) != llm.KindRateLimit {
+			return provider, err
+		}
+		slog.Warn("provider rate limited, trying next provider", "provider", provider.Name(), "error", err)
+	}
+
+	return d.providers[(start+len(d.providers)-1)%len(d.providers)], err
+}
@@ -0,0 +1,96 @@
+package pr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/llm/llmtest"
+)
+
+func TestDispatcher_RoundRobin(t *testing.T) {
+	claude := &llmtest.FakeProvider{ProviderName: "claude"}
+	gemini := &llmtest.FakeProvider{ProviderName: "gemini"}
+	d := newDispatcher([]llm.Provider{claude, gemini})
+
+	var got []string
+	for range 5 {
+		provider, err := d.Do(func(p llm.Provider) error { return nil })
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		got = append(got, provider.Name())
+	}
+
+	want := []string{"claude", "gemini", "claude", "gemini", "claude"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("distribution = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestDispatcher_RateLimitFailover(t *testing.T) {
+	claude := &llmtest.FakeProvider{ProviderName: "claude"}
+	gemini := &llmtest.FakeProvider{ProviderName: "gemini"}
+	d := newDispatcher([]llm.Provider{claude, gemini})
+
+	var calls []string
+	provider, err := d.Do(func(p llm.Provider) error {
+		calls = append(calls, p.Name())
+		if p.Name() == "claude" {
+			return llm.ErrRateLimitExceeded("claude", nil)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if provider.Name() != "gemini" {
+		t.Errorf("handled by %s, want gemini after claude was rate limited", provider.Name())
+	}
+	if len(calls) != 2 || calls[0] != "claude" || calls[1] != "gemini" {
+		t.Errorf("calls = %v, want [claude gemini]", calls)
+	}
+
+	// The rotation still advances by one item, so the next item starts with gemini
+	provider, _ = d.Do(func(p llm.Provider) error { return nil })
+	if provider.Name() != "gemini" {
+		t.Errorf("next item handled by %s, want gemini", provider.Name())
+	}
+}
+
+func TestDispatcher_Errors(t *testing.T) {
+	claude := &llmtest.FakeProvider{ProviderName: "claude"}
+	gemini := &llmtest.FakeProvider{ProviderName: "gemini"}
+	d := newDispatcher([]llm.Provider{claude, gemini})
+
+	t.Run("other errors do not fail over", func(t *testing.T) {
+		calls := 0
+		wantErr := errors.New("session crashed")
+		_, err := d.Do(func(p llm.Provider) error {
+			calls++
+			return wantErr
+		})
+		if !errors.Is(err, wantErr) || calls != 1 {
+			t.Errorf("Do() = %v after %d calls, want session error after 1 call", err, calls)
+		}
+	})
+
+	t.Run("all providers rate limited", func(t *testing.T) {
+		calls := 0
+		_, err := d.Do(func(p llm.Provider) error {
+			calls++
+			return llm.ErrRateLimitExceeded(p.Name(), nil)
+		})
+		if llm.KindOf(err) != llm.KindRateLimit || calls != 2 {
+			t.Errorf("Do() = %v after %d calls, want rate limit error after 2 calls", err, calls)
+		}
+	})
+
+	t.Run("no providers", func(t *testing.T) {
+		if _, err := newDispatcher(nil).Do(func(p llm.Provider) error { return nil }); err == nil {
+			t.Error("expected error with no providers")
+		}
+	})
+}
@@ -3,13 +3,23 @@ package pr
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/go-github/github"
+
+	"github.com/connorhough/smix/internal/llm"
 )
 
 // FeedbackItem represents a single feedback item from gemini-code-assist
@@ -20,10 +30,137 @@ type FeedbackItem struct {
 	Body      string `json:"body"`
 	DiffHunk  string `json:"diff_hunk"`  // New field
 	CommentID int64  `json:"comment_id"` // New field
+
+	// Lines lists every line the same feedback was posted on when duplicates are collapsed
+	Lines []int `json:"lines,omitempty"`
+
+	// Replies holds the discussion under a review comment, oldest first
+	Replies []ThreadReply `json:"replies,omitempty"`
+}
+
+// ThreadReply is a reply in a review comment thread
+type ThreadReply struct {
+	Author string `json:"author"`
+	Body   string `json:"body"`
+}
+
+// Output formats supported by FetchReviews
+const (
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+)
+
+// FeedbackFileJSON is the name of the file written when using FormatJSON
+const FeedbackFileJSON = "feedback.json"
+
+// Index files written alongside the prompt files
+const (
+	// IndexFile is the human-readable index
+	IndexFile = "INDEX.md"
+	// IndexFileJSON is the machine-readable index
+	IndexFileJSON = "index.json"
+)
+
+// IndexEntry describes one prompt file in index.json
+type IndexEntry struct {
+	Index      int    `json:"index"`
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Type       string `json:"type"`
+	PromptFile string `json:"prompt_file"`
+	CommentURL string `json:"comment_url,omitempty"`
+}
+
+// DefaultFetchConcurrency is the default number of concurrent file content requests.
+// Kept low to avoid tripping GitHub's secondary rate limits.
+const DefaultFetchConcurrency = 3
+
+// Default number of file lines shown before and after the commented line in prompt snippets
+const (
+	DefaultContextBefore = 10
+	DefaultContextAfter  = 30
+)
+
+// DefaultFullFileUnder is the line count below which prompts embed the whole file instead of a snippet
+const DefaultFullFileUnder = 150
+
+// FetchOptions configures how FetchReviews writes its output
+type FetchOptions struct {
+	// Concurrency bounds the number of in-flight file content requests. Defaults to DefaultFetchConcurrency.
+	Concurrency int
+
+	// Format selects the output format (FormatMarkdown or FormatJSON). Defaults to FormatMarkdown.
+	Format string
+
+	// PathFilters restricts feedback to files matching at least one glob. "**" matches any number of directories.
+	PathFilters []string
+
+	// NoGeneral excludes general PR comments that are not attached to a file
+	NoGeneral bool
+
+	// CommentIDs keeps only the review comments with these IDs. General comments have no ID
+	// and are excluded when any are given.
+	CommentIDs []int64
+
+	// ContextBefore and ContextAfter set how many lines around the commented line are included
+	// in each prompt snippet. Callers typically start from DefaultContextBefore and DefaultContextAfter.
+	ContextBefore int
+	ContextAfter  int
+
+	// FullFileUnder embeds the whole file in a prompt when it has fewer than this many lines,
+	// instead of the ContextBefore/ContextAfter window. Callers typically start from
+	// DefaultFullFileUnder. Zero always uses the window.
+	FullFileUnder int
+
+	// OverlapWindow is how many lines apart comments on the same file can be and still be
+	// reported as overlapping. Callers typically start from DefaultOverlapWindow. Negative
+	// disables the check.
+	OverlapWindow int
+
+	// NoIndex skips writing INDEX.md. index.json is still written.
+	NoIndex bool
+
+	// Conventions embeds the repository's CONVENTIONS.md at the PR head, condensed and truncated
+	// to MaxConventionsBytes, in every prompt
+	Conventions bool
+
+	// GroupOverlapping combines overlapping comments into a single prompt instead of only warning
+	GroupOverlapping bool
+
+	// Progress receives status messages while fetching. Nil discards them.
+	Progress io.Writer
+
+	// Since keeps only comments created or updated after this time. Zero keeps everything.
+	// Review comment threads are kept when any comment in the thread is newer.
+	Since time.Time
+
+	// Limit keeps only the first Limit feedback items, in prompt file order. Zero keeps everything.
+	Limit int
+
+	// RateLimitMaxWait is the longest GitHub calls wait for a rate limit to reset before failing.
+	// Callers typically start from DefaultRateLimitMaxWait. Zero fails immediately.
+	RateLimitMaxWait time.Duration
+}
+
+// FeedbackReport wraps feedback items with PR metadata for JSON output
+type FeedbackReport struct {
+	Repo     string         `json:"repo"`
+	PRNumber int            `json:"pr_number"`
+	Title    string         `json:"title"`
+	Items    []FeedbackItem `json:"items"`
 }
 
 // FetchReviews fetches gemini-code-assist feedback from a GitHub PR
-func FetchReviews(ctx context.Context, client *github.Client, repoOwner, repoName string, prNumber int, outputDir string) error {
+func FetchReviews(ctx context.Context, client *github.Client, repoOwner, repoName string, prNumber int, outputDir string, opts FetchOptions) error {
+	progress := opts.Progress
+	if progress == nil {
+		progress = io.Discard
+	}
+
+	if err := ValidatePathFilters(opts.PathFilters); err != nil {
+		return err
+	}
+
 	if outputDir == "" {
 		outputDir = fmt.Sprintf("./pr_feedback_pr%d", prNumber)
 	}
@@ -33,19 +170,122 @@ func FetchReviews(ctx context.Context, client *github.Client, repoOwner, repoNam
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
+	pr, feedbackItems, err := collectFeedback(ctx, client, repoOwner, repoName, prNumber, opts, progress)
+	if err != nil {
+		return err
+	}
+
+	meta := ReviewMetadata{
+		Repo:      fmt.Sprintf("%s/%s", repoOwner, repoName),
+		PRNumber:  prNumber,
+		HeadSHA:   pr.GetHead().GetSHA(),
+		FetchedAt: time.Now().UTC(),
+	}
+
+	if len(feedbackItems) == 0 {
+		if !opts.Since.IsZero() {
+			fmt.Fprintf(progress, "No new gemini-code-assist feedback since %s for PR #%d\n", opts.Since.Format(time.RFC3339), prNumber)
+			return writeMetadata(outputDir, meta)
+		}
+		fmt.Fprintf(progress, "No gemini-code-assist feedback found for PR #%d\n", prNumber)
+		return writeMetadata(outputDir, meta)
+	}
+
+	feedbackItems = dedupeFeedback(feedbackItems)
+
+	overlaps := findOverlaps(feedbackItems, opts.OverlapWindow)
+	warnOverlaps(feedbackItems, overlaps, opts.GroupOverlapping)
+	if opts.GroupOverlapping {
+		feedbackItems = mergeOverlaps(feedbackItems, overlaps)
+	}
+
+	fmt.Fprintf(progress, "Found %d feedback items\n", len(feedbackItems))
+
+	totalItems := len(feedbackItems)
+	if opts.Limit > 0 && totalItems > opts.Limit {
+		feedbackItems = feedbackItems[:opts.Limit]
+		fmt.Fprintf(progress, "Keeping the first %d of %d feedback items\n", opts.Limit, totalItems)
+	}
+
+	if opts.Format == FormatJSON {
+		report := FeedbackReport{
+			Repo:     fmt.Sprintf("%s/%s", repoOwner, repoName),
+			PRNumber: prNumber,
+			Title:    pr.GetTitle(),
+			Items:    feedbackItems,
+		}
+		jsonPath, err := writeFeedbackJSON(outputDir, report)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(progress, "\n✓ Feedback written to: %s\n", jsonPath)
+		return writeMetadata(outputDir, meta)
+	}
+
+	fmt.Fprintf(progress, "Creating individual prompt files in: %s\n", outputDir)
+
+	// Fetch the content of each referenced file once for snippet context
+	var files []string
+	for _, item := range feedbackItems {
+		if item.File != "" {
+			files = append(files, item.File)
+		}
+	}
+	fileContents, err := fetchFileContents(ctx, client.Repositories, progress, repoOwner, repoName, pr.GetHead().GetSHA(), files, opts.Concurrency)
+	if err != nil {
+		return err
+	}
+
+	var conventions string
+	if opts.Conventions {
+		conventions = fetchConventions(ctx, client.Repositories, repoOwner, repoName, pr.GetHead().GetSHA())
+		if conventions != "" {
+			fmt.Fprintf(progress, "Including %s in each prompt\n", ConventionsFile)
+		}
+	}
+
+	if err := writePromptFiles(progress, outputDir, repoOwner, repoName, prNumber, feedbackItems, fileContents, conventions, opts); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(progress, "\n✓ Created %d prompt files in: %s\n", len(feedbackItems), outputDir)
+
+	// Create an index file
+	if !opts.NoIndex {
+		indexFilePath := filepath.Join(outputDir, IndexFile)
+		indexContent := generateIndexContent(repoOwner, repoName, prNumber, feedbackItems, totalItems)
+		if err := os.WriteFile(indexFilePath, []byte(indexContent), 0o644); err != nil {
+			return fmt.Errorf("failed to create index file: %w", err)
+		}
+		fmt.Fprintf(progress, "✓ Index file created: %s\n", indexFilePath)
+	}
+	if err := writeIndexJSON(outputDir, repoOwner, repoName, prNumber, feedbackItems); err != nil {
+		return err
+	}
+
+	return writeMetadata(outputDir, meta)
+}
+
+// collectFeedback fetches the PR and returns its gemini-code-assist feedback, with replies grouped
+// into their threads and opts' Since, path, general-comment and comment ID filters applied
+func collectFeedback(ctx context.Context, client *github.Client, repoOwner, repoName string, prNumber int, opts FetchOptions, progress io.Writer) (*github.PullRequest, []FeedbackItem, error) {
 	// Verify that the PR is accessible
-	pr, _, err := client.PullRequests.Get(ctx, repoOwner, repoName, prNumber)
+	pr, err := doWithRateLimit(ctx, opts.RateLimitMaxWait, func() (*github.PullRequest, *github.Response, error) {
+		return client.PullRequests.Get(ctx, repoOwner, repoName, prNumber)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get PR #%d in %s/%s: %w", prNumber, repoOwner, repoName, err)
+		return nil, nil, fmt.Errorf("failed to get PR #%d in %s/%s: %w", prNumber, repoOwner, repoName, err)
 	}
-	fmt.Printf("Successfully fetched PR #%d: %s\n", prNumber, pr.GetTitle())
+	fmt.Fprintf(progress, "Successfully fetched PR #%d: %s\n", prNumber, pr.GetTitle())
 
 	// Fetch PR files to get diff hunks
-	prFiles, _, err := client.PullRequests.ListFiles(ctx, repoOwner, repoName, prNumber, &github.ListOptions{})
+	prFiles, err := doWithRateLimit(ctx, opts.RateLimitMaxWait, func() ([]*github.CommitFile, *github.Response, error) {
+		return client.PullRequests.ListFiles(ctx, repoOwner, repoName, prNumber, &github.ListOptions{})
+	})
 	if err != nil {
-		return fmt.Errorf("failed to fetch PR files: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch PR files: %w", err)
 	}
-	fmt.Printf("Fetched %d changed files\n", len(prFiles))
+	fmt.Fprintf(progress, "Fetched %d changed files\n", len(prFiles))
 
 	// Create a map of file paths to diff patches for quick lookup
 	fileDiffs := make(map[string]string)
@@ -56,24 +296,37 @@ func FetchReviews(ctx context.Context, client *github.Client, repoOwner, repoNam
 	}
 
 	// Fetch review comments (inline code comments)
-	reviewComments, _, err := client.PullRequests.ListComments(ctx, repoOwner, repoName, prNumber, &github.PullRequestListCommentsOptions{})
+	reviewComments, err := doWithRateLimit(ctx, opts.RateLimitMaxWait, func() ([]*github.PullRequestComment, *github.Response, error) {
+		return client.PullRequests.ListComments(ctx, repoOwner, repoName, prNumber, &github.PullRequestListCommentsOptions{})
+	})
 	if err != nil {
-		return fmt.Errorf("failed to fetch review comments: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch review comments: %w", err)
 	}
-	fmt.Printf("Fetched %d review comments\n", len(reviewComments))
+	fmt.Fprintf(progress, "Fetched %d review comments\n", len(reviewComments))
 
 	// Fetch issue comments (general PR comments)
-	issueComments, _, err := client.Issues.ListComments(ctx, repoOwner, repoName, prNumber, &github.IssueListCommentsOptions{})
+	issueComments, err := doWithRateLimit(ctx, opts.RateLimitMaxWait, func() ([]*github.IssueComment, *github.Response, error) {
+		return client.Issues.ListComments(ctx, repoOwner, repoName, prNumber, &github.IssueListCommentsOptions{})
+	})
 	if err != nil {
-		return fmt.Errorf("failed to fetch issue comments: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch issue comments: %w", err)
 	}
-	fmt.Printf("Fetched %d issue comments\n", len(issueComments))
+	fmt.Fprintf(progress, "Fetched %d issue comments\n", len(issueComments))
 
 	// Filter comments from gemini-code-assist bot
 	var feedbackItems []FeedbackItem
 
-	// Process review comments
+	// Process review comments. Replies are attached to the comment that started their thread
+	// rather than becoming items of their own.
+	threadReplies := groupReplies(reviewComments)
+	activity := threadActivity(reviewComments)
 	for _, comment := range reviewComments {
+		if comment.InReplyTo != nil {
+			continue
+		}
+		if !opts.Since.IsZero() && !activity[comment.GetID()].After(opts.Since) {
+			continue
+		}
 		if comment.User != nil && comment.User.Login != nil && strings.Contains(*comment.User.Login, "gemini-code-assist") {
 			line := 0
 			if comment.Position != nil {
@@ -100,6 +353,7 @@ func FetchReviews(ctx context.Context, client *github.Client, repoOwner, repoNam
 				Body:      *comment.Body,
 				DiffHunk:  diffHunk,
 				CommentID: commentID,
+				Replies:   threadReplies[commentID],
 			})
 		}
 	}
@@ -107,6 +361,9 @@ func FetchReviews(ctx context.Context, client *github.Client, repoOwner, repoNam
 	// Process issue comments, excluding summaries
 	for _, comment := range issueComments {
 		if comment.User != nil && comment.User.Login != nil && strings.Contains(*comment.User.Login, "gemini-code-assist") {
+			if !opts.Since.IsZero() && !latest(comment.CreatedAt, comment.UpdatedAt).After(opts.Since) {
+				continue
+			}
 			body := *comment.Body
 			// Exclude summary comments
 			if !strings.HasPrefix(body, "## Code Review") && !strings.HasPrefix(body, "## Summary") {
@@ -120,103 +377,409 @@ func FetchReviews(ctx context.Context, client *github.Client, repoOwner, repoNam
 		}
 	}
 
-	if len(feedbackItems) == 0 {
-		fmt.Printf("No gemini-code-assist feedback found for PR #%d\n", prNumber)
-		return nil
+	feedbackItems = filterFeedback(feedbackItems, opts.PathFilters, opts.NoGeneral)
+	feedbackItems = filterCommentIDs(feedbackItems, opts.CommentIDs)
+
+	return pr, feedbackItems, nil
+}
+
+// groupReplies maps the ID of each thread's first comment to the replies in that thread, in
+// the order GitHub returned them. Reply chains are followed back to the first comment.
+func groupReplies(comments []*github.PullRequestComment) map[int64][]ThreadReply {
+	parent := replyParents(comments)
+	replies := make(map[int64][]ThreadReply)
+	for _, comment := range comments {
+		if comment.InReplyTo == nil {
+			continue
+		}
+
+		root := threadRoot(parent, comment.GetInReplyTo())
+		replies[root] = append(replies[root], ThreadReply{
+			Author: comment.GetUser().GetLogin(),
+			Body:   comment.GetBody(),
+		})
+	}
+	return replies
+}
+
+// threadActivity maps the ID of each thread's first comment to the latest time any comment
+// in the thread was created or updated
+func threadActivity(comments []*github.PullRequestComment) map[int64]time.Time {
+	parent := replyParents(comments)
+	activity := make(map[int64]time.Time)
+	for _, comment := range comments {
+		root := comment.GetID()
+		if comment.InReplyTo != nil {
+			root = threadRoot(parent, comment.GetInReplyTo())
+		}
+		if t := latest(comment.CreatedAt, comment.UpdatedAt); t.After(activity[root]) {
+			activity[root] = t
+		}
 	}
+	return activity
+}
 
-	fmt.Printf("Found %d feedback items\n", len(feedbackItems))
-	fmt.Printf("Creating individual prompt files in: %s\n", outputDir)
+// replyParents maps each reply's ID to the ID of the comment it replies to
+func replyParents(comments []*github.PullRequestComment) map[int64]int64 {
+	parent := make(map[int64]int64, len(comments))
+	for _, comment := range comments {
+		if comment.InReplyTo != nil {
+			parent[comment.GetID()] = comment.GetInReplyTo()
+		}
+	}
+	return parent
+}
 
-	// Process each comment and create individual files
-	for i, item := range feedbackItems {
-		var outputFilePath string
-		var fileContent string
+// threadRoot follows reply links from id back to the comment that started the thread
+func threadRoot(parent map[int64]int64, id int64) int64 {
+	// Bound the walk so a malformed cycle cannot loop forever
+	for range len(parent) {
+		next, ok := parent[id]
+		if !ok {
+			break
+		}
+		id = next
+	}
+	return id
+}
 
-		if item.File != "" {
-			// Create sanitized filename
-			filename := strings.ReplaceAll(strings.ReplaceAll(item.File, "/", "_"), ".", "_")
-			outputFilePath = filepath.Join(outputDir, fmt.Sprintf("%d_%s_line%d.md", i+1, filename, item.Line))
+// latest returns the later of two optional timestamps, or the zero time when both are nil
+func latest(created, updated *time.Time) time.Time {
+	var t time.Time
+	if created != nil {
+		t = *created
+	}
+	if updated != nil && updated.After(t) {
+		t = *updated
+	}
+	return t
+}
 
-			// Fetch the file content for context
-			file, _, _, err := client.Repositories.GetContents(ctx, repoOwner, repoName, item.File, &github.RepositoryContentGetOptions{Ref: pr.GetHead().GetSHA()})
+// contentsGetter is the subset of the GitHub repositories API used to fetch file contents
+type contentsGetter interface {
+	GetContents(ctx context.Context, owner, repo, path string, opt *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error)
+}
+
+// fetchFileContents fetches the content of each unique file at ref using a bounded worker pool.
+// Files missing at ref (404) and contents that fail to decode are reported as warnings on
+// progress and left out of the result. Any other API error stops the remaining fetches and the
+// first one is returned, as is context cancellation.
+func fetchFileContents(ctx context.Context, getter contentsGetter, progress io.Writer, repoOwner, repoName, ref string, files []string, concurrency int) (map[string]string, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultFetchConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		contents = make(map[string]string)
+		seen     = make(map[string]bool)
+		sem      = make(chan struct{}, concurrency)
+	)
+
+	// fail records the first error and cancels the fetches still running
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+loop:
+	for _, name := range files {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break loop
+		}
+
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			file, _, _, err := getter.GetContents(ctx, repoOwner, repoName, name, &github.RepositoryContentGetOptions{Ref: ref})
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "warning: failed to fetch content of %s: %v\n", item.File, err)
-			} else if file != nil {
-				content, err := file.GetContent()
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "warning: failed to decode content of %s: %v\n", item.File, err)
-				} else {
-					fileContent = content
+				switch {
+				case ctx.Err() != nil:
+				case isNotFound(err):
+					mu.Lock()
+					fmt.Fprintf(progress, "warning: %s not found at %s, prompts will have no snippet for it\n", name, ref)
+					mu.Unlock()
+				default:
+					fail(fmt.Errorf("failed to fetch content of %s: %w", name, err))
 				}
+				return
+			}
+			if file == nil {
+				return
 			}
-		} else {
-			outputFilePath = filepath.Join(outputDir, fmt.Sprintf("%d_general_comment.md", i+1))
-		}
-
-		// Determine start line for the snippet
-		startLine := 1
-		if item.Line > 0 {
-			// Try to show context around the comment line
-			startLine = item.Line
-			if startLine > 10 {
-				startLine -= 10 // Show 10 lines before
-			} else {
-				startLine = 1
+
+			content, err := file.GetContent()
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(progress, "warning: failed to decode content of %s: %v\n", name, err)
+				return
 			}
+			contents[name] = content
+		}(name)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	// Cancellation of the parent context, not the cancel above, which only follows firstErr
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return contents, nil
+}
+
+// isNotFound reports whether err is a GitHub API 404 response
+func isNotFound(err error) bool {
+	var respErr *github.ErrorResponse
+	return errors.As(err, &respErr) && respErr.Response != nil && respErr.Response.StatusCode == http.StatusNotFound
+}
+
+// writeFeedbackJSON serializes the report to feedback.json in outputDir and returns its path
+func writeFeedbackJSON(outputDir string, report FeedbackReport) (string, error) {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode feedback: %w", err)
+	}
+
+	jsonPath := filepath.Join(outputDir, FeedbackFileJSON)
+	if err := os.WriteFile(jsonPath, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", jsonPath, err)
+	}
+
+	return jsonPath, nil
+}
+
+// filterFeedback keeps items whose file matches one of the path filters.
+// General comments are kept only when no filters are given and noGeneral is false.
+func filterFeedback(items []FeedbackItem, filters []string, noGeneral bool) []FeedbackItem {
+	if len(filters) == 0 && !noGeneral {
+		return items
+	}
+
+	var filtered []FeedbackItem
+	for _, item := range items {
+		if item.File == "" {
+			continue
+		}
+
+		if len(filters) == 0 {
+			filtered = append(filtered, item)
+			continue
 		}
 
-		// Create a code snippet of ~40 lines
-		var snippet string
-		if fileContent != "" {
-			lines := strings.Split(fileContent, "\n")
-			startIdx := startLine - 1
-			if startIdx < 0 {
-				startIdx = 0
+		for _, pattern := range filters {
+			if matchPath(pattern, item.File) {
+				filtered = append(filtered, item)
+				break
 			}
-			endIdx := startIdx + 40
-			if endIdx > len(lines) {
-				endIdx = len(lines)
+		}
+	}
+
+	return filtered
+}
+
+// filterCommentIDs keeps items whose CommentID is one of ids, or every item when ids is empty
+func filterCommentIDs(items []FeedbackItem, ids []int64) []FeedbackItem {
+	if len(ids) == 0 {
+		return items
+	}
+
+	var filtered []FeedbackItem
+	for _, item := range items {
+		if item.CommentID != 0 && slices.Contains(ids, item.CommentID) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// ValidatePathFilters returns an error for the first pattern path.Match rejects
+func ValidatePathFilters(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid path filter %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// matchPath reports whether name matches the slash-separated glob pattern.
+// Segments are matched with path.Match, and a "**" segment matches zero or more directories.
+func matchPath(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
 			}
-			if startIdx < endIdx {
-				snippet = strings.Join(lines[startIdx:endIdx], "\n")
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+
+	return len(name) == 0
+}
+
+// dedupeFeedback collapses items with identical file and body into a single item,
+// recording every line number the feedback was posted on. Order of first occurrence is preserved.
+func dedupeFeedback(items []FeedbackItem) []FeedbackItem {
+	type key struct{ file, body string }
+
+	var deduped []FeedbackItem
+	seen := make(map[key]int)
+
+	for _, item := range items {
+		k := key{item.File, strings.TrimSpace(item.Body)}
+		if idx, ok := seen[k]; ok {
+			if item.File != "" {
+				deduped[idx].Lines = append(deduped[idx].Lines, item.Line)
 			}
+			deduped[idx].Replies = append(deduped[idx].Replies, item.Replies...)
+			continue
 		}
 
-		// Generate comment URL
-		commentURL := ""
-		if item.CommentID > 0 {
-			commentURL = fmt.Sprintf("https://github.com/%s/%s/pull/%d#discussion_r%d",
-				repoOwner, repoName, prNumber, item.CommentID)
+		if item.File != "" {
+			item.Lines = []int{item.Line}
+		}
+		seen[k] = len(deduped)
+		deduped = append(deduped, item)
+	}
+
+	// Only keep the line list when it carries more than the primary line
+	for i := range deduped {
+		if len(deduped[i].Lines) < 2 {
+			deduped[i].Lines = nil
 		}
+	}
+
+	return deduped
+}
+
+// promptFileName returns the name of the prompt file for the item at index i
+func promptFileName(i int, item FeedbackItem) string {
+	if item.File == "" {
+		return fmt.Sprintf("%d_general_comment.md", i+1)
+	}
+	filename := strings.ReplaceAll(strings.ReplaceAll(item.File, "/", "_"), ".", "_")
+	return fmt.Sprintf("%d_%s_line%d.md", i+1, filename, item.Line)
+}
+
+// commentURL returns the link to item's review comment, or "" for general comments
+func commentURL(repoOwner, repoName string, prNumber int, item FeedbackItem) string {
+	if item.CommentID <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("https://github.com/%s/%s/pull/%d#discussion_r%d",
+		repoOwner, repoName, prNumber, item.CommentID)
+}
+
+// writePromptFiles writes one prompt file per feedback item using the prefetched file contents
+func writePromptFiles(progress io.Writer, outputDir, repoOwner, repoName string, prNumber int, feedbackItems []FeedbackItem, fileContents map[string]string, conventions string, opts FetchOptions) error {
+	for i, item := range feedbackItems {
+		outputFilePath := filepath.Join(outputDir, promptFileName(i, item))
+
+		snippet, startLine := promptSnippet(fileContents[item.File], item.Line, opts)
 
 		// Generate the prompt file with enhanced context
 		promptContent := generatePatchPrompt(
 			repoOwner, repoName, prNumber,
 			item.File, item.Body, snippet,
-			startLine, item.DiffHunk, commentURL,
+			startLine, item.DiffHunk, commentURL(repoOwner, repoName, prNumber, item), item.Lines, item.Replies,
 		)
+		promptContent += conventionsSection(conventions)
+		warnPromptSize(outputFilePath, promptContent)
 		if err := os.WriteFile(outputFilePath, []byte(promptContent), 0o644); err != nil {
 			return fmt.Errorf("failed to create prompt file %s: %w", outputFilePath, err)
 		}
 
-		fmt.Printf("Created: %s\n", outputFilePath)
+		fmt.Fprintf(progress, "Created: %s\n", outputFilePath)
 	}
 
-	// Create an index file
-	indexFilePath := filepath.Join(outputDir, "INDEX.md")
-	indexContent := generateIndexContent(repoOwner, repoName, prNumber, feedbackItems)
-	if err := os.WriteFile(indexFilePath, []byte(indexContent), 0o644); err != nil {
-		return fmt.Errorf("failed to create index file: %w", err)
+	return nil
+}
+
+// warnPromptSize warns when a prompt is estimated to exceed the default model context window.
+// The session model is not known when prompts are written, so the default window is used.
+func warnPromptSize(name, prompt string) {
+	if tokens, limit, fits := llm.CheckPromptSize(prompt, ""); !fits {
+		slog.Warn("prompt may exceed the model's context window; lower --full-file-under or the context lines", "file", name, "estimated_tokens", tokens, "limit", limit)
 	}
+}
 
-	fmt.Printf("\n✓ Created %d prompt files in: %s\n", len(feedbackItems), outputDir)
-	fmt.Printf("✓ Index file created: %s\n", indexFilePath)
+// promptSnippet returns the file content shown in a prompt and its 1-based first line number:
+// the whole file when it has fewer than opts.FullFileUnder lines, otherwise the window around line
+func promptSnippet(content string, line int, opts FetchOptions) (string, int) {
+	if content != "" && opts.FullFileUnder > 0 {
+		whole := strings.TrimSuffix(content, "\n")
+		if strings.Count(whole, "\n")+1 < opts.FullFileUnder {
+			return whole, 1
+		}
+	}
+	return snippetWindow(content, line, opts.ContextBefore, opts.ContextAfter)
+}
 
-	return nil
+// snippetWindow returns the lines of content from before lines above line to after lines below it,
+// clamped to the file bounds, along with the 1-based number of the first returned line.
+// Comments without a line number are treated as pointing at line 1.
+func snippetWindow(content string, line, before, after int) (string, int) {
+	if line < 1 {
+		line = 1
+	}
+	before = max(before, 0)
+	after = max(after, 0)
+
+	startLine := max(line-before, 1)
+	if content == "" {
+		return "", startLine
+	}
+
+	lines := strings.Split(content, "\n")
+	endIdx := min(line+after, len(lines))
+	if startLine-1 >= endIdx {
+		return "", startLine
+	}
+
+	return strings.Join(lines[startLine-1:endIdx], "\n"), startLine
 }
 
-func generatePatchPrompt(repoOwner, repoName string, prNumber int, file, comment, codeSnippet string, startLine int, diffHunk, commentURL string) string {
+func generatePatchPrompt(repoOwner, repoName string, prNumber int, file, comment, codeSnippet string, startLine int, diffHunk, commentURL string, lines []int, replies []ThreadReply) string {
 	repo := fmt.Sprintf("%s/%s", repoOwner, repoName)
 	language := inferLanguage(file)
 
@@ -234,6 +797,10 @@ func generatePatchPrompt(repoOwner, repoName string, prNumber int, file, comment
 - **Target File:** `+"`%s`"+`
 - **Reviewer:** gemini-code-assist[bot]`, repo, prNumber, file)
 
+	if len(lines) > 1 {
+		fmt.Fprintf(&prompt, "\n- **Lines:** %s", formatLines(lines))
+	}
+
 	if commentURL != "" {
 		fmt.Fprintf(&prompt, "\n- **Feedback Link:** %s", commentURL)
 	}
@@ -246,6 +813,22 @@ func generatePatchPrompt(repoOwner, repoName string, prNumber int, file, comment
 %s
 `, comment)
 
+	// Add the discussion that followed the feedback
+	if len(replies) > 0 {
+		prompt.WriteString(`
+## Discussion Thread
+
+> Replies to the feedback above, oldest first. Take them into account when deciding.
+`)
+		for _, reply := range replies {
+			author := reply.Author
+			if author == "" {
+				author = "unknown"
+			}
+			fmt.Fprintf(&prompt, "\n**@%s:**\n\n%s\n", author, reply.Body)
+		}
+	}
+
 	// Add diff context if available
 	if diffHunk != "" {
 		fmt.Fprintf(&prompt, `
@@ -309,31 +892,80 @@ func generatePatchPrompt(repoOwner, repoName string, prNumber int, file, comment
 	return prompt.String()
 }
 
+// languageByName maps special file base names to code fence language identifiers.
+// Checked before languageByExtension so names like CMakeLists.txt are not treated as plain text.
+var languageByName = map[string]string{
+	"Dockerfile":          "dockerfile",
+	"Containerfile":       "dockerfile",
+	"Makefile":            "makefile",
+	"GNUmakefile":         "makefile",
+	"Jenkinsfile":         "jenkinsfile",
+	"Vagrantfile":         "ruby",
+	"Gemfile":             "ruby",
+	"Rakefile":            "ruby",
+	"CMakeLists.txt":      "cmake",
+	"go.mod":              "go",
+	"go.sum":              "go",
+	"docker-compose.yml":  "yaml",
+	"docker-compose.yaml": "yaml",
+	".editorconfig":       "editorconfig",
+	".gitignore":          "gitignore",
+	".dockerignore":       "gitignore",
+	".gitattributes":      "gitattributes",
+	".bashrc":             "bash",
+	".zshrc":              "zsh",
+	".env":                "dotenv",
+}
+
+// languageByExtension maps lowercase file extensions (without the dot) to code fence language
+// identifiers. Extensions not listed here are used as-is.
+var languageByExtension = map[string]string{
+	"py":    "python",
+	"rb":    "ruby",
+	"kt":    "kotlin",
+	"kts":   "kotlin",
+	"rs":    "rust",
+	"js":    "javascript",
+	"mjs":   "javascript",
+	"cjs":   "javascript",
+	"ts":    "typescript",
+	"yml":   "yaml",
+	"md":    "markdown",
+	"h":     "c",
+	"hpp":   "cpp",
+	"cc":    "cpp",
+	"cxx":   "cpp",
+	"cs":    "csharp",
+	"fs":    "fsharp",
+	"ex":    "elixir",
+	"exs":   "elixir",
+	"erl":   "erlang",
+	"hs":    "haskell",
+	"ml":    "ocaml",
+	"pl":    "perl",
+	"ps1":   "powershell",
+	"zsh":   "zsh",
+	"tf":    "hcl",
+	"proto": "protobuf",
+	"cmake": "cmake",
+	"txt":   "text",
+}
+
 // inferLanguage returns the language identifier for syntax highlighting based on filename
 func inferLanguage(file string) string {
 	base := filepath.Base(file)
-	ext := filepath.Ext(file)
-
-	// Handle extensionless files
-	switch base {
-	case "Dockerfile":
-		return "dockerfile"
-	case "Makefile":
-		return "makefile"
-	case "Jenkinsfile":
-		return "jenkinsfile"
-	case "go.mod", "go.sum":
-		return "go"
-	case ".editorconfig":
-		return "editorconfig"
+	if lang, ok := languageByName[base]; ok {
+		return lang
 	}
 
-	// Handle extensions
-	if len(ext) > 1 {
-		return ext[1:] // Remove leading dot
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(base), "."))
+	if ext == "" {
+		return "text"
 	}
-
-	return "text"
+	if lang, ok := languageByExtension[ext]; ok {
+		return lang
+	}
+	return ext
 }
 
 // addLineNumbers adds line numbers to code snippets starting from startLine
@@ -355,7 +987,45 @@ func addLineNumbers(code string, startLine int) string {
 	return numbered.String()
 }
 
-func generateIndexContent(repoOwner, repoName string, prNumber int, feedbackItems []FeedbackItem) string {
+// formatLines renders a list of line numbers as a comma-separated string
+func formatLines(lines []int) string {
+	parts := make([]string, len(lines))
+	for i, line := range lines {
+		parts[i] = fmt.Sprintf("%d", line)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// writeIndexJSON writes index.json listing each prompt file in the same order as INDEX.md
+func writeIndexJSON(outputDir, repoOwner, repoName string, prNumber int, feedbackItems []FeedbackItem) error {
+	entries := make([]IndexEntry, 0, len(feedbackItems))
+	for i, item := range feedbackItems {
+		entries = append(entries, IndexEntry{
+			Index:      i + 1,
+			File:       item.File,
+			Line:       item.Line,
+			Type:       item.Type,
+			PromptFile: promptFileName(i, item),
+			CommentURL: commentURL(repoOwner, repoName, prNumber, item),
+		})
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+
+	indexPath := filepath.Join(outputDir, IndexFileJSON)
+	if err := os.WriteFile(indexPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", indexPath, err)
+	}
+
+	return nil
+}
+
+// generateIndexContent renders INDEX.md. totalItems is the number of items found before any
+// limit was applied; when it exceeds len(feedbackItems) the index notes the truncation.
+func generateIndexContent(repoOwner, repoName string, prNumber int, feedbackItems []FeedbackItem, totalItems int) string {
 	repo := fmt.Sprintf("%s/%s", repoOwner, repoName)
 	content := fmt.Sprintf(`# Gemini Code Assist Feedback - PR #%d
 
@@ -367,14 +1037,19 @@ func generateIndexContent(repoOwner, repoName string, prNumber int, feedbackItem
 
 `, prNumber, repo, len(feedbackItems), time.Now().Format("2006-01-02 15:04:05"))
 
+	if totalItems > len(feedbackItems) {
+		content += fmt.Sprintf("> **Truncated:** showing the first %d of %d feedback items (--limit).\n\n", len(feedbackItems), totalItems)
+	}
+
 	for i, item := range feedbackItems {
-		if item.File != "" {
-			filename := strings.ReplaceAll(strings.ReplaceAll(item.File, "/", "_"), ".", "_")
-			promptFile := fmt.Sprintf("%d_%s_line%d.md", i+1, filename, item.Line)
-			content += fmt.Sprintf("%d. [`%s:%d`](./%s)\n", i+1, item.File, item.Line, promptFile)
-		} else {
-			promptFile := fmt.Sprintf("%d_general_comment.md", i+1)
+		promptFile := promptFileName(i, item)
+		switch {
+		case item.File == "":
 			content += fmt.Sprintf("%d. [General PR Comment](./%s)\n", i+1, promptFile)
+		case len(item.Lines) > 1:
+			content += fmt.Sprintf("%d. [`%s:%s`](./%s)\n", i+1, item.File, formatLines(item.Lines), promptFile)
+		default:
+			content += fmt.Sprintf("%d. [`%s:%d`](./%s)\n", i+1, item.File, item.Line, promptFile)
 		}
 	}
 
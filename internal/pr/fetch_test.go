@@ -1,6 +1,25 @@
 package pr
 
-import "testing"
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/github"
+)
 
 func TestAddLineNumbers(t *testing.T) {
 	input := `func main() {
@@ -52,6 +71,24 @@ func TestInferLanguage(t *testing.T) {
 		{".editorconfig", "editorconfig"},
 		{"script.sh", "sh"},
 		{"unknown", "text"},
+		{"config.yml", "yaml"},
+		{"config.yaml", "yaml"},
+		{"Main.kt", "kotlin"},
+		{"build.gradle.kts", "kotlin"},
+		{"app.rb", "ruby"},
+		{"tool.py", "python"},
+		{"lib.rs", "rust"},
+		{"index.js", "javascript"},
+		{"index.ts", "typescript"},
+		{"README.md", "markdown"},
+		{"Script.PY", "python"},
+		{"CMakeLists.txt", "cmake"},
+		{"notes.txt", "text"},
+		{".gitignore", "gitignore"},
+		{"docker-compose.yml", "yaml"},
+		{"deploy/docker-compose.yaml", "yaml"},
+		{"internal/pr/fetch.go", "go"},
+		{"schema.graphql", "graphql"},
 	}
 
 	for _, tt := range tests {
@@ -63,3 +100,669 @@ func TestInferLanguage(t *testing.T) {
 		})
 	}
 }
+
+func TestDedupeFeedback(t *testing.T) {
+	items := []FeedbackItem{
+		{Type: "review_comment", File: "main.go", Line: 10, Body: "Use a constant here."},
+		{Type: "review_comment", File: "main.go", Line: 42, Body: "Use a constant here."},
+		{Type: "review_comment", File: "util.go", Line: 10, Body: "Use a constant here."},
+		{Type: "review_comment", File: "main.go", Line: 77, Body: "Use a constant here.\n"},
+		{Type: "review_comment", File: "main.go", Line: 5, Body: "Missing error check."},
+		{Type: "issue_comment", Body: "General note."},
+		{Type: "issue_comment", Body: "General note."},
+	}
+
+	got := dedupeFeedback(items)
+	if len(got) != 4 {
+		t.Fatalf("dedupeFeedback() returned %d items, want 4", len(got))
+	}
+
+	if got[0].Line != 10 {
+		t.Errorf("first item line = %d, want 10", got[0].Line)
+	}
+	wantLines := []int{10, 42, 77}
+	if len(got[0].Lines) != len(wantLines) {
+		t.Fatalf("first item lines = %v, want %v", got[0].Lines, wantLines)
+	}
+	for i, line := range wantLines {
+		if got[0].Lines[i] != line {
+			t.Errorf("first item lines = %v, want %v", got[0].Lines, wantLines)
+			break
+		}
+	}
+
+	if got[1].Lines != nil {
+		t.Errorf("unique item should have no line list, got %v", got[1].Lines)
+	}
+
+	tmpDir := t.TempDir()
+	if err := writePromptFiles(io.Discard, tmpDir, "owner", "repo", 1, got, map[string]string{}, "", FetchOptions{ContextBefore: DefaultContextBefore, ContextAfter: DefaultContextAfter}); err != nil {
+		t.Fatalf("writePromptFiles() error = %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(tmpDir, "*.md"))
+	if err != nil {
+		t.Fatalf("failed to list prompt files: %v", err)
+	}
+	if len(files) != 4 {
+		t.Errorf("wrote %d prompt files, want 4", len(files))
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, promptFileName(0, got[0])))
+	if err != nil {
+		t.Fatalf("failed to read prompt file: %v", err)
+	}
+	if !strings.Contains(string(content), "- **Lines:** 10, 42, 77") {
+		t.Error("expected prompt to list every duplicated line")
+	}
+
+	index := generateIndexContent("owner", "repo", 1, got, len(got))
+	if !strings.Contains(index, "`main.go:10, 42, 77`") {
+		t.Error("expected index to list every duplicated line")
+	}
+}
+
+func TestWriteFeedbackJSONRoundTrip(t *testing.T) {
+	report := FeedbackReport{
+		Repo:     "owner/repo",
+		PRNumber: 7,
+		Title:    "Add feature",
+		Items: []FeedbackItem{
+			{Type: "review_comment", File: "main.go", Line: 12, Body: "Check this error.", DiffHunk: "@@ -1 +1 @@", CommentID: 99},
+			{Type: "review_comment", File: "util.go", Line: 3, Body: "Duplicate.", Lines: []int{3, 9}},
+			{Type: "issue_comment", Body: "General note."},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	path, err := writeFeedbackJSON(tmpDir, report)
+	if err != nil {
+		t.Fatalf("writeFeedbackJSON() error = %v", err)
+	}
+	if filepath.Base(path) != FeedbackFileJSON {
+		t.Errorf("wrote %q, want %q", filepath.Base(path), FeedbackFileJSON)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+
+	var got FeedbackReport
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("failed to decode feedback JSON: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, report) {
+		t.Errorf("round-tripped report = %+v, want %+v", got, report)
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"internal/**", "internal/pr/fetch.go", true},
+		{"internal/**", "internal/main.go", true},
+		{"internal/**", "cmd/pr.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "internal/pr/fetch.go", true},
+		{"**/*.go", "README.md", false},
+		{"cmd/*.go", "cmd/pr.go", true},
+		{"cmd/*.go", "cmd/sub/pr.go", false},
+		{"internal/**/fetch.go", "internal/pr/fetch.go", true},
+		{"internal/**/fetch.go", "internal/fetch.go", true},
+		{"docs/*.md", "docs/guide.md", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			if got := matchPath(tt.pattern, tt.name); got != tt.want {
+				t.Errorf("matchPath(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePathFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		wantErr  bool
+	}{
+		{name: "none", patterns: nil},
+		{name: "valid globs", patterns: []string{"internal/**", "cmd/*.go", "docs/[a-z]*.md"}},
+		{name: "unclosed class", patterns: []string{"cmd/*.go", "internal/[pr"}, wantErr: true},
+		{name: "trailing escape", patterns: []string{`main\`}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePathFilters(tt.patterns)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePathFilters(%q) error = %v, wantErr %v", tt.patterns, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFetchReviews_InvalidPathFilter(t *testing.T) {
+	client := newTestGitHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s before path filters were validated", r.URL.Path)
+	}))
+
+	err := FetchReviews(context.Background(), client, "o", "r", 1, t.TempDir(), FetchOptions{PathFilters: []string{"internal/[pr"}})
+	if err == nil || !strings.Contains(err.Error(), `invalid path filter "internal/[pr"`) {
+		t.Errorf("FetchReviews() error = %v, want invalid path filter", err)
+	}
+}
+
+func TestFilterFeedback(t *testing.T) {
+	items := []FeedbackItem{
+		{File: "internal/pr/fetch.go", Line: 1, Body: "a"},
+		{File: "internal/llm/retry.go", Line: 2, Body: "b"},
+		{File: "cmd/pr.go", Line: 3, Body: "c"},
+		{File: "README.md", Line: 4, Body: "d"},
+		{Type: "issue_comment", Body: "general"},
+	}
+
+	tests := []struct {
+		name      string
+		filters   []string
+		noGeneral bool
+		want      []string
+	}{
+		{"no filters keeps everything", nil, false, []string{"internal/pr/fetch.go", "internal/llm/retry.go", "cmd/pr.go", "README.md", ""}},
+		{"no-general drops general comments", nil, true, []string{"internal/pr/fetch.go", "internal/llm/retry.go", "cmd/pr.go", "README.md"}},
+		{"recursive directory filter", []string{"internal/**"}, false, []string{"internal/pr/fetch.go", "internal/llm/retry.go"}},
+		{"multiple filters", []string{"internal/pr/*", "*.md"}, false, []string{"internal/pr/fetch.go", "README.md"}},
+		{"no matches", []string{"docs/**"}, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterFeedback(items, tt.filters, tt.noGeneral)
+			var files []string
+			for _, item := range got {
+				files = append(files, item.File)
+			}
+			if !reflect.DeepEqual(files, tt.want) {
+				t.Errorf("filterFeedback() files = %q, want %q", files, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterCommentIDs(t *testing.T) {
+	items := []FeedbackItem{
+		{File: "a.go", CommentID: 101},
+		{File: "b.go", CommentID: 102},
+		{File: "c.go", CommentID: 103},
+		{Type: "issue_comment", Body: "general"},
+	}
+
+	tests := []struct {
+		name string
+		ids  []int64
+		want []int64
+	}{
+		{"no ids keeps everything", nil, []int64{101, 102, 103, 0}},
+		{"keeps requested ids in fetch order", []int64{103, 101}, []int64{101, 103}},
+		{"unknown ids are ignored", []int64{102, 999}, []int64{102}},
+		{"no matches", []int64{999}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int64
+			for _, item := range filterCommentIDs(items, tt.ids) {
+				got = append(got, item.CommentID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterCommentIDs() ids = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// countingGetter is a contentsGetter that records the peak number of concurrent requests
+type countingGetter struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	calls    int
+}
+
+func (g *countingGetter) GetContents(ctx context.Context, owner, repo, path string, opt *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+	g.mu.Lock()
+	g.calls++
+	g.inFlight++
+	if g.inFlight > g.peak {
+		g.peak = g.inFlight
+	}
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		g.inFlight--
+		g.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(20 * time.Millisecond):
+	case <-ctx.Done():
+		return nil, nil, nil, ctx.Err()
+	}
+
+	if strings.HasPrefix(path, "missing") {
+		return nil, nil, nil, &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}, Message: "Not Found"}
+	}
+	if strings.HasPrefix(path, "broken") {
+		return nil, nil, nil, errors.New("server error")
+	}
+
+	content := "content of " + path
+	return &github.RepositoryContent{Content: &content}, nil, nil, nil
+}
+
+func TestFetchFileContents(t *testing.T) {
+	getter := &countingGetter{}
+	files := []string{"a.go", "b.go", "c.go", "a.go", "d.go", "e.go", "missing.go", "f.go"}
+
+	var progress bytes.Buffer
+	contents, err := fetchFileContents(context.Background(), getter, &progress, "owner", "repo", "sha", files, 2)
+	if err != nil {
+		t.Fatalf("fetchFileContents() error = %v", err)
+	}
+
+	if getter.peak > 2 {
+		t.Errorf("peak concurrent requests = %d, want at most 2", getter.peak)
+	}
+	if getter.calls != 7 {
+		t.Errorf("got %d requests, want 7 (duplicates fetched once)", getter.calls)
+	}
+	if len(contents) != 6 {
+		t.Errorf("got %d contents, want 6", len(contents))
+	}
+	if contents["c.go"] != "content of c.go" {
+		t.Errorf("contents[c.go] = %q, want %q", contents["c.go"], "content of c.go")
+	}
+	if _, ok := contents["missing.go"]; ok {
+		t.Error("expected failed fetch to be left out of contents")
+	}
+	if !strings.Contains(progress.String(), "warning: missing.go not found at sha") {
+		t.Errorf("progress = %q, want a warning for missing.go", progress.String())
+	}
+}
+
+func TestFetchFileContentsAPIError(t *testing.T) {
+	var progress bytes.Buffer
+	_, err := fetchFileContents(context.Background(), &countingGetter{}, &progress, "owner", "repo", "sha", []string{"a.go", "broken.go", "missing.go"}, 1)
+	if err == nil || !strings.Contains(err.Error(), "failed to fetch content of broken.go: server error") {
+		t.Errorf("fetchFileContents() error = %v, want the broken.go API error", err)
+	}
+}
+
+func TestFetchFileContentsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := fetchFileContents(ctx, &countingGetter{}, io.Discard, "owner", "repo", "sha", []string{"a.go", "b.go"}, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestSnippetWindow(t *testing.T) {
+	var b strings.Builder
+	for i := 1; i <= 100; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	long := strings.TrimSuffix(b.String(), "\n")
+	short := "one\ntwo\nthree"
+
+	tests := []struct {
+		name          string
+		content       string
+		line          int
+		before, after int
+		wantStart     int
+		wantFirst     string
+		wantLast      string
+		wantCount     int
+	}{
+		{"middle of file", long, 50, 10, 30, 40, "line 40", "line 80", 41},
+		{"near line 1", long, 3, 10, 30, 1, "line 1", "line 33", 33},
+		{"no line number", long, 0, 10, 30, 1, "line 1", "line 31", 31},
+		{"near end of file", long, 95, 10, 30, 85, "line 85", "line 100", 16},
+		{"file shorter than window", short, 2, 10, 30, 1, "one", "three", 3},
+		{"zero context", long, 50, 0, 0, 50, "line 50", "line 50", 1},
+		{"negative context clamps", long, 50, -5, -5, 50, "line 50", "line 50", 1},
+		{"line past end of file", short, 10, 2, 2, 8, "", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, start := snippetWindow(tt.content, tt.line, tt.before, tt.after)
+			if start != tt.wantStart {
+				t.Errorf("start = %d, want %d", start, tt.wantStart)
+			}
+			if tt.wantCount == 0 {
+				if got != "" {
+					t.Errorf("expected empty snippet, got %q", got)
+				}
+				return
+			}
+			lines := strings.Split(got, "\n")
+			if len(lines) != tt.wantCount {
+				t.Errorf("got %d lines, want %d", len(lines), tt.wantCount)
+			}
+			if lines[0] != tt.wantFirst || lines[len(lines)-1] != tt.wantLast {
+				t.Errorf("window = %q..%q, want %q..%q", lines[0], lines[len(lines)-1], tt.wantFirst, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestPromptSnippet(t *testing.T) {
+	numbered := func(n int) string {
+		var b strings.Builder
+		for i := 1; i <= n; i++ {
+			fmt.Fprintf(&b, "line %d\n", i)
+		}
+		return b.String()
+	}
+	opts := FetchOptions{ContextBefore: 10, ContextAfter: 30, FullFileUnder: 150}
+
+	tests := []struct {
+		name      string
+		content   string
+		opts      FetchOptions
+		wantStart int
+		wantCount int
+	}{
+		{"short file is embedded whole", numbered(120), opts, 1, 120},
+		{"long file is windowed", numbered(300), opts, 90, 41},
+		{"file at threshold is windowed", numbered(150), opts, 90, 41},
+		{"zero threshold always windows", numbered(120), FetchOptions{ContextBefore: 10, ContextAfter: 30}, 90, 32},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, start := promptSnippet(tt.content, 100, tt.opts)
+			if start != tt.wantStart {
+				t.Errorf("start = %d, want %d", start, tt.wantStart)
+			}
+			if n := len(strings.Split(got, "\n")); n != tt.wantCount {
+				t.Errorf("got %d lines, want %d", n, tt.wantCount)
+			}
+		})
+	}
+}
+
+// newTestGitHubClient returns a client whose requests are served by handler
+func newTestGitHubClient(t *testing.T, handler http.Handler) *github.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(server.Client())
+	baseURL, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL = baseURL
+	return client
+}
+
+// fakePRHandler serves a PR with one gemini-code-assist review comment on main.go
+func fakePRHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/o/r/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"number": 1, "title": "Add feature", "head": {"sha": "abc123"}}`)
+	})
+	mux.HandleFunc("/repos/o/r/pulls/1/files", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"filename": "main.go", "patch": "@@ -1 +1 @@"}]`)
+	})
+	mux.HandleFunc("/repos/o/r/pulls/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": 7, "path": "main.go", "position": 2, "body": "Check the error.", "user": {"login": "gemini-code-assist[bot]"}}]`)
+	})
+	mux.HandleFunc("/repos/o/r/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("/repos/o/r/contents/main.go", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"type": "file", "encoding": "base64", "content": "cGFja2FnZSBtYWluCg=="}`)
+	})
+	return mux
+}
+
+func TestFetchReviews_Progress(t *testing.T) {
+	client := newTestGitHubClient(t, fakePRHandler())
+
+	t.Run("progress goes to the progress writer", func(t *testing.T) {
+		var progress bytes.Buffer
+		outputDir := t.TempDir()
+
+		if err := FetchReviews(context.Background(), client, "o", "r", 1, outputDir, FetchOptions{Progress: &progress}); err != nil {
+			t.Fatalf("FetchReviews() error = %v", err)
+		}
+
+		for _, want := range []string{"Successfully fetched PR #1: Add feature", "Found 1 feedback items", "Created: "} {
+			if !strings.Contains(progress.String(), want) {
+				t.Errorf("progress missing %q:\n%s", want, progress.String())
+			}
+		}
+		if _, err := os.Stat(filepath.Join(outputDir, "INDEX.md")); err != nil {
+			t.Errorf("expected INDEX.md to be written: %v", err)
+		}
+		meta, ok, err := LoadMetadata(outputDir)
+		if err != nil || !ok {
+			t.Fatalf("LoadMetadata() = %v, %v", ok, err)
+		}
+		if meta.HeadSHA != "abc123" || meta.Repo != "o/r" || meta.PRNumber != 1 {
+			t.Errorf("metadata = %+v, want head abc123 for o/r#1", meta)
+		}
+	})
+
+	t.Run("nil progress writes nothing", func(t *testing.T) {
+		outputDir := t.TempDir()
+		if err := FetchReviews(context.Background(), client, "o", "r", 1, outputDir, FetchOptions{}); err != nil {
+			t.Fatalf("FetchReviews() error = %v", err)
+		}
+		files, _ := filepath.Glob(filepath.Join(outputDir, "*.md"))
+		if len(files) != 2 {
+			t.Errorf("expected a prompt file and INDEX.md, got %v", files)
+		}
+	})
+}
+
+func TestFetchReviews_GroupsThreads(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/", fakePRHandler())
+	mux.HandleFunc("/repos/o/r/pulls/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"id": 7, "path": "main.go", "position": 2, "body": "Check the error.", "user": {"login": "gemini-code-assist[bot]"}},
+			{"id": 8, "in_reply_to_id": 7, "path": "main.go", "position": 2, "body": "The error is handled by the caller.", "user": {"login": "octocat"}},
+			{"id": 9, "in_reply_to_id": 8, "path": "main.go", "position": 2, "body": "Then a comment explaining that would help.", "user": {"login": "gemini-code-assist[bot]"}}
+		]`)
+	})
+	client := newTestGitHubClient(t, mux)
+	outputDir := t.TempDir()
+
+	if err := FetchReviews(context.Background(), client, "o", "r", 1, outputDir, FetchOptions{}); err != nil {
+		t.Fatalf("FetchReviews() error = %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(outputDir, "*.md"))
+	var prompts []string
+	for _, file := range files {
+		if filepath.Base(file) != "INDEX.md" {
+			prompts = append(prompts, file)
+		}
+	}
+	if len(prompts) != 1 {
+		t.Fatalf("expected a single prompt file for the thread, got %v", prompts)
+	}
+
+	content, err := os.ReadFile(prompts[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Check the error.",
+		"## Discussion Thread",
+		"**@octocat:**\n\nThe error is handled by the caller.",
+		"**@gemini-code-assist[bot]:**\n\nThen a comment explaining that would help.",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("prompt missing %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestGroupReplies(t *testing.T) {
+	id := func(n int64) *int64 { return &n }
+	comments := []*github.PullRequestComment{
+		{ID: id(1), Body: github.String("root a")},
+		{ID: id(2), Body: github.String("root b")},
+		{ID: id(3), InReplyTo: id(1), Body: github.String("a1"), User: &github.User{Login: github.String("alice")}},
+		{ID: id(4), InReplyTo: id(3), Body: github.String("a2")},
+		{ID: id(5), InReplyTo: id(2), Body: github.String("b1")},
+	}
+
+	replies := groupReplies(comments)
+
+	if got := replies[1]; len(got) != 2 || got[0].Body != "a1" || got[0].Author != "alice" || got[1].Body != "a2" {
+		t.Errorf("thread 1 replies = %+v", got)
+	}
+	if got := replies[2]; len(got) != 1 || got[0].Body != "b1" {
+		t.Errorf("thread 2 replies = %+v", got)
+	}
+	if _, ok := replies[3]; ok {
+		t.Error("replies should be grouped under the thread root, not intermediate replies")
+	}
+}
+
+func TestFetchReviews_IndexJSON(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/", fakePRHandler())
+	mux.HandleFunc("/repos/o/r/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": 11, "body": "Consider adding tests.", "user": {"login": "gemini-code-assist[bot]"}}]`)
+	})
+	client := newTestGitHubClient(t, mux)
+	outputDir := t.TempDir()
+
+	if err := FetchReviews(context.Background(), client, "o", "r", 1, outputDir, FetchOptions{}); err != nil {
+		t.Fatalf("FetchReviews() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, IndexFileJSON))
+	if err != nil {
+		t.Fatalf("expected %s to be written: %v", IndexFileJSON, err)
+	}
+	var entries []IndexEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("invalid %s: %v", IndexFileJSON, err)
+	}
+
+	prompts, _ := filepath.Glob(filepath.Join(outputDir, "*.md"))
+	if len(entries) != len(prompts)-1 {
+		t.Fatalf("got %d index entries for prompt files %v", len(entries), prompts)
+	}
+
+	for i, entry := range entries {
+		if entry.Index != i+1 {
+			t.Errorf("entries[%d].Index = %d, want %d", i, entry.Index, i+1)
+		}
+		if _, err := os.Stat(filepath.Join(outputDir, entry.PromptFile)); err != nil {
+			t.Errorf("entries[%d].PromptFile %q not written: %v", i, entry.PromptFile, err)
+		}
+		switch entry.File {
+		case "main.go":
+			if entry.Type != "review_comment" || entry.CommentURL != "https://github.com/o/r/pull/1#discussion_r7" {
+				t.Errorf("review entry = %+v", entry)
+			}
+		case "":
+			if entry.CommentURL != "" {
+				t.Errorf("general entry CommentURL = %q, want empty", entry.CommentURL)
+			}
+		default:
+			t.Errorf("unexpected entry %+v", entry)
+		}
+	}
+}
+
+func TestFetchReviews_NoIndex(t *testing.T) {
+	tests := []struct {
+		name      string
+		noIndex   bool
+		wantIndex bool
+	}{
+		{"index written by default", false, true},
+		{"no-index skips INDEX.md", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestGitHubClient(t, fakePRHandler())
+			outputDir := t.TempDir()
+
+			if err := FetchReviews(context.Background(), client, "o", "r", 1, outputDir, FetchOptions{NoIndex: tt.noIndex}); err != nil {
+				t.Fatalf("FetchReviews() error = %v", err)
+			}
+
+			_, err := os.Stat(filepath.Join(outputDir, IndexFile))
+			if gotIndex := err == nil; gotIndex != tt.wantIndex {
+				t.Errorf("%s written = %v, want %v", IndexFile, gotIndex, tt.wantIndex)
+			}
+			if _, err := os.Stat(filepath.Join(outputDir, "1_main_go_line2.md")); err != nil {
+				t.Errorf("prompt file not written: %v", err)
+			}
+		})
+	}
+}
+
+func TestFetchReviews_Limit(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/", fakePRHandler())
+	mux.HandleFunc("/repos/o/r/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": 11, "body": "Consider adding tests.", "user": {"login": "gemini-code-assist[bot]"}}]`)
+	})
+	client := newTestGitHubClient(t, mux)
+
+	tests := []struct {
+		name          string
+		limit         int
+		wantPrompts   int
+		wantTruncated bool
+	}{
+		{name: "limit truncates", limit: 1, wantPrompts: 1, wantTruncated: true},
+		{name: "limit at count", limit: 2, wantPrompts: 2},
+		{name: "no limit", limit: 0, wantPrompts: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputDir := t.TempDir()
+			if err := FetchReviews(context.Background(), client, "o", "r", 1, outputDir, FetchOptions{Limit: tt.limit}); err != nil {
+				t.Fatalf("FetchReviews() error = %v", err)
+			}
+
+			files, _ := filepath.Glob(filepath.Join(outputDir, "*.md"))
+			if got := len(files) - 1; got != tt.wantPrompts {
+				t.Errorf("got %d prompt files, want %d: %v", got, tt.wantPrompts, files)
+			}
+
+			index, err := os.ReadFile(filepath.Join(outputDir, "INDEX.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			truncated := strings.Contains(string(index), "showing the first 1 of 2 feedback items")
+			if truncated != tt.wantTruncated {
+				t.Errorf("INDEX.md truncation notice = %v, want %v:\n%s", truncated, tt.wantTruncated, index)
+			}
+		})
+	}
+}
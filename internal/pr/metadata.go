@@ -0,0 +1,102 @@
+package pr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/go-github/github"
+)
+
+// MetadataFile is the name of the file in a review directory that records which PR revision was fetched
+const MetadataFile = "metadata.json"
+
+// ReviewMetadata describes the PR revision a review directory was fetched from
+type ReviewMetadata struct {
+	Repo      string    `json:"repo"`
+	PRNumber  int       `json:"pr_number"`
+	HeadSHA   string    `json:"head_sha"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// writeMetadata records meta in dir
+func writeMetadata(dir string, meta ReviewMetadata) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode review metadata: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, MetadataFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write review metadata: %w", err)
+	}
+	return nil
+}
+
+// LoadMetadata reads the metadata recorded in dir. ok is false when dir has none,
+// e.g. because it was fetched by an older version of smix.
+func LoadMetadata(dir string) (meta ReviewMetadata, ok bool, err error) {
+	path := filepath.Join(dir, MetadataFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ReviewMetadata{}, false, nil
+		}
+		return ReviewMetadata{}, false, fmt.Errorf("failed to read review metadata: %w", err)
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return ReviewMetadata{}, false, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return meta, true, nil
+}
+
+// Freshness compares the head SHA recorded in a review directory with the live PR head
+type Freshness struct {
+	// RecordedSHA is empty when the directory has no metadata
+	RecordedSHA string
+	HeadSHA     string
+}
+
+// Stale reports whether the PR head moved since the directory was fetched, or is unknown
+func (f Freshness) Stale() bool {
+	return f.RecordedSHA != f.HeadSHA
+}
+
+// pullRequestGetter is the subset of the GitHub pull requests service used by CheckFreshness
+type pullRequestGetter interface {
+	Get(ctx context.Context, owner, repo string, number int) (*github.PullRequest, *github.Response, error)
+}
+
+// CheckFreshness compares the head SHA recorded in dir against the live head of the pull request
+func CheckFreshness(ctx context.Context, prs pullRequestGetter, dir, repoOwner, repoName string, prNumber int, maxWait time.Duration) (Freshness, error) {
+	meta, _, err := LoadMetadata(dir)
+	if err != nil {
+		return Freshness{}, err
+	}
+
+	pr, err := doWithRateLimit(ctx, maxWait, func() (*github.PullRequest, *github.Response, error) {
+		return prs.Get(ctx, repoOwner, repoName, prNumber)
+	})
+	if err != nil {
+		return Freshness{}, fmt.Errorf("failed to get PR #%d in %s/%s: %w", prNumber, repoOwner, repoName, err)
+	}
+
+	return Freshness{RecordedSHA: meta.HeadSHA, HeadSHA: pr.GetHead().GetSHA()}, nil
+}
+
+// ClearPromptFiles removes the prompt files and INDEX.md left in dir by a previous fetch,
+// so a refetch does not mix old feedback with new
+func ClearPromptFiles(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return fmt.Errorf("failed to list prompt files: %w", err)
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", file, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package pr
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/github"
+)
+
+// stubPRGetter returns a PR with the given head SHA, or err
+type stubPRGetter struct {
+	headSHA string
+	err     error
+}
+
+func (s stubPRGetter) Get(ctx context.Context, owner, repo string, number int) (*github.PullRequest, *github.Response, error) {
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+	return &github.PullRequest{Head: &github.PullRequestBranch{SHA: github.String(s.headSHA)}}, nil, nil
+}
+
+func TestCheckFreshness(t *testing.T) {
+	tests := []struct {
+		name      string
+		recorded  string // empty writes no metadata
+		live      string
+		getErr    error
+		wantStale bool
+		wantErr   bool
+	}{
+		{name: "head unchanged", recorded: "abc123", live: "abc123"},
+		{name: "head moved", recorded: "abc123", live: "def456", wantStale: true},
+		{name: "no metadata", live: "def456", wantStale: true},
+		{name: "PR lookup fails", recorded: "abc123", getErr: errors.New("not found"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.recorded != "" {
+				if err := writeMetadata(dir, ReviewMetadata{Repo: "o/r", PRNumber: 1, HeadSHA: tt.recorded}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			freshness, err := CheckFreshness(context.Background(), stubPRGetter{headSHA: tt.live, err: tt.getErr}, dir, "o", "r", 1, 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckFreshness() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if freshness.Stale() != tt.wantStale {
+				t.Errorf("Stale() = %v, want %v (%+v)", freshness.Stale(), tt.wantStale, freshness)
+			}
+			if freshness.RecordedSHA != tt.recorded || freshness.HeadSHA != tt.live {
+				t.Errorf("freshness = %+v, want recorded %q and head %q", freshness, tt.recorded, tt.live)
+			}
+		})
+	}
+}
+
+func TestLoadMetadata_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, MetadataFile), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := LoadMetadata(dir); err == nil {
+		t.Error("expected an error for invalid metadata")
+	}
+}
+
+func TestClearPromptFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"1_main_go_line2.md", "INDEX.md", DecisionsFile, MetadataFile} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := ClearPromptFiles(dir); err != nil {
+		t.Fatalf("ClearPromptFiles() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if len(names) != 2 || names[0] != DecisionsFile || names[1] != MetadataFile {
+		t.Errorf("remaining files = %v, want only %s and %s", names, DecisionsFile, MetadataFile)
+	}
+}
@@ -0,0 +1,49 @@
+package pr
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// OpenPath returns the INDEX.md in a review directory, or the directory itself when it has no index
+func OpenPath(dir string) (string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("directory '%s' does not exist", dir)
+		}
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("'%s' is not a directory", dir)
+	}
+
+	index := filepath.Join(dir, IndexFile)
+	if _, err := os.Stat(index); err == nil {
+		return index, nil
+	}
+	return dir, nil
+}
+
+// OpenInEditor runs editor with path as its last argument. editor is a command line such as
+// "code --wait", as found in $EDITOR.
+func OpenInEditor(ctx context.Context, editor, path string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		return fmt.Errorf("no editor configured")
+	}
+
+	cmd := exec.CommandContext(ctx, fields[0], append(fields[1:], path)...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to open %s with %s: %w", path, fields[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package pr
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpenPath(t *testing.T) {
+	withIndex := t.TempDir()
+	if err := os.WriteFile(filepath.Join(withIndex, IndexFile), []byte("# Index\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	withoutIndex := t.TempDir()
+	file := filepath.Join(withoutIndex, "notes.txt")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		dir     string
+		want    string
+		wantErr string
+	}{
+		{name: "index present", dir: withIndex, want: filepath.Join(withIndex, IndexFile)},
+		{name: "no index", dir: withoutIndex, want: withoutIndex},
+		{name: "missing directory", dir: filepath.Join(withoutIndex, "missing"), wantErr: "does not exist"},
+		{name: "not a directory", dir: file, wantErr: "is not a directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := OpenPath(tt.dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("OpenPath() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OpenPath() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("OpenPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
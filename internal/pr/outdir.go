@@ -0,0 +1,85 @@
+package pr
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/connorhough/smix/internal/paths"
+)
+
+// DefaultOutputBase returns the directory fetched feedback is kept under when no base is
+// configured: reviews in the smix cache directory ($XDG_CACHE_HOME/smix)
+func DefaultOutputBase() (string, error) {
+	cacheDir, err := paths.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "reviews"), nil
+}
+
+// OutputBase returns the configured output base with a leading ~/ expanded, or
+// DefaultOutputBase when configured is empty
+func OutputBase(configured string) (string, error) {
+	if configured == "" {
+		return DefaultOutputBase()
+	}
+	home, _ := os.UserHomeDir()
+	return expandHome(configured, home), nil
+}
+
+// ReviewDir returns the directory feedback for a PR is written to. pattern may contain
+// {repo} (owner/name with slashes replaced by underscores), {pr}, and {date} (YYYY-MM-DD).
+// An empty pattern uses DefaultReviewDir. Relative results are placed under base; an empty
+// base keeps them relative to the current directory.
+func ReviewDir(base, pattern, repoOwner, repoName string, prNumber int, now time.Time) string {
+	dir := DefaultReviewDir(prNumber)
+	if pattern != "" {
+		repo := sanitizePathSegment(repoOwner + "/" + repoName)
+		dir = strings.NewReplacer(
+			"{repo}", repo,
+			"{pr}", strconv.Itoa(prNumber),
+			"{date}", now.Format("2006-01-02"),
+		).Replace(pattern)
+	}
+
+	if base == "" || filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(base, dir)
+}
+
+// FindReviewDir returns the directory an earlier fetch wrote feedback for a PR to. When pattern
+// contains {date}, the existing directory with the latest date is chosen, so feedback fetched
+// on an earlier day is still found. Without an existing match it returns ReviewDir for now.
+func FindReviewDir(base, pattern, repoOwner, repoName string, prNumber int, now time.Time) string {
+	if !strings.Contains(pattern, "{date}") {
+		return ReviewDir(base, pattern, repoOwner, repoName, prNumber, now)
+	}
+
+	// Resolve everything but the date, escape it for globbing, then match any YYYY-MM-DD
+	const dateMarker = "\x00"
+	dir := ReviewDir(base, strings.ReplaceAll(pattern, "{date}", dateMarker), repoOwner, repoName, prNumber, now)
+	glob := strings.ReplaceAll(escapeGlob(dir), dateMarker, "[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]")
+
+	// Glob sorts its matches, and the dates sort chronologically, so the last directory is the latest
+	matches, _ := filepath.Glob(glob)
+	for i := len(matches) - 1; i >= 0; i-- {
+		if info, err := os.Stat(matches[i]); err == nil && info.IsDir() {
+			return matches[i]
+		}
+	}
+	return ReviewDir(base, pattern, repoOwner, repoName, prNumber, now)
+}
+
+// escapeGlob escapes the characters filepath.Match treats specially
+func escapeGlob(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`).Replace(s)
+}
+
+// sanitizePathSegment replaces path separators so a value can be used as a single directory name
+func sanitizePathSegment(s string) string {
+	return strings.NewReplacer("/", "_", `\`, "_").Replace(s)
+}
@@ -0,0 +1,114 @@
+package pr
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReviewDir(t *testing.T) {
+	now := time.Date(2026, 4, 5, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		base    string
+		pattern string
+		want    string
+	}{
+		{name: "default", pattern: "", want: "./pr_review_pr42"},
+		{name: "all placeholders", pattern: "reviews/{repo}/pr{pr}-{date}", want: "reviews/octocat_Hello-World/pr42-2026-04-05"},
+		{name: "repeated placeholder", pattern: "{pr}/{pr}", want: "42/42"},
+		{name: "no placeholders", pattern: "feedback", want: "feedback"},
+		{name: "unknown placeholder kept", pattern: "{branch}-{pr}", want: "{branch}-42"},
+		{name: "default under base", base: "/cache/smix/reviews", pattern: "", want: "/cache/smix/reviews/pr_review_pr42"},
+		{name: "pattern under base", base: "/cache/smix/reviews", pattern: "{repo}/pr{pr}", want: "/cache/smix/reviews/octocat_Hello-World/pr42"},
+		{name: "absolute pattern ignores base", base: "/cache/smix/reviews", pattern: "/tmp/pr{pr}", want: "/tmp/pr42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReviewDir(tt.base, tt.pattern, "octocat", "Hello-World", 42, now); got != tt.want {
+				t.Errorf("ReviewDir(%q, %q) = %q, want %q", tt.base, tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindReviewDir(t *testing.T) {
+	now := time.Date(2026, 4, 5, 10, 0, 0, 0, time.UTC)
+	base := t.TempDir()
+	for _, dir := range []string{"o_r/pr42-2026-03-30", "o_r/pr42-2026-04-02", "o_r/pr7-2026-04-04", "o_r/pr42-latest"} {
+		if err := os.MkdirAll(filepath.Join(base, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(base, "o_r", "pr42-2026-04-03"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		pr      int
+		want    string
+	}{
+		{name: "latest dated directory", pattern: "{repo}/pr{pr}-{date}", pr: 42, want: filepath.Join(base, "o_r", "pr42-2026-04-02")},
+		{name: "other PR", pattern: "{repo}/pr{pr}-{date}", pr: 7, want: filepath.Join(base, "o_r", "pr7-2026-04-04")},
+		{name: "no match uses today", pattern: "{repo}/pr{pr}-{date}", pr: 9, want: filepath.Join(base, "o_r", "pr9-2026-04-05")},
+		{name: "no date placeholder", pattern: "{repo}/pr{pr}-latest", pr: 42, want: filepath.Join(base, "o_r", "pr42-latest")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindReviewDir(base, tt.pattern, "o", "r", tt.pr, now); got != tt.want {
+				t.Errorf("FindReviewDir(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchReviews_CreatesReviewDir(t *testing.T) {
+	client := newTestGitHubClient(t, fakePRHandler())
+	base := t.TempDir()
+	outputDir := ReviewDir("", filepath.Join(base, "{repo}", "pr{pr}"), "o", "r", 1, time.Now())
+
+	if err := FetchReviews(context.Background(), client, "o", "r", 1, outputDir, FetchOptions{}); err != nil {
+		t.Fatalf("FetchReviews() error = %v", err)
+	}
+
+	want := filepath.Join(base, "o_r", "pr1")
+	if _, err := os.Stat(filepath.Join(want, "INDEX.md")); err != nil {
+		t.Errorf("expected feedback in %s: %v", want, err)
+	}
+}
+
+func TestOutputBase(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/xdg/cache")
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+
+	tests := []struct {
+		configured string
+		want       string
+	}{
+		{configured: "", want: filepath.Join("/xdg/cache", "smix", "reviews")},
+		{configured: "/srv/reviews", want: "/srv/reviews"},
+		{configured: "~/reviews", want: filepath.Join(home, "reviews")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.configured, func(t *testing.T) {
+			got, err := OutputBase(tt.configured)
+			if err != nil {
+				t.Fatalf("OutputBase() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("OutputBase(%q) = %q, want %q", tt.configured, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package pr
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// DefaultOverlapWindow is how many lines apart two comments on the same file can be and still
+// be treated as overlapping
+const DefaultOverlapWindow = 3
+
+// findOverlaps groups the indices of file comments that target the same file within window lines
+// of each other. Nearby comments chain, so lines 10, 12 and 14 form one group with a window of 2.
+// Only groups with more than one item are returned, ordered by their first item.
+func findOverlaps(items []FeedbackItem, window int) [][]int {
+	if window < 0 {
+		return nil
+	}
+
+	byFile := make(map[string][]int)
+	var files []string
+	for i, item := range items {
+		if item.File == "" {
+			continue
+		}
+		if _, ok := byFile[item.File]; !ok {
+			files = append(files, item.File)
+		}
+		byFile[item.File] = append(byFile[item.File], i)
+	}
+
+	var groups [][]int
+	for _, file := range files {
+		indices := byFile[file]
+		slices.SortStableFunc(indices, func(a, b int) int { return items[a].Line - items[b].Line })
+
+		group := []int{indices[0]}
+		for _, idx := range indices[1:] {
+			if items[idx].Line-items[group[len(group)-1]].Line <= window {
+				group = append(group, idx)
+				continue
+			}
+			if len(group) > 1 {
+				groups = append(groups, group)
+			}
+			group = []int{idx}
+		}
+		if len(group) > 1 {
+			groups = append(groups, group)
+		}
+	}
+
+	for _, group := range groups {
+		slices.Sort(group)
+	}
+	slices.SortFunc(groups, func(a, b []int) int { return a[0] - b[0] })
+	return groups
+}
+
+// warnOverlaps logs a warning for each group of overlapping items
+func warnOverlaps(items []FeedbackItem, groups [][]int, grouped bool) {
+	for _, group := range groups {
+		lines := make([]int, len(group))
+		for i, idx := range group {
+			lines[i] = items[idx].Line
+		}
+		msg := "feedback items target overlapping lines; applying them in sequence may conflict (pass --group-overlapping to combine them)"
+		if grouped {
+			msg = "combining feedback items that target overlapping lines into one prompt"
+		}
+		slog.Warn(msg, "file", items[group[0]].File, "lines", formatLines(lines))
+	}
+}
+
+// mergeOverlaps combines each group of overlapping items into a single item at the position of
+// its first member, so one session reconciles all of the feedback together
+func mergeOverlaps(items []FeedbackItem, groups [][]int) []FeedbackItem {
+	if len(groups) == 0 {
+		return items
+	}
+
+	merged := make(map[int]FeedbackItem)
+	absorbed := make(map[int]bool)
+	for _, group := range groups {
+		members := make([]FeedbackItem, len(group))
+		for i, idx := range group {
+			members[i] = items[idx]
+			if i > 0 {
+				absorbed[idx] = true
+			}
+		}
+		merged[group[0]] = combineItems(members)
+	}
+
+	var result []FeedbackItem
+	for i, item := range items {
+		if absorbed[i] {
+			continue
+		}
+		if combined, ok := merged[i]; ok {
+			item = combined
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// combineItems joins items on the same file into one item whose body lists each comment
+func combineItems(items []FeedbackItem) FeedbackItem {
+	combined := items[0]
+	combined.Lines = nil
+	combined.Replies = nil
+
+	var body strings.Builder
+	var hunks []string
+	fmt.Fprintf(&body, "> %d comments target nearby lines of this file. Reconcile them together.\n", len(items))
+	for i, item := range items {
+		fmt.Fprintf(&body, "\n### Comment %d (line %d)\n\n%s\n", i+1, item.Line, strings.TrimSpace(item.Body))
+
+		lines := item.Lines
+		if len(lines) == 0 {
+			lines = []int{item.Line}
+		}
+		combined.Lines = append(combined.Lines, lines...)
+		combined.Replies = append(combined.Replies, item.Replies...)
+		if item.DiffHunk != "" && !slices.Contains(hunks, item.DiffHunk) {
+			hunks = append(hunks, item.DiffHunk)
+		}
+	}
+
+	slices.Sort(combined.Lines)
+	combined.Lines = slices.Compact(combined.Lines)
+	combined.Line = combined.Lines[0]
+	if len(combined.Lines) < 2 {
+		combined.Lines = nil
+	}
+	combined.Body = body.String()
+	combined.DiffHunk = strings.Join(hunks, "\n")
+	return combined
+}
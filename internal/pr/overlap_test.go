@@ -0,0 +1,96 @@
+package pr
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindOverlaps(t *testing.T) {
+	items := []FeedbackItem{
+		{File: "main.go", Line: 10, Body: "a"},
+		{File: "util.go", Line: 10, Body: "b"},
+		{File: "main.go", Line: 12, Body: "c"},
+		{File: "main.go", Line: 40, Body: "d"},
+		{Body: "general"},
+		{File: "util.go", Line: 50, Body: "e"},
+	}
+
+	tests := []struct {
+		name   string
+		window int
+		want   [][]int
+	}{
+		{"overlapping pair within window", 3, [][]int{{0, 2}}},
+		{"window too small", 1, nil},
+		{"wide window chains", 30, [][]int{{0, 2, 3}}},
+		{"negative disables", -1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findOverlaps(items, tt.window); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findOverlaps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeOverlaps(t *testing.T) {
+	items := []FeedbackItem{
+		{File: "main.go", Line: 12, Body: "Check the error.", DiffHunk: "@@ -1 +1 @@", CommentID: 1},
+		{File: "util.go", Line: 5, Body: "Unrelated.", CommentID: 2},
+		{File: "main.go", Line: 10, Body: "Rename this variable.", DiffHunk: "@@ -1 +1 @@", CommentID: 3, Replies: []ThreadReply{{Author: "dev", Body: "ok"}}},
+		{File: "util.go", Line: 30, Body: "Also unrelated.", CommentID: 4},
+	}
+
+	groups := findOverlaps(items, DefaultOverlapWindow)
+	got := mergeOverlaps(items, groups)
+
+	if len(got) != 3 {
+		t.Fatalf("got %d items, want 3: %+v", len(got), got)
+	}
+	combined := got[0]
+	if combined.File != "main.go" || combined.Line != 10 || fmt.Sprint(combined.Lines) != "[10 12]" {
+		t.Errorf("combined target = %s:%d lines %v, want main.go:10 lines [10 12]", combined.File, combined.Line, combined.Lines)
+	}
+	for _, want := range []string{"2 comments target nearby lines", "### Comment 1 (line 12)\n\nCheck the error.", "### Comment 2 (line 10)\n\nRename this variable."} {
+		if !strings.Contains(combined.Body, want) {
+			t.Errorf("combined body missing %q:\n%s", want, combined.Body)
+		}
+	}
+	if combined.DiffHunk != "@@ -1 +1 @@" {
+		t.Errorf("DiffHunk = %q, want the shared hunk once", combined.DiffHunk)
+	}
+	if len(combined.Replies) != 1 {
+		t.Errorf("Replies = %v, want the reply carried over", combined.Replies)
+	}
+	if got[1].CommentID != 2 || got[2].CommentID != 4 {
+		t.Errorf("non-overlapping items = %+v, want them unchanged and in order", got[1:])
+	}
+}
+
+func TestWarnOverlaps(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	items := []FeedbackItem{
+		{File: "main.go", Line: 10},
+		{File: "main.go", Line: 11},
+		{File: "main.go", Line: 90},
+	}
+	warnOverlaps(items, findOverlaps(items, DefaultOverlapWindow), false)
+
+	output := buf.String()
+	if strings.Count(output, "level=WARN") != 1 {
+		t.Fatalf("want exactly one warning, got:\n%s", output)
+	}
+	if !strings.Contains(output, "file=main.go") || !strings.Contains(output, `lines="10, 11"`) {
+		t.Errorf("warning missing file or lines:\n%s", output)
+	}
+}
@@ -4,22 +4,146 @@ package pr
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
 
 	"github.com/connorhough/smix/internal/config"
 	"github.com/connorhough/smix/internal/llm"
 	"github.com/connorhough/smix/internal/providers"
 )
 
+// ProcessOptions configures how ProcessReviews handles feedback files
+type ProcessOptions struct {
+	// DryRun lists the feedback files and resolved provider without launching sessions
+	DryRun bool
+	// PromptTemplate is the path to a text/template file for the session prompt (built-in when empty)
+	PromptTemplate string
+	// Progress receives status messages between sessions. Nil discards them.
+	Progress io.Writer
+	// Out receives the DryRun plan. Nil uses os.Stdout.
+	Out io.Writer
+	// Providers distributes items across several providers in round-robin order, failing over
+	// on rate limits. Overrides the configured provider; the configured model is only used
+	// when a single provider is listed.
+	Providers []string
+	// Limit processes only the first Limit feedback files in numeric order. Zero processes all.
+	Limit int
+	// SkipInvalid drops feedback files that fail ValidateFeedbackFile instead of only warning about them
+	SkipInvalid bool
+	// ConfirmThreshold asks for confirmation before launching more than this many sessions.
+	// Zero uses DefaultConfirmThreshold; a negative value never asks.
+	ConfirmThreshold int
+	// Yes launches sessions without asking for confirmation
+	Yes bool
+	// Delay is waited between session launches (via llm.DefaultClock) to stay under provider
+	// rate limits. Zero launches them back to back.
+	Delay time.Duration
+	// RetryStatuses reprocesses only the feedback files whose decision in decisions.json has
+	// one of these statuses (e.g. StatusFailed). Empty processes every file.
+	RetryStatuses []string
+}
+
+// filesWithStatus keeps the feedback files whose recorded decision has one of statuses.
+// Decisions are matched by prompt file name; files without a decision are dropped.
+func filesWithStatus(files []string, decisions []Decision, statuses []string) []string {
+	recorded := make(map[string]string, len(decisions))
+	for _, d := range decisions {
+		recorded[filepath.Base(d.FeedbackFile)] = d.Status
+	}
+
+	var kept []string
+	for _, file := range files {
+		status, ok := recorded[filepath.Base(file)]
+		if !ok {
+			continue
+		}
+		for _, want := range statuses {
+			if strings.EqualFold(status, want) {
+				kept = append(kept, file)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+// DefaultConfirmThreshold is the number of sessions that can be launched without confirmation
+const DefaultConfirmThreshold = 10
+
 // ProcessReviews processes pull request feedback files and launches interactive provider sessions for each one.
-// Requires a provider that implements InteractiveProvider and a TTY.
-func ProcessReviews(ctx context.Context, feedbackDir string, cfg *config.ProviderConfig) error {
+// Requires a provider that implements InteractiveProvider and a TTY, unless opts.DryRun is set.
+func ProcessReviews(ctx context.Context, feedbackDir string, cfg *config.ProviderConfig, opts ProcessOptions) error {
+	progress := opts.Progress
+	if progress == nil {
+		progress = io.Discard
+	}
+
 	if _, err := os.Stat(feedbackDir); os.IsNotExist(err) {
 		return fmt.Errorf("directory '%s' does not exist", feedbackDir)
 	}
 
+	filteredFiles, err := findFeedbackFiles(feedbackDir)
+	if err != nil {
+		return err
+	}
+	filteredFiles, err = validateFeedbackFiles(filteredFiles, opts.SkipInvalid)
+	if err != nil {
+		return err
+	}
+	if len(opts.RetryStatuses) > 0 {
+		decisions, err := LoadDecisions(feedbackDir)
+		if err != nil {
+			return err
+		}
+		total := len(filteredFiles)
+		filteredFiles = filesWithStatus(filteredFiles, decisions, opts.RetryStatuses)
+		fmt.Fprintf(progress, "Retrying %d of %d feedback files with status %s\n", len(filteredFiles), total, strings.Join(opts.RetryStatuses, " or "))
+		if len(filteredFiles) == 0 {
+			return nil
+		}
+	}
+	if opts.Limit > 0 && len(filteredFiles) > opts.Limit {
+		fmt.Fprintf(progress, "Processing the first %d of %d feedback files\n", opts.Limit, len(filteredFiles))
+		filteredFiles = filteredFiles[:opts.Limit]
+	}
+
+	promptTmpl, err := LoadPromptTemplate(opts.PromptTemplate)
+	if err != nil {
+		return err
+	}
+
+	// Get provider names from options or config, default to claude
+	providerNames := opts.Providers
+	if len(providerNames) == 0 {
+		providerName := cfg.Provider
+		if providerName == "" {
+			providerName = "claude"
+		}
+		providerNames = []string{providerName}
+	}
+	if len(providerNames) > 1 && cfg.Model != "" {
+		slog.Warn("ignoring model with multiple providers; each provider uses its default model", "model", cfg.Model)
+		cfg = &config.ProviderConfig{Provider: cfg.Provider, Retries: cfg.Retries, StrictModels: cfg.StrictModels}
+	}
+
+	if opts.DryRun {
+		out := opts.Out
+		if out == nil {
+			out = os.Stdout
+		}
+		writeReviewPlan(out, filteredFiles, strings.Join(providerNames, ", "), cfg.Model)
+		return nil
+	}
+
 	// Create IOStreams for interactive mode
 	streams := llm.NewIOStreams()
 
@@ -27,72 +151,352 @@ func ProcessReviews(ctx context.Context, feedbackDir string, cfg *config.Provide
 		return fmt.Errorf("pr review command requires an interactive terminal (TTY). This command cannot run in CI/CD pipelines or with redirected stdin")
 	}
 
-	// Get provider name from config, default to claude
-	providerName := cfg.Provider
-	if providerName == "" {
-		providerName = "claude"
+	resolved := make([]llm.Provider, 0, len(providerNames))
+	for _, name := range providerNames {
+		provider, err := providers.GetProvider(ctx, name)
+		if err != nil {
+			return fmt.Errorf("failed to get %s provider: %w", name, err)
+		}
+		if err := providers.CheckModel(provider, cfg); err != nil {
+			return err
+		}
+
+		// Verify provider supports interactive mode
+		if _, ok := provider.(llm.InteractiveProvider); !ok {
+			return fmt.Errorf("provider %q does not support interactive mode (required for pr command). Interactive mode requires a provider that can yield control of stdin/stdout/stderr", provider.Name())
+		}
+		resolved = append(resolved, provider)
+	}
+	dispatch := newDispatcher(resolved)
+
+	totalCount := len(filteredFiles)
+	fmt.Fprintf(progress, "Found %d feedback files to process\n", totalCount)
+	fmt.Fprintf(progress, "Using interactive provider: %s\n", strings.Join(providerNames, ", "))
+	fmt.Fprintln(progress, "Launching interactive sessions for each feedback item...")
+	fmt.Fprintln(progress)
+
+	launchSessions(ctx, streams, progress, dispatch, filteredFiles, cfg, promptTmpl, opts)
+	return nil
+}
+
+// launchSessions runs reviewItems over files, first asking for confirmation when there are more
+// files than opts.ConfirmThreshold and opts.Yes is not set
+func launchSessions(ctx context.Context, streams *llm.IOStreams, progress io.Writer, dispatch *dispatcher, files []string, cfg *config.ProviderConfig, promptTmpl *template.Template, opts ProcessOptions) {
+	threshold := opts.ConfirmThreshold
+	if threshold == 0 {
+		threshold = DefaultConfirmThreshold
+	}
+	if !opts.Yes && threshold > 0 && len(files) > threshold && !confirmLaunch(streams, files) {
+		fmt.Fprintln(progress, "Aborted: no sessions launched")
+		return
+	}
+
+	reviewItems(ctx, streams, progress, dispatch, files, cfg, promptTmpl, opts.Delay)
+}
+
+// confirmLaunch lists files and asks on streams whether to launch a session for each.
+// Only "y" or "yes" confirms; anything else, including end of input, declines.
+func confirmLaunch(streams *llm.IOStreams, files []string) bool {
+	writeFileList(streams.Out, files)
+	fmt.Fprintf(streams.Out, "\nLaunch %d interactive sessions? [y/N]: ", len(files))
+	line, _ := readLine(streams.In)
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// Actions offered between feedback items
+const (
+	actionNext  = "n"
+	actionSkip  = "s"
+	actionRetry = "r"
+	actionQuit  = "q"
+)
+
+// reviewItems launches a session for each feedback file in turn, waiting delay between launches.
+// After each session the user chooses to continue, skip the next item, retry the current one,
+// or quit, via streams.In. Sessions that fail to launch are recorded as FAILED in the feedback
+// directory's decisions.json so they can be reprocessed with RetryStatuses.
+func reviewItems(ctx context.Context, streams *llm.IOStreams, progress io.Writer, dispatch *dispatcher, files []string, cfg *config.ProviderConfig, promptTmpl *template.Template, delay time.Duration) *reviewSummary {
+	totalCount := len(files)
+	summary := newReviewSummary(totalCount)
+	start := time.Now()
+	style := streams.Styler()
+	separator := style.Cyan("--------")
+
+	quit := false
+	launched := false
+	for i := 0; i < totalCount && !quit; {
+		feedbackFile := files[i]
+		basename := filepath.Base(feedbackFile)
+
+		if launched && delay > 0 {
+			fmt.Fprintf(progress, "Waiting %s before the next session...\n", delay)
+			if err := llm.DefaultClock.Sleep(ctx, delay); err != nil {
+				quit = true
+				break
+			}
+		}
+		launched = true
+
+		fmt.Fprintln(progress, separator)
+		fmt.Fprintln(progress, style.Bold(fmt.Sprintf("Processing [%d/%d]: %s", i+1, totalCount, basename)))
+		fmt.Fprintln(progress, separator)
+		fmt.Fprintln(progress)
+
+		targetFile := extractTargetFile(feedbackFile)
+
+		fmt.Fprintf(progress, "Launching interactive session...\n")
+		_, err := dispatch.Do(func(provider llm.Provider) error {
+			if len(dispatch.providers) > 1 {
+				fmt.Fprintf(progress, "Using provider: %s\n", provider.Name())
+			}
+			return LaunchClaudeCode(ctx, provider, streams, feedbackFile, targetFile, i+1, totalCount, cfg, promptTmpl)
+		})
+		if err != nil {
+			fmt.Fprintf(streams.ErrOut, "%s %v\n", style.Red("Failed to launch interactive session:"), err)
+		}
+		if recordErr := recordLaunch(feedbackFile, targetFile, err); recordErr != nil {
+			fmt.Fprintf(streams.ErrOut, "warning: %v\n", recordErr)
+		}
+		fmt.Fprintln(progress)
+
+		// An interrupt (Ctrl-C) cancels ctx and ends the session; stop instead of launching the next one
+		if ctx.Err() != nil {
+			summary.Processed++
+			if err != nil {
+				summary.Failed++
+			}
+			quit = true
+			break
+		}
+
+		action := promptAction(streams, files, i)
+		if action == actionRetry {
+			continue
+		}
+
+		summary.Processed++
+		if err != nil {
+			summary.Failed++
+		}
+		i++
+
+		switch action {
+		case actionSkip:
+			if i < totalCount {
+				fmt.Fprintf(progress, "Skipping [%d/%d]: %s\n\n", i+1, totalCount, filepath.Base(files[i]))
+				summary.Skipped++
+				i++
+			}
+		case actionQuit:
+			quit = true
+		}
+	}
+
+	summary.Elapsed = time.Since(start)
+	countDecisions(summary, files)
+
+	fmt.Fprintln(progress, separator)
+	if quit {
+		fmt.Fprintln(progress, style.Yellow("Review stopped."))
+	} else {
+		fmt.Fprintln(progress, style.Green("All feedback items processed!"))
 	}
+	fmt.Fprintln(progress, summary.String())
+	fmt.Fprintln(progress, separator)
+
+	return summary
+}
 
-	provider, err := providers.GetProvider(ctx, providerName)
+// recordLaunch records a session that failed to launch as FAILED in the feedback file's
+// directory. After a successful launch an earlier FAILED decision for the file is dropped,
+// since the session's own outcome is not known.
+func recordLaunch(feedbackFile, targetFile string, launchErr error) error {
+	dir, name := filepath.Dir(feedbackFile), filepath.Base(feedbackFile)
+	if launchErr != nil {
+		return RecordDecision(dir, Decision{FeedbackFile: name, Status: StatusFailed, File: targetFile, Reasoning: launchErr.Error()})
+	}
+	return forgetDecision(dir, name, StatusFailed)
+}
+
+// countDecisions tallies the recorded decisions for files into summary.Decisions
+func countDecisions(summary *reviewSummary, files []string) {
+	if len(files) == 0 {
+		return
+	}
+	decisions, err := readDecisions(filepath.Dir(files[0]))
 	if err != nil {
-		return fmt.Errorf("failed to get %s provider: %w", providerName, err)
+		return
 	}
+	names := make(map[string]bool, len(files))
+	for _, file := range files {
+		names[filepath.Base(file)] = true
+	}
+	for _, d := range decisions {
+		if names[filepath.Base(d.FeedbackFile)] {
+			summary.Decisions[strings.ToUpper(d.Status)]++
+		}
+	}
+}
 
-	// Verify provider supports interactive mode
-	if _, ok := provider.(llm.InteractiveProvider); !ok {
-		return fmt.Errorf("provider %q does not support interactive mode (required for pr command). Interactive mode requires a provider that can yield control of stdin/stdout/stderr", provider.Name())
+// promptAction asks what to do after the session for files[current] and returns one of the action
+// constants. Enter and end of input mean next; unrecognized answers repeat the prompt.
+func promptAction(streams *llm.IOStreams, files []string, current int) string {
+	next := "end of review"
+	if current+1 < len(files) {
+		next = filepath.Base(files[current+1])
 	}
 
-	// Find all feedback markdown files excluding INDEX.md
-	feedbackFiles, err := filepath.Glob(filepath.Join(feedbackDir, "*.md"))
+	for {
+		fmt.Fprintf(streams.Out, "Up next: %s\n[n]ext / [s]kip / [r]etry / [q]uit: ", next)
+		line, err := readLine(streams.In)
+		answer := strings.ToLower(strings.TrimSpace(line))
+		switch answer {
+		case "", actionNext, "next":
+			return actionNext
+		case actionSkip, "skip":
+			return actionSkip
+		case actionRetry, "retry":
+			return actionRetry
+		case actionQuit, "quit":
+			return actionQuit
+		}
+		if err != nil {
+			return actionNext
+		}
+		fmt.Fprintf(streams.Out, "Unknown choice %q\n", answer)
+	}
+}
+
+// readLine reads a single line from r one byte at a time so that nothing past the newline is
+// consumed; the remaining input belongs to the next interactive session.
+func readLine(r io.Reader) (string, error) {
+	var line []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				return string(line), nil
+			}
+			line = append(line, buf[0])
+		}
+		if err != nil {
+			return string(line), err
+		}
+	}
+}
+
+// findFeedbackFiles returns the feedback prompt files in dir, excluding INDEX.md, ordered by
+// their item number so that 10_... follows 9_...
+func findFeedbackFiles(dir string) ([]string, error) {
+	feedbackFiles, err := filepath.Glob(filepath.Join(dir, "*.md"))
 	if err != nil {
-		return fmt.Errorf("failed to find feedback files: %w", err)
+		return nil, fmt.Errorf("failed to find feedback files: %w", err)
 	}
 
 	var filteredFiles []string
 	for _, file := range feedbackFiles {
-		if filepath.Base(file) != "INDEX.md" {
+		if filepath.Base(file) != IndexFile {
 			filteredFiles = append(filteredFiles, file)
 		}
 	}
 
 	if len(filteredFiles) == 0 {
-		return fmt.Errorf("no feedback files found in %s", feedbackDir)
+		return nil, fmt.Errorf("no feedback files found in %s", dir)
 	}
 
-	totalCount := len(filteredFiles)
-	fmt.Printf("Found %d feedback files to process\n", totalCount)
-	fmt.Printf("Using interactive provider: %s\n", provider.Name())
-	fmt.Println("Launching interactive sessions for each feedback item...")
-	fmt.Println()
+	sort.SliceStable(filteredFiles, func(i, j int) bool {
+		return itemNumber(filteredFiles[i]) < itemNumber(filteredFiles[j])
+	})
 
-	for i, feedbackFile := range filteredFiles {
-		basename := filepath.Base(feedbackFile)
+	return filteredFiles, nil
+}
+
+// itemNumber returns the numeric prefix of a prompt file name such as 3_main_go_line10.md.
+// Files without one sort after numbered files.
+func itemNumber(file string) int {
+	prefix, _, _ := strings.Cut(filepath.Base(file), "_")
+	n, err := strconv.Atoi(prefix)
+	if err != nil {
+		return math.MaxInt
+	}
+	return n
+}
 
-		fmt.Println("--------")
-		fmt.Printf("Processing [%d/%d]: %s\n", i+1, totalCount, basename)
-		fmt.Println("--------")
-		fmt.Println()
+// writeReviewPlan prints the feedback files that would be processed and the resolved provider
+func writeReviewPlan(w io.Writer, files []string, providerName, model string) {
+	if model == "" {
+		model = "(provider default)"
+	}
 
-		targetFile := extractTargetFile(feedbackFile)
+	fmt.Fprintf(w, "Dry run: %d feedback files would be processed\n", len(files))
+	fmt.Fprintf(w, "Provider: %s\n", providerName)
+	fmt.Fprintf(w, "Model: %s\n", model)
+	fmt.Fprintln(w)
 
-		fmt.Printf("Launching interactive session...\n")
-		if err := LaunchClaudeCode(ctx, provider, streams, feedbackFile, targetFile, i+1, totalCount, cfg); err != nil {
-			fmt.Printf("Failed to launch interactive session: %v\n", err)
+	writeFileList(w, files)
+}
+
+// writeFileList prints a numbered line per feedback file with the file it targets
+func writeFileList(w io.Writer, files []string) {
+	for i, file := range files {
+		target := extractTargetFile(file)
+		if target == "" {
+			target = "(general comment)"
 		}
+		fmt.Fprintf(w, "[%d/%d] %s -> %s\n", i+1, len(files), filepath.Base(file), target)
+	}
+}
 
-		fmt.Println()
+// reviewSummary tallies the outcome of a review run for the final report
+type reviewSummary struct {
+	Total     int
+	Processed int
+	Failed    int
+	Skipped   int
+	// Decisions counts recorded decisions (e.g. APPLIED, REJECTED) when available
+	Decisions map[string]int
+	Elapsed   time.Duration
+}
+
+func newReviewSummary(total int) *reviewSummary {
+	return &reviewSummary{
+		Total:     total,
+		Decisions: make(map[string]int),
 	}
+}
 
-	fmt.Println("--------")
-	fmt.Println("All feedback items processed!")
-	fmt.Println("--------")
+// String renders the summary as a single line
+func (s *reviewSummary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Summary: %d of %d items processed, %d failed", s.Processed, s.Total, s.Failed)
+	if s.Skipped > 0 {
+		fmt.Fprintf(&b, ", %d skipped", s.Skipped)
+	}
 
-	return nil
+	if len(s.Decisions) > 0 {
+		names := make([]string, 0, len(s.Decisions))
+		for name := range s.Decisions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(&b, ", %d %s", s.Decisions[name], strings.ToLower(name))
+		}
+	}
+
+	fmt.Fprintf(&b, " (elapsed %s)", s.Elapsed.Round(time.Second))
+	return b.String()
 }
 
 // LaunchClaudeCode opens an interactive session with the provider to review feedback and implement changes.
-func LaunchClaudeCode(ctx context.Context, provider llm.Provider, streams *llm.IOStreams, feedbackFile, targetFile string, currentIndex, totalCount int, cfg *config.ProviderConfig) error {
+// The prompt is rendered from promptTmpl, or the built-in template when promptTmpl is nil.
+func LaunchClaudeCode(ctx context.Context, provider llm.Provider, streams *llm.IOStreams, feedbackFile, targetFile string, currentIndex, totalCount int, cfg *config.ProviderConfig, promptTmpl *template.Template) error {
 	// Verify streams are interactive
 	if !streams.IsInteractive() {
 		return fmt.Errorf("interactive mode requires a terminal (TTY), but stdin is not a terminal. This can happen when running in CI/CD pipelines or when stdin is redirected")
@@ -104,52 +508,16 @@ func LaunchClaudeCode(ctx context.Context, provider llm.Provider, streams *llm.I
 		return fmt.Errorf("provider %q does not support interactive mode", provider.Name())
 	}
 
-	batchInfo := ""
-	if totalCount > 1 {
-		batchInfo = fmt.Sprintf("\n\n**Note:** This is feedback item %d of %d in this PR. Focus only on this item.", currentIndex, totalCount)
-	}
-
-	targetFileInfo := ""
-	if targetFile != "" {
-		targetFileInfo = fmt.Sprintf("\n**Target file to modify (if applying):** `%s`", targetFile)
+	prompt, err := renderPrompt(promptTmpl, PromptData{
+		FeedbackFile: feedbackFile,
+		TargetFile:   targetFile,
+		Index:        currentIndex,
+		Total:        totalCount,
+	})
+	if err != nil {
+		return err
 	}
 
-	prompt := fmt.Sprintf(`You are a Senior Software Engineer tasked with triaging and applying automated code review feedback.
-
-**Feedback Context:**
-%s%s%s
-
-## Your Agent Protocol
-You have access to file system tools and linters. You must execute the following steps in order:
-
-1.  **Investigate:**
-    * Read the feedback context provided above to understand the issue.
-    * **Tool Use:** Use your file reading tool to load the *current* version of the target file from disk. Do not rely solely on the feedback snippet.
-    * If the file does not exist, stop and report "File not found."
-
-2.  **Evaluate (Think Step):**
-    * Analyze the code. Is the feedback technically correct?
-    * Is this actionable? (Ignore low-value style nits unless they fix a linter violation).
-    * Does the fix introduce security risks or break existing logic?
-
-3.  **Execute (If Applying):**
-		* **Tool Use:** Plan out the changes that need to be made to address the feedback
-    * **Tool Use:** Apply the fix to the file using your editing tool if the changes are minimal. If the fix requires medium or large changes, dispatch sub-agents to solve each task, or prompt the user to manually launch sub-agents according to the tasks laid out in the plan.
-    * **Tool Use:** Run the appropriate linter/formatter for this file type (e.g., 'gofmt', 'eslint', 'black') to ensure the new code is valid.
-    * If the linter fails, attempt to self-correct or revert the changes.
-
-4.  **Execute (If Rejecting):**
-    * Do not modify any files.
-
-## Final Report (Output to User)
-After completing your actions, provide a concise summary in the following format:
-
-**STATUS:** [APPLIED | REJECTED | FAILED]
-**FILE:** [File Path]
-**ACTION TAKEN:** [One sentence summary of what you did, e.g., "Updated regex to fix ReDoS vulnerability and ran gofmt."]
-**REASONING:** [Brief explanation of why you made this decision.]
-`, feedbackFile, targetFileInfo, batchInfo)
-
 	// Use provider's interactive mode with injected streams
 	var opts []llm.Option
 	if cfg.Model != "" {
@@ -158,19 +526,86 @@ After completing your actions, provide a concise summary in the following format
 	return interactive.RunInteractive(ctx, streams, prompt, opts...)
 }
 
+// targetFilePattern matches the "- **Target File:** `path/to/file`" metadata line.
+// General comments have an empty path.
+var targetFilePattern = regexp.MustCompile(`(?m)^- \*\*Target File:\*\* ` + "`" + `([^` + "`" + `]*)` + "`" + `$`)
+
+// requiredFeedbackLines are the lines every prompt file written by FetchReviews starts with
+var requiredFeedbackLines = []string{
+	"## Metadata",
+	"- **Repository:**",
+	"- **Pull Request:**",
+	"## Reviewer Feedback",
+}
+
 // extractTargetFile extracts the target file path from a feedback markdown file
 func extractTargetFile(feedbackFile string) string {
 	content, err := os.ReadFile(feedbackFile)
 	if err != nil {
 		return ""
 	}
+	return parseTargetFile(string(content))
+}
 
-	// Look for "**Target File:** `path/to/file`" in the markdown
-	re := regexp.MustCompile(`(?m)^- \*\*Target File:\*\* ` + "`" + `([^` + "`" + `]+)` + "`" + `$`)
-	matches := re.FindStringSubmatch(string(content))
+// parseTargetFile returns the Target File path in prompt content, or "" when there is none
+func parseTargetFile(content string) string {
+	matches := targetFilePattern.FindStringSubmatch(content)
 	if len(matches) > 1 {
 		return matches[1]
 	}
-
 	return ""
 }
+
+// ValidateFeedbackFile checks that a prompt file has the metadata and feedback sections written
+// by FetchReviews, returning an error naming what is missing (e.g. after hand editing)
+func ValidateFeedbackFile(feedbackFile string) error {
+	content, err := os.ReadFile(feedbackFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", feedbackFile, err)
+	}
+
+	var missing []string
+	for _, want := range requiredFeedbackLines {
+		if !hasLinePrefix(string(content), want) {
+			missing = append(missing, strconv.Quote(want))
+		}
+	}
+	if !targetFilePattern.MatchString(string(content)) {
+		missing = append(missing, strconv.Quote("- **Target File:**"))
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s is missing %s", filepath.Base(feedbackFile), strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// hasLinePrefix reports whether any line of content starts with prefix
+func hasLinePrefix(content, prefix string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateFeedbackFiles warns about files that fail ValidateFeedbackFile and, when skipInvalid
+// is set, drops them. It fails when skipping leaves nothing to process.
+func validateFeedbackFiles(files []string, skipInvalid bool) ([]string, error) {
+	valid := make([]string, 0, len(files))
+	for _, file := range files {
+		if err := ValidateFeedbackFile(file); err != nil {
+			if skipInvalid {
+				slog.Warn("skipping malformed feedback file", "error", err)
+				continue
+			}
+			slog.Warn("malformed feedback file (pass --skip-invalid to skip it)", "error", err)
+		}
+		valid = append(valid, file)
+	}
+
+	if len(valid) == 0 {
+		return nil, fmt.Errorf("no valid feedback files to process")
+	}
+	return valid, nil
+}
@@ -1,9 +1,21 @@
 package pr
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/llm/llmtest"
 )
 
 func TestExtractTargetFile(t *testing.T) {
@@ -56,3 +68,605 @@ func TestExtractTargetFileNotFound(t *testing.T) {
 		t.Errorf("extractTargetFile() = %q, want %q", got, want)
 	}
 }
+
+func TestReviewSummaryString(t *testing.T) {
+	summary := newReviewSummary(5)
+	summary.Processed = 5
+	summary.Failed = 1
+	summary.Elapsed = 90 * time.Second
+
+	want := "Summary: 5 of 5 items processed, 1 failed (elapsed 1m30s)"
+	if got := summary.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	summary.Decisions["REJECTED"] = 1
+	summary.Decisions["APPLIED"] = 3
+	want = "Summary: 5 of 5 items processed, 1 failed, 3 applied, 1 rejected (elapsed 1m30s)"
+	if got := summary.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	summary.Skipped = 2
+	want = "Summary: 5 of 5 items processed, 1 failed, 2 skipped, 3 applied, 1 rejected (elapsed 1m30s)"
+	if got := summary.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func writeFeedbackFixtures(t *testing.T, dir string) {
+	t.Helper()
+	files := map[string]string{
+		"1_main_go_line10.md":  "- **Target File:** `main.go`\n",
+		"2_general_comment.md": "# General comment\n",
+		"INDEX.md":             "# Index\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestWriteReviewPlan(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFeedbackFixtures(t, tmpDir)
+
+	files, err := findFeedbackFiles(tmpDir)
+	if err != nil {
+		t.Fatalf("findFeedbackFiles() error = %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 feedback files (INDEX.md excluded), got %d", len(files))
+	}
+
+	var out bytes.Buffer
+	writeReviewPlan(&out, files, "claude", "")
+
+	output := out.String()
+	for _, want := range []string{
+		"Dry run: 2 feedback files would be processed",
+		"Provider: claude",
+		"Model: (provider default)",
+		"[1/2] 1_main_go_line10.md -> main.go",
+		"[2/2] 2_general_comment.md -> (general comment)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("plan missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "INDEX.md") {
+		t.Error("plan should not include INDEX.md")
+	}
+}
+
+func TestProcessReviews_DryRunSkipsProvider(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFeedbackFixtures(t, tmpDir)
+
+	// An unknown provider would fail if it were resolved, and tests have no TTY
+	cfg := &config.ProviderConfig{Provider: "does-not-exist"}
+	var out bytes.Buffer
+	if err := ProcessReviews(context.Background(), tmpDir, cfg, ProcessOptions{DryRun: true, Out: &out}); err != nil {
+		t.Fatalf("ProcessReviews() dry run error = %v", err)
+	}
+	for _, want := range []string{"1_main_go_line10.md", "2_general_comment.md", "does-not-exist"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("plan missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestFindFeedbackFiles_NumericOrder(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"10_b_go_line1.md", "2_a_go_line1.md", "1_general_comment.md", "INDEX.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("# Feedback\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := findFeedbackFiles(dir)
+	if err != nil {
+		t.Fatalf("findFeedbackFiles() error = %v", err)
+	}
+	var got []string
+	for _, file := range files {
+		got = append(got, filepath.Base(file))
+	}
+	want := []string{"1_general_comment.md", "2_a_go_line1.md", "10_b_go_line1.md"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("findFeedbackFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestProcessReviews_Limit(t *testing.T) {
+	tests := []struct {
+		name         string
+		limit        int
+		wantProgress string
+	}{
+		{name: "limit below count", limit: 1, wantProgress: "Processing the first 1 of 2 feedback files"},
+		{name: "limit above count", limit: 5, wantProgress: ""},
+		{name: "no limit", limit: 0, wantProgress: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			writeFeedbackFixtures(t, tmpDir)
+
+			var progress bytes.Buffer
+			cfg := &config.ProviderConfig{Provider: "does-not-exist"}
+			opts := ProcessOptions{DryRun: true, Limit: tt.limit, Progress: &progress, Out: io.Discard}
+			if err := ProcessReviews(context.Background(), tmpDir, cfg, opts); err != nil {
+				t.Fatalf("ProcessReviews() error = %v", err)
+			}
+			if got := strings.TrimSpace(progress.String()); got != tt.wantProgress {
+				t.Errorf("progress = %q, want %q", got, tt.wantProgress)
+			}
+		})
+	}
+}
+
+func TestFilesWithStatus(t *testing.T) {
+	files := []string{"/d/1_a_go_line1.md", "/d/2_b_go_line2.md", "/d/3_c_go_line3.md", "/d/4_d_go_line4.md", "/d/5_general_comment.md"}
+	decisions := []Decision{
+		{FeedbackFile: "1_a_go_line1.md", Status: StatusApplied},
+		{FeedbackFile: "2_b_go_line2.md", Status: StatusFailed},
+		{FeedbackFile: "pr_review_pr1/3_c_go_line3.md", Status: StatusRejected},
+		{FeedbackFile: "4_d_go_line4.md", Status: "failed"},
+	}
+
+	tests := []struct {
+		name     string
+		statuses []string
+		want     []string
+	}{
+		{name: "failed only", statuses: []string{StatusFailed}, want: []string{"/d/2_b_go_line2.md", "/d/4_d_go_line4.md"}},
+		{name: "failed and rejected", statuses: []string{StatusFailed, StatusRejected}, want: []string{"/d/2_b_go_line2.md", "/d/3_c_go_line3.md", "/d/4_d_go_line4.md"}},
+		{name: "no matches", statuses: []string{StatusSkipped}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filesWithStatus(files, decisions, tt.statuses); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filesWithStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessReviews_RetryFailed(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFeedbackFixtures(t, tmpDir)
+	cfg := &config.ProviderConfig{Provider: "does-not-exist"}
+
+	t.Run("requires decisions", func(t *testing.T) {
+		err := ProcessReviews(context.Background(), tmpDir, cfg, ProcessOptions{DryRun: true, RetryStatuses: []string{StatusFailed}})
+		if err == nil || !strings.Contains(err.Error(), DecisionsFile) {
+			t.Errorf("ProcessReviews() error = %v, want missing %s", err, DecisionsFile)
+		}
+	})
+
+	failing := filepath.Join(tmpDir, "1_main_go_line10.md")
+	general := filepath.Join(tmpDir, "2_general_comment.md")
+	review := func(fake *llmtest.FakeProvider, files ...string) *reviewSummary {
+		streams, in, _ := llm.TestIOStreams()
+		in.WriteString("n\nn\n")
+		return reviewItems(context.Background(), streams, io.Discard, newDispatcher([]llm.Provider{fake}), files, &config.ProviderConfig{}, nil, 0)
+	}
+
+	t.Run("launch failure is recorded", func(t *testing.T) {
+		summary := review(&llmtest.FakeProvider{InteractiveErr: errors.New("claude: command not found")}, failing)
+		review(&llmtest.FakeProvider{}, general)
+
+		decisions, err := LoadDecisions(tmpDir)
+		if err != nil {
+			t.Fatalf("LoadDecisions() error = %v", err)
+		}
+		want := Decision{FeedbackFile: "1_main_go_line10.md", Status: StatusFailed, File: "main.go", Reasoning: "claude: command not found"}
+		if len(decisions) != 1 || decisions[0] != want {
+			t.Errorf("decisions = %+v, want [%+v]", decisions, want)
+		}
+		if summary.Decisions[StatusFailed] != 1 || !strings.Contains(summary.String(), "1 failed") {
+			t.Errorf("summary = %q, want the recorded FAILED decision counted", summary.String())
+		}
+	})
+
+	t.Run("only failed items", func(t *testing.T) {
+		var progress, out bytes.Buffer
+		opts := ProcessOptions{DryRun: true, Progress: &progress, Out: &out, RetryStatuses: []string{StatusFailed}}
+		if err := ProcessReviews(context.Background(), tmpDir, cfg, opts); err != nil {
+			t.Fatalf("ProcessReviews() error = %v", err)
+		}
+		if got := strings.TrimSpace(progress.String()); got != "Retrying 1 of 2 feedback files with status FAILED" {
+			t.Errorf("progress = %q", got)
+		}
+		if !strings.Contains(out.String(), "1_main_go_line10.md") || strings.Contains(out.String(), "2_general_comment.md") {
+			t.Errorf("plan = %q, want only the failed item", out.String())
+		}
+	})
+
+	t.Run("successful retry clears the failure", func(t *testing.T) {
+		review(&llmtest.FakeProvider{}, failing)
+		decisions, err := readDecisions(tmpDir)
+		if err != nil {
+			t.Fatalf("readDecisions() error = %v", err)
+		}
+		if len(decisions) != 0 {
+			t.Errorf("decisions = %+v, want none", decisions)
+		}
+	})
+}
+
+func TestReviewItems_Navigation(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for i := 1; i <= 5; i++ {
+		file := filepath.Join(dir, fmt.Sprintf("%d_item.md", i))
+		if err := os.WriteFile(file, []byte("# Feedback\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+
+	tests := []struct {
+		name          string
+		input         string
+		wantItems     []int
+		wantProcessed int
+		wantSkipped   int
+		wantStopped   bool
+	}{
+		{
+			name:          "next skip retry quit",
+			input:         "n\ns\nr\nq\n",
+			wantItems:     []int{1, 2, 4, 4},
+			wantProcessed: 3,
+			wantSkipped:   1,
+			wantStopped:   true,
+		},
+		{
+			name:          "quit after first item",
+			input:         "q\n",
+			wantItems:     []int{1},
+			wantProcessed: 1,
+			wantStopped:   true,
+		},
+		{
+			name:          "enter and end of input continue",
+			input:         "\nnext\n",
+			wantItems:     []int{1, 2, 3, 4, 5},
+			wantProcessed: 5,
+		},
+		{
+			name:          "unknown answers re-prompt",
+			input:         "x\nS\nq\n",
+			wantItems:     []int{1, 3},
+			wantProcessed: 2,
+			wantSkipped:   1,
+			wantStopped:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streams, in, out := llm.TestIOStreams()
+			in.WriteString(tt.input)
+			fake := &llmtest.FakeProvider{}
+			var progress bytes.Buffer
+
+			summary := reviewItems(context.Background(), streams, &progress, newDispatcher([]llm.Provider{fake}), files, &config.ProviderConfig{}, nil, 0)
+
+			var gotItems []int
+			for _, prompt := range fake.InteractivePrompts() {
+				for i, file := range files {
+					if strings.Contains(prompt, file) {
+						gotItems = append(gotItems, i+1)
+					}
+				}
+			}
+			if fmt.Sprint(gotItems) != fmt.Sprint(tt.wantItems) {
+				t.Errorf("processed items = %v, want %v", gotItems, tt.wantItems)
+			}
+			if summary.Processed != tt.wantProcessed || summary.Skipped != tt.wantSkipped {
+				t.Errorf("summary processed=%d skipped=%d, want %d and %d", summary.Processed, summary.Skipped, tt.wantProcessed, tt.wantSkipped)
+			}
+			if stopped := strings.Contains(progress.String(), "Review stopped."); stopped != tt.wantStopped {
+				t.Errorf("stopped = %v, want %v", stopped, tt.wantStopped)
+			}
+			if !strings.Contains(out.String(), "[n]ext / [s]kip / [r]etry / [q]uit") {
+				t.Errorf("output missing action prompt: %q", out.String())
+			}
+		})
+	}
+}
+
+func TestReviewItems_StopsWhenInterrupted(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for i := 1; i <= 3; i++ {
+		file := filepath.Join(dir, fmt.Sprintf("%d_item.md", i))
+		if err := os.WriteFile(file, []byte("# Feedback\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	streams, _, out := llm.TestIOStreams()
+	fake := &llmtest.FakeProvider{InteractiveErr: context.Canceled}
+	var progress bytes.Buffer
+
+	summary := reviewItems(ctx, streams, &progress, newDispatcher([]llm.Provider{fake}), files, &config.ProviderConfig{}, nil, 0)
+
+	if n := len(fake.InteractivePrompts()); n != 1 {
+		t.Errorf("launched %d sessions, want 1", n)
+	}
+	if summary.Processed != 1 || summary.Failed != 1 {
+		t.Errorf("summary processed=%d failed=%d, want 1 and 1", summary.Processed, summary.Failed)
+	}
+	if !strings.Contains(progress.String(), "Review stopped.") {
+		t.Errorf("progress missing stop message:\n%s", progress.String())
+	}
+	if strings.Contains(out.String(), "[n]ext") {
+		t.Errorf("did not expect the action prompt after an interrupt: %q", out.String())
+	}
+}
+
+func TestLaunchSessions_Confirmation(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for i := 1; i <= 12; i++ {
+		file := filepath.Join(dir, fmt.Sprintf("%d_item.md", i))
+		if err := os.WriteFile(file, []byte("# Feedback\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+
+	tests := []struct {
+		name         string
+		input        string
+		opts         ProcessOptions
+		wantSessions int
+		wantAsked    bool
+	}{
+		{name: "declined", input: "n\n", wantSessions: 0, wantAsked: true},
+		{name: "end of input declines", input: "", wantSessions: 0, wantAsked: true},
+		{name: "confirmed", input: "y\nq\n", wantSessions: 1, wantAsked: true},
+		{name: "yes skips prompt", input: "q\n", opts: ProcessOptions{Yes: true}, wantSessions: 1},
+		{name: "under threshold", input: "q\n", opts: ProcessOptions{ConfirmThreshold: 20}, wantSessions: 1},
+		{name: "negative threshold never asks", input: "q\n", opts: ProcessOptions{ConfirmThreshold: -1}, wantSessions: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streams, in, out := llm.TestIOStreams()
+			in.WriteString(tt.input)
+			fake := &llmtest.FakeProvider{}
+			var progress bytes.Buffer
+
+			launchSessions(context.Background(), streams, &progress, newDispatcher([]llm.Provider{fake}), files, &config.ProviderConfig{}, nil, tt.opts)
+
+			if n := len(fake.InteractivePrompts()); n != tt.wantSessions {
+				t.Errorf("launched %d sessions, want %d", n, tt.wantSessions)
+			}
+			asked := strings.Contains(out.String(), "Launch 12 interactive sessions? [y/N]")
+			if asked != tt.wantAsked {
+				t.Errorf("asked = %v, want %v; output:\n%s", asked, tt.wantAsked, out.String())
+			}
+			if tt.wantAsked && !strings.Contains(out.String(), "[12/12] 12_item.md") {
+				t.Errorf("summary missing last file:\n%s", out.String())
+			}
+			if aborted := strings.Contains(progress.String(), "no sessions launched"); aborted != (tt.wantSessions == 0) {
+				t.Errorf("aborted = %v, want %v", aborted, tt.wantSessions == 0)
+			}
+		})
+	}
+}
+
+func TestReviewItems_Delay(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for i := 1; i <= 3; i++ {
+		file := filepath.Join(dir, fmt.Sprintf("%d_item.md", i))
+		if err := os.WriteFile(file, []byte("# Feedback\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+
+	tests := []struct {
+		name       string
+		input      string
+		delay      time.Duration
+		wantSleeps []time.Duration
+	}{
+		{"between each launch", "n\nn\n", 5 * time.Second, []time.Duration{5 * time.Second, 5 * time.Second}},
+		{"retry waits too", "r\nq\n", 2 * time.Second, []time.Duration{2 * time.Second}},
+		{"no delay", "n\nn\n", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := llmtest.NewFakeClock(time.Now())
+			llmtest.UseClock(t, clock)
+			streams, in, _ := llm.TestIOStreams()
+			in.WriteString(tt.input)
+			fake := &llmtest.FakeProvider{}
+			var progress bytes.Buffer
+
+			reviewItems(context.Background(), streams, &progress, newDispatcher([]llm.Provider{fake}), files, &config.ProviderConfig{}, nil, tt.delay)
+
+			if got := clock.Sleeps(); !reflect.DeepEqual(got, tt.wantSleeps) {
+				t.Errorf("sleeps = %v, want %v", got, tt.wantSleeps)
+			}
+		})
+	}
+}
+
+func TestReviewItems_DelayCancelled(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{filepath.Join(dir, "1_item.md"), filepath.Join(dir, "2_item.md")}
+	for _, file := range files {
+		if err := os.WriteFile(file, []byte("# Feedback\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A clock whose Sleep fails stands in for an interrupt during the delay
+	llmtest.UseClock(t, cancelledClock{})
+	streams, in, _ := llm.TestIOStreams()
+	in.WriteString("n\n")
+	fake := &llmtest.FakeProvider{}
+	var progress bytes.Buffer
+
+	summary := reviewItems(context.Background(), streams, &progress, newDispatcher([]llm.Provider{fake}), files, &config.ProviderConfig{}, nil, time.Second)
+
+	if n := len(fake.InteractivePrompts()); n != 1 {
+		t.Errorf("launched %d sessions, want 1", n)
+	}
+	if summary.Processed != 1 || !strings.Contains(progress.String(), "Review stopped.") {
+		t.Errorf("summary processed=%d, progress:\n%s", summary.Processed, progress.String())
+	}
+}
+
+// cancelledClock is an llm.Clock whose Sleep always reports cancellation
+type cancelledClock struct{}
+
+func (cancelledClock) Now() time.Time { return time.Now() }
+func (cancelledClock) Sleep(ctx context.Context, d time.Duration) error {
+	return context.Canceled
+}
+
+func TestReadLine_StopsAtNewline(t *testing.T) {
+	in := strings.NewReader("r\nleft for the session\n")
+	line, err := readLine(in)
+	if err != nil || line != "r" {
+		t.Fatalf("readLine() = %q, %v, want %q", line, err, "r")
+	}
+	rest, _ := readLine(in)
+	if rest != "left for the session" {
+		t.Errorf("remaining input = %q, want it untouched", rest)
+	}
+}
+
+func TestValidateFeedbackFile(t *testing.T) {
+	wellFormed := generatePatchPrompt("owner", "repo", 1, "main.go", "Check the error.", "package main", 1, "", "", nil, nil)
+	general := generatePatchPrompt("owner", "repo", 1, "", "Add tests.", "", 1, "", "", nil, nil)
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr []string
+	}{
+		{name: "well-formed", content: wellFormed},
+		{name: "general comment", content: general},
+		{
+			name:    "hand-edited without metadata",
+			content: "# Notes\n\nSome feedback I wrote myself.\n",
+			wantErr: []string{`"## Metadata"`, `"- **Repository:**"`, `"## Reviewer Feedback"`, `"- **Target File:**"`},
+		},
+		{
+			name:    "feedback section removed",
+			content: strings.Replace(wellFormed, "## Reviewer Feedback", "## Feedback", 1),
+			wantErr: []string{`"## Reviewer Feedback"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "1_main_go_line1.md")
+			if err := os.WriteFile(file, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := ValidateFeedbackFile(file)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("ValidateFeedbackFile() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("ValidateFeedbackFile() expected an error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q missing %s", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateFeedbackFiles(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "1_main_go_line1.md")
+	invalid := filepath.Join(dir, "2_notes.md")
+	if err := os.WriteFile(valid, []byte(generatePatchPrompt("o", "r", 1, "main.go", "Fix.", "", 1, "", "", nil, nil)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(invalid, []byte("# Notes\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		files       []string
+		skipInvalid bool
+		want        []string
+		wantErr     bool
+	}{
+		{name: "warn keeps malformed", files: []string{valid, invalid}, want: []string{valid, invalid}},
+		{name: "skip drops malformed", files: []string{valid, invalid}, skipInvalid: true, want: []string{valid}},
+		{name: "skip leaves nothing", files: []string{invalid}, skipInvalid: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := validateFeedbackFiles(tt.files, tt.skipInvalid)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateFeedbackFiles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("validateFeedbackFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReviewItems_RateLimitFailover(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for i := 1; i <= 2; i++ {
+		file := filepath.Join(dir, fmt.Sprintf("%d_item.md", i))
+		if err := os.WriteFile(file, []byte("# Feedback\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+
+	limited := &llmtest.FakeProvider{ProviderName: "claude", InteractiveErr: llm.ErrRateLimitExceeded("claude", errors.New("usage limit reached"))}
+	available := &llmtest.FakeProvider{ProviderName: "gemini"}
+	streams, in, _ := llm.TestIOStreams()
+	in.WriteString("n\nn\n")
+	var progress bytes.Buffer
+
+	summary := reviewItems(context.Background(), streams, &progress, newDispatcher([]llm.Provider{limited, available}), files, &config.ProviderConfig{}, nil, 0)
+
+	if got := len(available.InteractivePrompts()); got != 2 {
+		t.Errorf("gemini sessions = %d, want both items after claude was rate limited", got)
+	}
+	if got := len(limited.InteractivePrompts()); got != 1 {
+		t.Errorf("claude sessions = %d, want the one attempt that hit the rate limit", got)
+	}
+	if summary.Processed != 2 || summary.Failed != 0 {
+		t.Errorf("summary = %+v, want 2 processed and none failed", summary)
+	}
+	if decisions, err := readDecisions(dir); err != nil || len(decisions) != 0 {
+		t.Errorf("decisions = %+v, %v, want none recorded", decisions, err)
+	}
+}
@@ -0,0 +1,104 @@
+package pr
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// PromptData holds the fields available to a review prompt template
+type PromptData struct {
+	// FeedbackFile is the path to the feedback markdown file being processed
+	FeedbackFile string
+	// TargetFile is the file the feedback refers to, empty for general comments
+	TargetFile string
+	// Index is the 1-based position of this item in the batch
+	Index int
+	// Total is the number of feedback items in the batch
+	Total int
+}
+
+// defaultPromptTemplate is the built-in agent protocol used when no custom template is configured
+const defaultPromptTemplate = `You are a Senior Software Engineer tasked with triaging and applying automated code review feedback.
+
+**Feedback Context:**
+{{.FeedbackFile}}
+{{- if .TargetFile}}
+**Target file to modify (if applying):** ` + "`{{.TargetFile}}`" + `
+{{- end}}
+{{- if gt .Total 1}}
+
+**Note:** This is feedback item {{.Index}} of {{.Total}} in this PR. Focus only on this item.
+{{- end}}
+
+## Your Agent Protocol
+You have access to file system tools and linters. You must execute the following steps in order:
+
+1.  **Investigate:**
+    * Read the feedback context provided above to understand the issue.
+    * **Tool Use:** Use your file reading tool to load the *current* version of the target file from disk. Do not rely solely on the feedback snippet.
+    * If the file does not exist, stop and report "File not found."
+
+2.  **Evaluate (Think Step):**
+    * Analyze the code. Is the feedback technically correct?
+    * Is this actionable? (Ignore low-value style nits unless they fix a linter violation).
+    * Does the fix introduce security risks or break existing logic?
+
+3.  **Execute (If Applying):**
+    * **Tool Use:** Plan out the changes that need to be made to address the feedback
+    * **Tool Use:** Apply the fix to the file using your editing tool if the changes are minimal. If the fix requires medium or large changes, dispatch sub-agents to solve each task, or prompt the user to manually launch sub-agents according to the tasks laid out in the plan.
+    * **Tool Use:** Run the appropriate linter/formatter for this file type (e.g., 'gofmt', 'eslint', 'black') to ensure the new code is valid.
+    * If the linter fails, attempt to self-correct or revert the changes.
+
+4.  **Execute (If Rejecting):**
+    * Do not modify any files.
+
+## Final Report (Output to User)
+After completing your actions, provide a concise summary in the following format:
+
+**STATUS:** [APPLIED | REJECTED | FAILED]
+**FILE:** [File Path]
+**ACTION TAKEN:** [One sentence summary of what you did, e.g., "Updated regex to fix ReDoS vulnerability and ran gofmt."]
+**REASONING:** [Brief explanation of why you made this decision.]
+`
+
+var builtinPromptTemplate = template.Must(template.New("builtin").Option("missingkey=error").Parse(defaultPromptTemplate))
+
+// LoadPromptTemplate parses the review prompt template at path, or returns the built-in template when path is empty.
+// Errors are returned for unreadable files and templates that fail to parse or reference unknown fields.
+func LoadPromptTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return builtinPromptTemplate, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompt template: %w", err)
+	}
+
+	tmpl, err := template.New(path).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt template %s: %w", path, err)
+	}
+
+	// Execute against sample data so references to unknown fields fail now rather than mid-review
+	if _, err := renderPrompt(tmpl, PromptData{FeedbackFile: "feedback.md", TargetFile: "main.go", Index: 1, Total: 1}); err != nil {
+		return nil, fmt.Errorf("invalid prompt template %s: %w", path, err)
+	}
+
+	return tmpl, nil
+}
+
+// renderPrompt executes tmpl with data, falling back to the built-in template when tmpl is nil
+func renderPrompt(tmpl *template.Template, data PromptData) (string, error) {
+	if tmpl == nil {
+		tmpl = builtinPromptTemplate
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt template: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,113 @@
+package pr
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderPrompt_Builtin(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    PromptData
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "batch item with target",
+			data: PromptData{FeedbackFile: "01_main.go.md", TargetFile: "main.go", Index: 2, Total: 3},
+			want: []string{
+				"01_main.go.md\n**Target file to modify (if applying):** `main.go`",
+				"feedback item 2 of 3",
+				"**STATUS:** [APPLIED | REJECTED | FAILED]",
+			},
+		},
+		{
+			name:    "single general comment",
+			data:    PromptData{FeedbackFile: "00_general.md", Index: 1, Total: 1},
+			want:    []string{"**Feedback Context:**\n00_general.md\n\n## Your Agent Protocol"},
+			notWant: []string{"Target file to modify", "feedback item"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderPrompt(nil, tt.data)
+			if err != nil {
+				t.Fatalf("renderPrompt() error = %v", err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("prompt missing %q\n%s", s, got)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(got, s) {
+					t.Errorf("prompt should not contain %q\n%s", s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadPromptTemplate_Custom(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompt.tmpl")
+	content := "Review {{.FeedbackFile}} for {{.TargetFile}} ({{.Index}}/{{.Total}})"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := LoadPromptTemplate(path)
+	if err != nil {
+		t.Fatalf("LoadPromptTemplate() error = %v", err)
+	}
+
+	got, err := renderPrompt(tmpl, PromptData{FeedbackFile: "03_api.go.md", TargetFile: "api.go", Index: 3, Total: 7})
+	if err != nil {
+		t.Fatalf("renderPrompt() error = %v", err)
+	}
+
+	want := "Review 03_api.go.md for api.go (3/7)"
+	if got != want {
+		t.Errorf("renderPrompt() = %q, want %q", got, want)
+	}
+}
+
+func TestLoadPromptTemplate_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"parse error", "Review {{.FeedbackFile"},
+		{"unknown field", "Review {{.Feedback}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "_")+".tmpl")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadPromptTemplate(path); err == nil {
+				t.Error("expected error for invalid template")
+			}
+		})
+	}
+
+	if _, err := LoadPromptTemplate(filepath.Join(dir, "missing.tmpl")); err == nil {
+		t.Error("expected error for missing template file")
+	}
+}
+
+func TestLoadPromptTemplate_DefaultWhenEmpty(t *testing.T) {
+	tmpl, err := LoadPromptTemplate("")
+	if err != nil {
+		t.Fatalf("LoadPromptTemplate(\"\") error = %v", err)
+	}
+	if tmpl != builtinPromptTemplate {
+		t.Error("expected built-in template when path is empty")
+	}
+}
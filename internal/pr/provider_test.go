@@ -64,7 +64,7 @@ func TestLaunchInteractiveSession_Success(t *testing.T) {
 	targetFile := "main.go"
 	cfg := &config.ProviderConfig{Provider: "mock", Model: ""}
 
-	err := LaunchClaudeCode(ctx, provider, streams, feedbackFile, targetFile, 1, 3, cfg)
+	err := LaunchClaudeCode(ctx, provider, streams, feedbackFile, targetFile, 1, 3, cfg, nil)
 	if err != nil {
 		t.Errorf("LaunchClaudeCode() error = %v", err)
 	}
@@ -103,7 +103,7 @@ func TestLaunchInteractiveSession_NonInteractiveProvider(t *testing.T) {
 	ctx := context.Background()
 	cfg := &config.ProviderConfig{Provider: "basic", Model: ""}
 
-	err := LaunchClaudeCode(ctx, wrapper, streams, "test.md", "main.go", 1, 1, cfg)
+	err := LaunchClaudeCode(ctx, wrapper, streams, "test.md", "main.go", 1, 1, cfg, nil)
 	if err == nil {
 		t.Error("expected error for non-interactive provider")
 	}
@@ -140,7 +140,7 @@ func TestLaunchInteractiveSession_NonInteractiveStreams(t *testing.T) {
 	ctx := context.Background()
 	cfg := &config.ProviderConfig{Provider: "mock", Model: ""}
 
-	err := LaunchClaudeCode(ctx, mock, streams, "test.md", "main.go", 1, 1, cfg)
+	err := LaunchClaudeCode(ctx, mock, streams, "test.md", "main.go", 1, 1, cfg, nil)
 	if err == nil {
 		t.Error("expected error for non-interactive streams")
 	}
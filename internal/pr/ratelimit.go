@@ -0,0 +1,86 @@
+package pr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/go-github/github"
+)
+
+// DefaultRateLimitMaxWait is how long GitHub calls wait for a rate limit to reset before giving up
+const DefaultRateLimitMaxWait = time.Minute
+
+// rateLimitRetries bounds how many times a rate-limited call is retried after waiting
+const rateLimitRetries = 2
+
+// rateLimitSleep pauses for d or until ctx is done. Replaced in tests.
+var rateLimitSleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// doWithRateLimit runs a GitHub API call. When GitHub reports a rate limit that resets within
+// maxWait, it waits and retries the call. Otherwise it returns an error stating when the limit resets.
+// A maxWait of zero never waits.
+func doWithRateLimit[T any](ctx context.Context, maxWait time.Duration, call func() (T, *github.Response, error)) (T, error) {
+	for attempt := 0; ; attempt++ {
+		result, _, err := call()
+		if !isRateLimit(err) {
+			return result, err
+		}
+
+		wait, known := rateLimitWait(err)
+		if !known || wait > maxWait || attempt >= rateLimitRetries {
+			return result, rateLimitError(err, wait, known)
+		}
+
+		slog.Warn("GitHub rate limit reached, waiting for reset", "wait", wait.Round(time.Second))
+		if err := rateLimitSleep(ctx, wait); err != nil {
+			return result, err
+		}
+	}
+}
+
+// isRateLimit reports whether err is a primary or secondary GitHub rate limit
+func isRateLimit(err error) bool {
+	var rateErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	return errors.As(err, &rateErr) || errors.As(err, &abuseErr)
+}
+
+// rateLimitWait returns how long to wait before retrying a rate-limited call.
+// known is false when err is not a rate limit or GitHub did not say when it resets.
+func rateLimitWait(err error) (wait time.Duration, known bool) {
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		if rateErr.Rate.Reset.Time.IsZero() {
+			return 0, false
+		}
+		return max(time.Until(rateErr.Rate.Reset.Time), 0), true
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) && abuseErr.RetryAfter != nil {
+		return *abuseErr.RetryAfter, true
+	}
+	return 0, false
+}
+
+// rateLimitError describes a rate limit that was not waited out
+func rateLimitError(err error, wait time.Duration, known bool) error {
+	if !known {
+		return fmt.Errorf("GitHub API rate limit exceeded; try again later or set GITHUB_TOKEN for a higher limit: %w", err)
+	}
+	reset := time.Now().Add(wait)
+	return fmt.Errorf("GitHub API rate limit exceeded; resets at %s (in %s). Set GITHUB_TOKEN for a higher limit: %w",
+		reset.Format("15:04:05"), wait.Round(time.Second), err)
+}
@@ -0,0 +1,172 @@
+package pr
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/github"
+)
+
+// scriptedTransport returns its responses in order, one per request
+type scriptedTransport struct {
+	responses []func(req *http.Request) *http.Response
+	calls     int
+}
+
+func (s *scriptedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	respond := s.responses[min(s.calls, len(s.responses)-1)]
+	s.calls++
+	return respond(req), nil
+}
+
+func jsonResponse(status int, header http.Header, body string) func(*http.Request) *http.Response {
+	return func(req *http.Request) *http.Response {
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			StatusCode: status,
+			Header:     header,
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}
+	}
+}
+
+// primaryRateLimit is a primary rate limit response resetting at reset
+func primaryRateLimit(reset time.Time) func(*http.Request) *http.Response {
+	header := http.Header{}
+	header.Set("X-RateLimit-Limit", "60")
+	header.Set("X-RateLimit-Remaining", "0")
+	header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	return jsonResponse(http.StatusForbidden, header, `{"message": "API rate limit exceeded for 127.0.0.1."}`)
+}
+
+// secondaryRateLimit is a secondary (abuse) rate limit response with an optional Retry-After
+func secondaryRateLimit(retryAfter string) func(*http.Request) *http.Response {
+	header := http.Header{}
+	if retryAfter != "" {
+		header.Set("Retry-After", retryAfter)
+	}
+	return jsonResponse(http.StatusForbidden, header,
+		`{"message": "You have triggered an abuse detection mechanism.", "documentation_url": "https://developer.github.com/v3/#abuse-rate-limits"}`)
+}
+
+func TestDoWithRateLimit(t *testing.T) {
+	success := jsonResponse(http.StatusOK, nil, `[{"id": 1}, {"id": 2}]`)
+
+	tests := []struct {
+		name       string
+		responses  []func(*http.Request) *http.Response
+		maxWait    time.Duration
+		wantErr    string
+		wantCalls  int
+		wantSleeps []time.Duration
+	}{
+		{
+			name:      "success passes through",
+			responses: []func(*http.Request) *http.Response{success},
+			maxWait:   time.Minute,
+			wantCalls: 1,
+		},
+		{
+			name:       "primary limit already reset retries",
+			responses:  []func(*http.Request) *http.Response{primaryRateLimit(time.Now().Add(-time.Second)), success},
+			maxWait:    time.Minute,
+			wantCalls:  2,
+			wantSleeps: []time.Duration{0},
+		},
+		{
+			name:       "secondary limit within max wait retries",
+			responses:  []func(*http.Request) *http.Response{secondaryRateLimit("30"), success},
+			maxWait:    time.Minute,
+			wantCalls:  2,
+			wantSleeps: []time.Duration{30 * time.Second},
+		},
+		{
+			name:      "primary limit beyond max wait reports reset",
+			responses: []func(*http.Request) *http.Response{primaryRateLimit(time.Now().Add(time.Hour)), success},
+			maxWait:   time.Minute,
+			wantErr:   "GitHub API rate limit exceeded; resets at",
+			wantCalls: 1,
+		},
+		{
+			name:      "zero max wait fails immediately",
+			responses: []func(*http.Request) *http.Response{secondaryRateLimit("30"), success},
+			wantErr:   "resets at",
+			wantCalls: 1,
+		},
+		{
+			name:      "secondary limit without retry-after",
+			responses: []func(*http.Request) *http.Response{secondaryRateLimit(""), success},
+			maxWait:   time.Minute,
+			wantErr:   "try again later",
+			wantCalls: 1,
+		},
+		{
+			name:       "gives up after repeated limits",
+			responses:  []func(*http.Request) *http.Response{secondaryRateLimit("1")},
+			maxWait:    time.Minute,
+			wantErr:    "rate limit exceeded",
+			wantCalls:  rateLimitRetries + 1,
+			wantSleeps: []time.Duration{time.Second, time.Second},
+		},
+		{
+			name:      "other errors are returned unchanged",
+			responses: []func(*http.Request) *http.Response{jsonResponse(http.StatusNotFound, nil, `{"message": "Not Found"}`)},
+			maxWait:   time.Minute,
+			wantErr:   "404 Not Found",
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sleeps []time.Duration
+			origSleep := rateLimitSleep
+			rateLimitSleep = func(ctx context.Context, d time.Duration) error {
+				sleeps = append(sleeps, d)
+				return nil
+			}
+			t.Cleanup(func() { rateLimitSleep = origSleep })
+
+			transport := &scriptedTransport{responses: tt.responses}
+			client := github.NewClient(&http.Client{Transport: transport})
+			ctx := context.Background()
+
+			files, err := doWithRateLimit(ctx, tt.maxWait, func() ([]*github.CommitFile, *github.Response, error) {
+				return client.PullRequests.ListFiles(ctx, "o", "r", 1, &github.ListOptions{})
+			})
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(files) != 2 {
+					t.Errorf("got %d files, want 2", len(files))
+				}
+			}
+
+			if transport.calls != tt.wantCalls {
+				t.Errorf("transport calls = %d, want %d", transport.calls, tt.wantCalls)
+			}
+			if len(sleeps) != len(tt.wantSleeps) {
+				t.Fatalf("sleeps = %v, want %v", sleeps, tt.wantSleeps)
+			}
+			for i := range sleeps {
+				if sleeps[i] != tt.wantSleeps[i] {
+					t.Errorf("sleeps = %v, want %v", sleeps, tt.wantSleeps)
+				}
+			}
+		})
+	}
+}
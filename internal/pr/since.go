@@ -0,0 +1,36 @@
+package pr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseSince parses a --since value relative to now. It accepts an RFC3339 timestamp,
+// a date (YYYY-MM-DD, midnight UTC), or a duration ago such as "24h", "90m", or "7d".
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+
+	// time.ParseDuration has no day unit, so handle a plain "<n>d" separately
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid --since %q: use an RFC3339 time (2024-06-01T15:04:05Z), a date (2024-06-01), or a duration such as 24h or 7d", value)
+}
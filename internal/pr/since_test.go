@@ -0,0 +1,120 @@
+package pr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 6, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "", want: time.Time{}},
+		{value: "2024-06-01T15:04:05Z", want: time.Date(2024, 6, 1, 15, 4, 5, 0, time.UTC)},
+		{value: "2024-06-01", want: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "24h", want: now.Add(-24 * time.Hour)},
+		{value: "90m", want: now.Add(-90 * time.Minute)},
+		{value: "7d", want: now.AddDate(0, 0, -7)},
+		{value: "yesterday", wantErr: true},
+		{value: "-1h", wantErr: true},
+		{value: "2024-13-01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseSince(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSince(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("ParseSince(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchReviews_Since(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/", fakePRHandler())
+	mux.HandleFunc("/repos/o/r/pulls/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"id": 1, "path": "main.go", "position": 1, "body": "Old comment.", "created_at": "2024-05-01T00:00:00Z", "updated_at": "2024-05-01T00:00:00Z", "user": {"login": "gemini-code-assist[bot]"}},
+			{"id": 2, "path": "main.go", "position": 2, "body": "Edited after cutoff.", "created_at": "2024-05-01T00:00:00Z", "updated_at": "2024-06-02T00:00:00Z", "user": {"login": "gemini-code-assist[bot]"}},
+			{"id": 3, "path": "main.go", "position": 3, "body": "New comment.", "created_at": "2024-06-03T00:00:00Z", "user": {"login": "gemini-code-assist[bot]"}},
+			{"id": 4, "path": "main.go", "position": 4, "body": "Old thread, new reply.", "created_at": "2024-05-01T00:00:00Z", "user": {"login": "gemini-code-assist[bot]"}},
+			{"id": 5, "in_reply_to_id": 4, "path": "main.go", "position": 4, "body": "Reply.", "created_at": "2024-06-04T00:00:00Z", "user": {"login": "octocat"}}
+		]`)
+	})
+	mux.HandleFunc("/repos/o/r/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"id": 10, "body": "Old general note.", "created_at": "2024-05-15T00:00:00Z", "user": {"login": "gemini-code-assist[bot]"}},
+			{"id": 11, "body": "New general note.", "created_at": "2024-06-05T00:00:00Z", "user": {"login": "gemini-code-assist[bot]"}}
+		]`)
+	})
+	client := newTestGitHubClient(t, mux)
+
+	tests := []struct {
+		name       string
+		since      time.Time
+		wantBodies []string
+		wantNone   bool
+	}{
+		{
+			name:       "keeps comments after cutoff",
+			since:      time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			wantBodies: []string{"Edited after cutoff.", "New comment.", "Old thread, new reply.", "New general note."},
+		},
+		{
+			name:       "zero since keeps everything",
+			wantBodies: []string{"Old comment.", "Edited after cutoff.", "New comment.", "Old thread, new reply.", "Old general note.", "New general note."},
+		},
+		{
+			name:     "nothing newer",
+			since:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantNone: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var progress bytes.Buffer
+			outputDir := t.TempDir()
+			opts := FetchOptions{Format: FormatJSON, Since: tt.since, Progress: &progress}
+			if err := FetchReviews(context.Background(), client, "o", "r", 1, outputDir, opts); err != nil {
+				t.Fatalf("FetchReviews() error = %v", err)
+			}
+
+			if tt.wantNone {
+				if !strings.Contains(progress.String(), "No new gemini-code-assist feedback since 2025-01-01T00:00:00Z") {
+					t.Errorf("progress = %q, want no new feedback message", progress.String())
+				}
+				return
+			}
+
+			data, err := os.ReadFile(filepath.Join(outputDir, FeedbackFileJSON))
+			if err != nil {
+				t.Fatal(err)
+			}
+			report := string(data)
+			if got := strings.Count(report, `"type"`); got != len(tt.wantBodies) {
+				t.Errorf("got %d items, want %d:\n%s", got, len(tt.wantBodies), report)
+			}
+			for _, body := range tt.wantBodies {
+				if !strings.Contains(report, body) {
+					t.Errorf("report missing %q", body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package pr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/github"
+)
+
+// DecisionsFile is the name of the file in a review directory that records per-item decisions
+const DecisionsFile = "decisions.json"
+
+// Decision statuses reported by the review agent
+const (
+	StatusApplied  = "APPLIED"
+	StatusRejected = "REJECTED"
+	StatusFailed   = "FAILED"
+	StatusSkipped  = "SKIPPED"
+)
+
+// Decision records the outcome of processing a single feedback item
+type Decision struct {
+	// FeedbackFile is the prompt file the decision was made for
+	FeedbackFile string `json:"feedback_file"`
+	Status       string `json:"status"`
+	File         string `json:"file,omitempty"`
+	Action       string `json:"action,omitempty"`
+	Reasoning    string `json:"reasoning,omitempty"`
+}
+
+// DefaultReviewDir returns the directory pr review writes feedback for prNumber into
+func DefaultReviewDir(prNumber int) string {
+	return fmt.Sprintf("./pr_review_pr%d", prNumber)
+}
+
+// LoadDecisions reads the decisions recorded in dir
+func LoadDecisions(dir string) ([]Decision, error) {
+	decisions, err := readDecisions(dir)
+	if err != nil {
+		return nil, err
+	}
+	if decisions == nil {
+		return nil, fmt.Errorf("no decisions recorded in %s (expected %s, written by pr apply --batch and pr review)", dir, DecisionsFile)
+	}
+	if len(decisions) == 0 {
+		return nil, fmt.Errorf("%s contains no decisions", filepath.Join(dir, DecisionsFile))
+	}
+
+	return decisions, nil
+}
+
+// RecordDecision adds d to the decisions recorded in dir, replacing any earlier decision for the
+// same feedback file. dir is created if it does not exist.
+func RecordDecision(dir string, d Decision) error {
+	decisions, err := readDecisions(dir)
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i := range decisions {
+		if decisions[i].FeedbackFile == d.FeedbackFile {
+			decisions[i] = d
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		decisions = append(decisions, d)
+	}
+	return writeDecisions(dir, decisions)
+}
+
+// forgetDecision removes the decision recorded in dir for feedbackFile when it has status.
+// Nothing is written when there is no such decision.
+func forgetDecision(dir, feedbackFile, status string) error {
+	decisions, err := readDecisions(dir)
+	if err != nil {
+		return err
+	}
+
+	kept := decisions[:0]
+	for _, d := range decisions {
+		if d.FeedbackFile == feedbackFile && strings.EqualFold(d.Status, status) {
+			continue
+		}
+		kept = append(kept, d)
+	}
+	if len(kept) == len(decisions) {
+		return nil
+	}
+	return writeDecisions(dir, kept)
+}
+
+// writeDecisions replaces decisions.json in dir, creating dir if it does not exist
+func writeDecisions(dir string, decisions []Decision) error {
+	data, err := json.MarshalIndent(decisions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode decisions: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, DecisionsFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write decisions: %w", err)
+	}
+	return nil
+}
+
+// readDecisions reads decisions.json in dir, returning nil without an error when it does not exist
+func readDecisions(dir string) ([]Decision, error) {
+	path := filepath.Join(dir, DecisionsFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read decisions: %w", err)
+	}
+
+	decisions := []Decision{}
+	if err := json.Unmarshal(data, &decisions); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return decisions, nil
+}
+
+// FormatSummaryComment renders decisions as a markdown comment for the pull request
+func FormatSummaryComment(decisions []Decision) string {
+	counts := make(map[string]int)
+	for _, d := range decisions {
+		counts[strings.ToUpper(d.Status)]++
+	}
+
+	var b strings.Builder
+	b.WriteString("## Review feedback summary\n\n")
+	fmt.Fprintf(&b, "Processed %d feedback items", len(decisions))
+
+	var parts []string
+	for _, status := range []string{StatusApplied, StatusRejected, StatusSkipped, StatusFailed} {
+		if counts[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[status], strings.ToLower(status)))
+		}
+	}
+	if len(parts) > 0 {
+		fmt.Fprintf(&b, ": %s", strings.Join(parts, ", "))
+	}
+	b.WriteString(".\n\n")
+
+	for i, d := range decisions {
+		target := d.File
+		if target == "" {
+			target = "general comment"
+		}
+		fmt.Fprintf(&b, "%d. **%s** `%s`", i+1, strings.ToUpper(d.Status), target)
+		if d.Action != "" {
+			fmt.Fprintf(&b, " — %s", d.Action)
+		}
+		b.WriteString("\n")
+		if d.Reasoning != "" {
+			fmt.Fprintf(&b, "   - Reasoning: %s\n", strings.Join(strings.Fields(d.Reasoning), " "))
+		}
+	}
+
+	return b.String()
+}
+
+// commentCreator is the subset of the GitHub issues API used to post comments
+type commentCreator interface {
+	CreateComment(ctx context.Context, owner, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+}
+
+// PostSummaryComment posts body as a comment on the pull request and returns the comment URL
+func PostSummaryComment(ctx context.Context, issues commentCreator, owner, repo string, prNumber int, body string) (string, error) {
+	comment, _, err := issues.CreateComment(ctx, owner, repo, prNumber, &github.IssueComment{Body: github.String(body)})
+	if err != nil {
+		return "", fmt.Errorf("failed to post summary comment: %w", err)
+	}
+	return comment.GetHTMLURL(), nil
+}
@@ -0,0 +1,121 @@
+package pr
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/github"
+)
+
+type mockCommentCreator struct {
+	owner, repo string
+	number      int
+	body        string
+	err         error
+}
+
+func (m *mockCommentCreator) CreateComment(ctx context.Context, owner, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	if m.err != nil {
+		return nil, nil, m.err
+	}
+	m.owner, m.repo, m.number, m.body = owner, repo, number, comment.GetBody()
+	return &github.IssueComment{HTMLURL: github.String("https://github.com/o/r/pull/7#issuecomment-1")}, nil, nil
+}
+
+var sampleDecisions = []Decision{
+	{FeedbackFile: "1_main_go_line10.md", Status: StatusApplied, File: "main.go", Action: "Added error check.", Reasoning: "The error was ignored."},
+	{FeedbackFile: "2_util_go_line5.md", Status: StatusRejected, File: "util.go", Reasoning: "Style nit\nwith no linter violation."},
+	{FeedbackFile: "3_general_comment.md", Status: "skipped"},
+}
+
+func TestFormatSummaryComment(t *testing.T) {
+	got := FormatSummaryComment(sampleDecisions)
+
+	want := []string{
+		"Processed 3 feedback items: 1 applied, 1 rejected, 1 skipped.",
+		"1. **APPLIED** `main.go` — Added error check.\n   - Reasoning: The error was ignored.",
+		"2. **REJECTED** `util.go`\n   - Reasoning: Style nit with no linter violation.",
+		"3. **SKIPPED** `general comment`\n",
+	}
+	for _, s := range want {
+		if !strings.Contains(got, s) {
+			t.Errorf("comment missing %q\n%s", s, got)
+		}
+	}
+}
+
+func TestPostSummaryComment(t *testing.T) {
+	mock := &mockCommentCreator{}
+	body := FormatSummaryComment(sampleDecisions)
+
+	url, err := PostSummaryComment(context.Background(), mock, "o", "r", 7, body)
+	if err != nil {
+		t.Fatalf("PostSummaryComment() error = %v", err)
+	}
+	if url != "https://github.com/o/r/pull/7#issuecomment-1" {
+		t.Errorf("url = %q", url)
+	}
+	if mock.owner != "o" || mock.repo != "r" || mock.number != 7 {
+		t.Errorf("posted to %s/%s#%d, want o/r#7", mock.owner, mock.repo, mock.number)
+	}
+	if mock.body != body {
+		t.Errorf("posted body = %q, want %q", mock.body, body)
+	}
+
+	failing := &mockCommentCreator{err: errors.New("403 Resource not accessible by integration")}
+	if _, err := PostSummaryComment(context.Background(), failing, "o", "r", 7, body); err == nil {
+		t.Error("expected error when the API call fails")
+	}
+}
+
+func TestLoadDecisions(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := LoadDecisions(dir); err == nil {
+		t.Error("expected error when decisions.json is missing")
+	}
+
+	content := `[{"feedback_file": "1_main_go_line10.md", "status": "APPLIED", "file": "main.go"}]`
+	if err := os.WriteFile(filepath.Join(dir, DecisionsFile), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	decisions, err := LoadDecisions(dir)
+	if err != nil {
+		t.Fatalf("LoadDecisions() error = %v", err)
+	}
+	if len(decisions) != 1 || decisions[0].Status != StatusApplied || decisions[0].File != "main.go" {
+		t.Errorf("LoadDecisions() = %+v", decisions)
+	}
+}
+
+func TestRecordDecision(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "review")
+
+	steps := []Decision{
+		{FeedbackFile: "1_main_go_line10.md", Status: StatusFailed},
+		{FeedbackFile: "2_general_comment.md", Status: StatusRejected},
+		{FeedbackFile: "1_main_go_line10.md", Status: StatusApplied},
+	}
+	for _, d := range steps {
+		if err := RecordDecision(dir, d); err != nil {
+			t.Fatalf("RecordDecision(%+v) error = %v", d, err)
+		}
+	}
+
+	decisions, err := LoadDecisions(dir)
+	if err != nil {
+		t.Fatalf("LoadDecisions() error = %v", err)
+	}
+	want := []Decision{
+		{FeedbackFile: "1_main_go_line10.md", Status: StatusApplied},
+		{FeedbackFile: "2_general_comment.md", Status: StatusRejected},
+	}
+	if len(decisions) != len(want) || decisions[0] != want[0] || decisions[1] != want[1] {
+		t.Errorf("decisions = %+v, want %+v", decisions, want)
+	}
+}
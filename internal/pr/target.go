@@ -0,0 +1,82 @@
+package pr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/github"
+)
+
+// ErrGitHubAuth is wrapped by errors for requests GitHub rejected as unauthenticated or forbidden
+var ErrGitHubAuth = errors.New("GitHub authentication failed")
+
+// NotFoundError reports a pull request that does not exist, or that the token cannot see
+type NotFoundError struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("PR #%d not found in %s/%s", e.Number, e.Owner, e.Repo)
+}
+
+// ExpandRepo prefixes a bare repository name with defaultOwner, so "myrepo" becomes
+// "defaultOwner/myrepo". Names that already contain an owner are returned unchanged.
+func ExpandRepo(repo, defaultOwner string) (string, error) {
+	if strings.Contains(repo, "/") {
+		return repo, nil
+	}
+	if defaultOwner == "" {
+		return "", fmt.Errorf("repo %q has no owner: use 'owner/name' or set github.default_owner", repo)
+	}
+	return defaultOwner + "/" + repo, nil
+}
+
+// ParseTarget validates the "owner/name" and PR number arguments shared by the pr commands
+// without touching the network
+func ParseTarget(repo, number string) (owner, name string, prNumber int, err error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") || strings.ContainsAny(repo, " \t") {
+		return "", "", 0, fmt.Errorf("invalid repo format. Expected 'owner/name', got '%s'", repo)
+	}
+
+	prNumber, err = strconv.Atoi(number)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("invalid PR number %q: must be a positive integer", number)
+	}
+	if prNumber <= 0 {
+		return "", "", 0, fmt.Errorf("invalid PR number %d: must be a positive integer", prNumber)
+	}
+
+	return owner, name, prNumber, nil
+}
+
+// CheckTarget makes a single lightweight request confirming the pull request exists before
+// anything else is fetched. Missing pull requests return a *NotFoundError and rejected
+// credentials an error wrapping ErrGitHubAuth.
+func CheckTarget(ctx context.Context, prs pullRequestGetter, owner, name string, prNumber int, maxWait time.Duration) error {
+	_, err := doWithRateLimit(ctx, maxWait, func() (*github.PullRequest, *github.Response, error) {
+		return prs.Get(ctx, owner, name, prNumber)
+	})
+	if err == nil {
+		return nil
+	}
+
+	var respErr *github.ErrorResponse
+	if errors.As(err, &respErr) && respErr.Response != nil {
+		switch respErr.Response.StatusCode {
+		case http.StatusNotFound:
+			return &NotFoundError{Owner: owner, Repo: name, Number: prNumber}
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return fmt.Errorf("%w for %s/%s (check GITHUB_TOKEN, github.token_file, or 'gh auth status'): %v", ErrGitHubAuth, owner, name, respErr.Message)
+		}
+	}
+
+	return fmt.Errorf("failed to get PR #%d in %s/%s: %w", prNumber, owner, name, err)
+}
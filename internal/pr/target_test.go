@@ -0,0 +1,128 @@
+package pr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/github"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		name       string
+		repo       string
+		number     string
+		wantOwner  string
+		wantName   string
+		wantNumber int
+		wantErr    string
+	}{
+		{name: "valid", repo: "octocat/Hello-World", number: "123", wantOwner: "octocat", wantName: "Hello-World", wantNumber: 123},
+		{name: "missing slash", repo: "octocat", number: "1", wantErr: "invalid repo format"},
+		{name: "empty owner", repo: "/repo", number: "1", wantErr: "invalid repo format"},
+		{name: "empty name", repo: "owner/", number: "1", wantErr: "invalid repo format"},
+		{name: "extra segment", repo: "owner/repo/extra", number: "1", wantErr: "invalid repo format"},
+		{name: "whitespace", repo: "owner/my repo", number: "1", wantErr: "invalid repo format"},
+		{name: "non-numeric PR", repo: "owner/repo", number: "abc", wantErr: `invalid PR number "abc"`},
+		{name: "trailing garbage", repo: "owner/repo", number: "12abc", wantErr: `invalid PR number "12abc"`},
+		{name: "zero PR", repo: "owner/repo", number: "0", wantErr: "invalid PR number 0"},
+		{name: "negative PR", repo: "owner/repo", number: "-4", wantErr: "invalid PR number -4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner, name, number, err := ParseTarget(tt.repo, tt.number)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseTarget() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTarget() error = %v", err)
+			}
+			if owner != tt.wantOwner || name != tt.wantName || number != tt.wantNumber {
+				t.Errorf("ParseTarget() = %q, %q, %d, want %q, %q, %d", owner, name, number, tt.wantOwner, tt.wantName, tt.wantNumber)
+			}
+		})
+	}
+}
+
+func TestCheckTarget(t *testing.T) {
+	responseErr := func(status int) error {
+		return &github.ErrorResponse{Response: &http.Response{StatusCode: status}, Message: http.StatusText(status)}
+	}
+
+	tests := []struct {
+		name         string
+		err          error
+		wantNotFound bool
+		wantAuth     bool
+		wantErr      string
+	}{
+		{name: "exists"},
+		{name: "not found", err: responseErr(http.StatusNotFound), wantNotFound: true, wantErr: "PR #123 not found in owner/repo"},
+		{name: "unauthorized", err: responseErr(http.StatusUnauthorized), wantAuth: true, wantErr: "GitHub authentication failed for owner/repo"},
+		{name: "forbidden", err: responseErr(http.StatusForbidden), wantAuth: true, wantErr: "GitHub authentication failed"},
+		{name: "server error", err: responseErr(http.StatusBadGateway), wantErr: "failed to get PR #123 in owner/repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckTarget(context.Background(), stubPRGetter{headSHA: "abc", err: tt.err}, "owner", "repo", 123, 0)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CheckTarget() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("CheckTarget() error = %v, want %q", err, tt.wantErr)
+			}
+
+			var notFound *NotFoundError
+			if got := errors.As(err, &notFound); got != tt.wantNotFound {
+				t.Errorf("errors.As(NotFoundError) = %v, want %v", got, tt.wantNotFound)
+			}
+			if got := errors.Is(err, ErrGitHubAuth); got != tt.wantAuth {
+				t.Errorf("errors.Is(ErrGitHubAuth) = %v, want %v", got, tt.wantAuth)
+			}
+		})
+	}
+}
+
+func TestExpandRepo(t *testing.T) {
+	tests := []struct {
+		name         string
+		repo         string
+		defaultOwner string
+		want         string
+		wantErr      bool
+	}{
+		{name: "bare name with default owner", repo: "myrepo", defaultOwner: "myorg", want: "myorg/myrepo"},
+		{name: "full name ignores default owner", repo: "octocat/Hello-World", defaultOwner: "myorg", want: "octocat/Hello-World"},
+		{name: "full name without default owner", repo: "octocat/Hello-World", want: "octocat/Hello-World"},
+		{name: "bare name without default owner", repo: "myrepo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandRepo(tt.repo, tt.defaultOwner)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "github.default_owner") {
+					t.Fatalf("ExpandRepo() error = %v, want a hint about github.default_owner", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExpandRepo() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExpandRepo() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package pr
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// GitHubTokenEnvVar is the environment variable checked first for a GitHub token
+const GitHubTokenEnvVar = "GITHUB_TOKEN"
+
+// Sources a GitHub token can be resolved from
+const (
+	TokenSourceEnv   = "env"
+	TokenSourceFile  = "token_file"
+	TokenSourceGHCLI = "gh"
+)
+
+// ghTokenTimeout bounds how long `gh auth token` may take
+const ghTokenTimeout = 5 * time.Second
+
+// TokenResolver finds a GitHub token from the environment, a token file, or the gh CLI
+type TokenResolver struct {
+	Getenv   func(key string) string
+	ReadFile func(name string) ([]byte, error)
+	LookPath func(file string) (string, error)
+	// GHToken runs `gh auth token` using the gh binary at path
+	GHToken func(ctx context.Context, path string) (string, error)
+}
+
+// NewTokenResolver creates a TokenResolver using the real environment, filesystem, and gh CLI
+func NewTokenResolver() *TokenResolver {
+	return &TokenResolver{
+		Getenv:   os.Getenv,
+		ReadFile: os.ReadFile,
+		LookPath: exec.LookPath,
+		GHToken:  runGHAuthToken,
+	}
+}
+
+// Resolve returns a GitHub token and the source it came from, checking in order:
+// the GITHUB_TOKEN environment variable, tokenFile (when set), and `gh auth token`.
+// An empty token means none was found and the caller should fall back to anonymous access.
+// A tokenFile that is set but unreadable or empty is reported as an error.
+func (r *TokenResolver) Resolve(ctx context.Context, tokenFile string) (token, source string, err error) {
+	if token := strings.TrimSpace(r.Getenv(GitHubTokenEnvVar)); token != "" {
+		return token, TokenSourceEnv, nil
+	}
+
+	if tokenFile != "" {
+		data, err := r.ReadFile(expandHome(tokenFile, r.Getenv("HOME")))
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read github.token_file: %w", err)
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", "", fmt.Errorf("github.token_file %s is empty", tokenFile)
+		}
+		return token, TokenSourceFile, nil
+	}
+
+	path, err := r.LookPath("gh")
+	if err != nil {
+		return "", "", nil
+	}
+	// gh exits non-zero when not logged in, which just means there is no token to use
+	token, err = r.GHToken(ctx, path)
+	if err != nil || token == "" {
+		return "", "", nil
+	}
+	return token, TokenSourceGHCLI, nil
+}
+
+// runGHAuthToken returns the token printed by `gh auth token`
+func runGHAuthToken(ctx context.Context, path string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, ghTokenTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, path, "auth", "token").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// expandHome replaces a leading ~/ in path with home
+func expandHome(path, home string) string {
+	if home != "" && strings.HasPrefix(path, "~/") {
+		return filepath.Join(home, path[2:])
+	}
+	return path
+}
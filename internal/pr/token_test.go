@@ -0,0 +1,100 @@
+package pr
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTokenResolver_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		env        string
+		tokenFile  string
+		ghPath     bool
+		ghToken    string
+		ghErr      error
+		wantToken  string
+		wantSource string
+		wantErr    bool
+	}{
+		{
+			name: "env wins over everything", env: "env-token", tokenFile: tokenFile, ghPath: true, ghToken: "gh-token",
+			wantToken: "env-token", wantSource: TokenSourceEnv,
+		},
+		{
+			name: "token file before gh", tokenFile: tokenFile, ghPath: true, ghToken: "gh-token",
+			wantToken: "file-token", wantSource: TokenSourceFile,
+		},
+		{
+			name: "gh auth token as last resort", ghPath: true, ghToken: "gh-token",
+			wantToken: "gh-token", wantSource: TokenSourceGHCLI,
+		},
+		{
+			name: "gh not installed",
+		},
+		{
+			name: "gh not logged in", ghPath: true, ghErr: errors.New("exit status 1"),
+		},
+		{
+			name: "missing token file", tokenFile: filepath.Join(dir, "missing"), ghPath: true, ghToken: "gh-token",
+			wantErr: true,
+		},
+		{
+			name: "empty token file", tokenFile: emptyFile,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &TokenResolver{
+				Getenv: func(key string) string {
+					if key == GitHubTokenEnvVar {
+						return tt.env
+					}
+					return ""
+				},
+				ReadFile: os.ReadFile,
+				LookPath: func(file string) (string, error) {
+					if tt.ghPath {
+						return "/usr/bin/gh", nil
+					}
+					return "", errors.New("not found")
+				},
+				GHToken: func(ctx context.Context, path string) (string, error) {
+					return tt.ghToken, tt.ghErr
+				},
+			}
+
+			token, source, err := r.Resolve(context.Background(), tt.tokenFile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if token != tt.wantToken || source != tt.wantSource {
+				t.Errorf("Resolve() = (%q, %q), want (%q, %q)", token, source, tt.wantToken, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	if got := expandHome("~/tokens/gh", "/home/dev"); got != filepath.Join("/home/dev", "tokens/gh") {
+		t.Errorf("expandHome() = %q", got)
+	}
+	if got := expandHome("/etc/token", "/home/dev"); got != "/etc/token" {
+		t.Errorf("expandHome() = %q, want unchanged path", got)
+	}
+}
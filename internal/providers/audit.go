@@ -0,0 +1,232 @@
+package providers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/paths"
+)
+
+// AuditRecord is a single line of the audit log, written for every Generate call
+type AuditRecord struct {
+	Timestamp      time.Time `json:"timestamp"`
+	Command        string    `json:"command"`
+	Provider       string    `json:"provider"`
+	Model          string    `json:"model"`
+	PromptHash     string    `json:"prompt_hash"`
+	ResponseLength int       `json:"response_length"`
+	Error          string    `json:"error,omitempty"`
+	// Prompt and Response are only recorded when AuditLog.Full is set
+	Prompt   string `json:"prompt,omitempty"`
+	Response string `json:"response,omitempty"`
+}
+
+// AuditLog appends AuditRecords as JSON lines to a file
+type AuditLog struct {
+	// Path is the file records are appended to
+	Path string
+	// Full records the prompt and response text in addition to the hash and length
+	Full bool
+	// Command is the smix command the records are attributed to (e.g. "pr review")
+	Command string
+
+	now func() time.Time
+	mu  sync.Mutex
+}
+
+// NewAuditLog creates an audit log appending to path
+func NewAuditLog(path string, full bool, command string) *AuditLog {
+	return &AuditLog{Path: path, Full: full, Command: command, now: time.Now}
+}
+
+// Write appends rec to the log file, creating the file and its directory if needed
+func (a *AuditLog) Write(rec AuditRecord) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+
+	if err := paths.EnsureParent(a.Path); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(a.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// record builds and writes the record for one Generate call. Write failures are logged
+// rather than returned so a broken audit file does not discard a successful response.
+func (a *AuditLog) record(provider llm.Provider, prompt, response string, genErr error, opts []llm.Option) {
+	now := time.Now
+	if a.now != nil {
+		now = a.now
+	}
+
+	model := llm.BuildOptions(opts).Model
+	if model == "" {
+		model = provider.DefaultModel()
+	}
+
+	sum := sha256.Sum256([]byte(prompt))
+	rec := AuditRecord{
+		Timestamp:      now().UTC(),
+		Command:        a.Command,
+		Provider:       provider.Name(),
+		Model:          model,
+		PromptHash:     hex.EncodeToString(sum[:]),
+		ResponseLength: len(response),
+	}
+	if genErr != nil {
+		rec.Error = genErr.Error()
+	}
+	if a.Full {
+		rec.Prompt = prompt
+		rec.Response = response
+	}
+
+	if err := a.Write(rec); err != nil {
+		slog.Warn("audit log write failed", "path", a.Path, "error", err)
+	}
+}
+
+// auditProvider decorates a provider so every Generate call is recorded in an AuditLog
+type auditProvider struct {
+	llm.Provider
+	log *AuditLog
+}
+
+// Generate delegates to the wrapped provider and records the call
+func (p *auditProvider) Generate(ctx context.Context, prompt string, opts ...llm.Option) (string, error) {
+	response, err := p.Provider.Generate(ctx, prompt, opts...)
+	p.log.record(p.Provider, prompt, response, err, opts)
+	return response, err
+}
+
+// auditCandidates forwards llm.CandidateGenerator, recording one line per returned
+// candidate so the log matches what n sequential Generate calls would write
+type auditCandidates struct {
+	p         *auditProvider
+	generator llm.CandidateGenerator
+}
+
+// GenerateCandidates delegates to the wrapped provider and records the answers
+func (c auditCandidates) GenerateCandidates(ctx context.Context, prompt string, n int, opts ...llm.Option) ([]string, error) {
+	answers, err := c.generator.GenerateCandidates(ctx, prompt, n, opts...)
+	if err != nil {
+		c.p.log.record(c.p.Provider, prompt, "", err, opts)
+		return nil, err
+	}
+	for _, answer := range answers {
+		c.p.log.record(c.p.Provider, prompt, answer, nil, opts)
+	}
+	return answers, nil
+}
+
+// auditModels forwards llm.ModelLister, llm.ModelResolver and llm.ModelIDMatcher. Providers
+// implement them together; methods the wrapped provider lacks fall back to their no-op behavior.
+type auditModels struct {
+	provider llm.Provider
+}
+
+// ListModels delegates to the wrapped provider when it is a llm.ModelLister
+func (m auditModels) ListModels() []string {
+	if lister, ok := m.provider.(llm.ModelLister); ok {
+		return lister.ListModels()
+	}
+	return nil
+}
+
+// ResolveModel delegates to the wrapped provider when it is a llm.ModelResolver
+func (m auditModels) ResolveModel(model string) string {
+	if resolver, ok := m.provider.(llm.ModelResolver); ok {
+		return resolver.ResolveModel(model)
+	}
+	return model
+}
+
+// MatchesModelID delegates to the wrapped provider when it is a llm.ModelIDMatcher
+func (m auditModels) MatchesModelID(model string) bool {
+	if matcher, ok := m.provider.(llm.ModelIDMatcher); ok {
+		return matcher.MatchesModelID(model)
+	}
+	return false
+}
+
+// WithAudit wraps provider so every Generate and GenerateCandidates call is appended to log.
+// The returned provider implements exactly the optional interfaces (llm.InteractiveProvider,
+// llm.CandidateGenerator, llm.ModelLister/llm.ModelResolver/llm.ModelIDMatcher) that provider implements.
+// Interactive sessions are not recorded since their prompts and responses never pass through smix.
+func WithAudit(provider llm.Provider, log *AuditLog) llm.Provider {
+	wrapped := &auditProvider{Provider: provider, log: log}
+
+	interactive, isInteractive := provider.(llm.InteractiveProvider)
+	generator, isGenerator := provider.(llm.CandidateGenerator)
+	_, isLister := provider.(llm.ModelLister)
+	_, isResolver := provider.(llm.ModelResolver)
+	_, isMatcher := provider.(llm.ModelIDMatcher)
+	candidates := auditCandidates{p: wrapped, generator: generator}
+	models := auditModels{provider: provider}
+
+	switch hasModels := isLister || isResolver || isMatcher; {
+	case isInteractive && isGenerator && hasModels:
+		return struct {
+			*auditProvider
+			llm.InteractiveProvider
+			auditCandidates
+			auditModels
+		}{wrapped, interactive, candidates, models}
+	case isInteractive && isGenerator:
+		return struct {
+			*auditProvider
+			llm.InteractiveProvider
+			auditCandidates
+		}{wrapped, interactive, candidates}
+	case isInteractive && hasModels:
+		return struct {
+			*auditProvider
+			llm.InteractiveProvider
+			auditModels
+		}{wrapped, interactive, models}
+	case isInteractive:
+		return struct {
+			*auditProvider
+			llm.InteractiveProvider
+		}{wrapped, interactive}
+	case isGenerator && hasModels:
+		return struct {
+			*auditProvider
+			auditCandidates
+			auditModels
+		}{wrapped, candidates, models}
+	case isGenerator:
+		return struct {
+			*auditProvider
+			auditCandidates
+		}{wrapped, candidates}
+	case hasModels:
+		return struct {
+			*auditProvider
+			auditModels
+		}{wrapped, models}
+	default:
+		return wrapped
+	}
+}
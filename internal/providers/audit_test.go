@@ -0,0 +1,211 @@
+package providers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/llm/llmtest"
+)
+
+// readAuditRecords parses every JSON line in path
+func readAuditRecords(t *testing.T, path string) []AuditRecord {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	defer f.Close()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("invalid audit line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestWithAudit(t *testing.T) {
+	fixed := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	const helloHash = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+	tests := []struct {
+		name string
+		full bool
+		err  error
+		opts []llm.Option
+		want AuditRecord
+	}{
+		{
+			name: "hashes prompt by default",
+			want: AuditRecord{Command: "ask", Provider: "fake", Model: "fake-model", PromptHash: helloHash, ResponseLength: 5},
+		},
+		{
+			name: "records model override",
+			opts: []llm.Option{llm.WithModel("other")},
+			want: AuditRecord{Command: "ask", Provider: "fake", Model: "other", PromptHash: helloHash, ResponseLength: 5},
+		},
+		{
+			name: "full records text",
+			full: true,
+			want: AuditRecord{Command: "ask", Provider: "fake", Model: "fake-model", PromptHash: helloHash, ResponseLength: 5, Prompt: "hello", Response: "world"},
+		},
+		{
+			name: "records errors",
+			err:  errors.New("boom"),
+			want: AuditRecord{Command: "ask", Provider: "fake", Model: "fake-model", PromptHash: helloHash, Error: "boom"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "logs", "audit.jsonl")
+			log := NewAuditLog(path, tt.full, "ask")
+			log.now = func() time.Time { return fixed }
+
+			fake := &llmtest.FakeProvider{Responses: []string{"world"}, Err: tt.err}
+			provider := WithAudit(fake, log)
+
+			got, err := provider.Generate(context.Background(), "hello", tt.opts...)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Generate() error = %v, want %v", err, tt.err)
+			}
+			if tt.err == nil && got != "world" {
+				t.Errorf("Generate() = %q, want %q", got, "world")
+			}
+
+			records := readAuditRecords(t, path)
+			if len(records) != 1 {
+				t.Fatalf("got %d records, want 1", len(records))
+			}
+			tt.want.Timestamp = fixed
+			if records[0] != tt.want {
+				t.Errorf("record = %+v, want %+v", records[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestWithAudit_Appends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	provider := WithAudit(&llmtest.FakeProvider{Responses: []string{"a", "bb"}}, NewAuditLog(path, false, "do"))
+
+	for _, prompt := range []string{"one", "two"} {
+		if _, err := provider.Generate(context.Background(), prompt); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	}
+
+	records := readAuditRecords(t, path)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if records[0].ResponseLength != 1 || records[1].ResponseLength != 2 {
+		t.Errorf("response lengths = %d, %d, want 1, 2", records[0].ResponseLength, records[1].ResponseLength)
+	}
+	if records[0].PromptHash == records[1].PromptHash {
+		t.Error("different prompts produced the same hash")
+	}
+}
+
+// fullProvider implements every optional provider interface
+type fullProvider struct {
+	llmtest.FakeProvider
+}
+
+func (f *fullProvider) GenerateCandidates(ctx context.Context, prompt string, n int, opts ...llm.Option) ([]string, error) {
+	return llm.GenerateSequential(ctx, &f.FakeProvider, prompt, n, opts...)
+}
+
+func (f *fullProvider) ListModels() []string { return []string{"fake-model"} }
+
+func (f *fullProvider) ResolveModel(model string) string { return model }
+
+func TestWithAudit_PreservesCapabilities(t *testing.T) {
+	log := NewAuditLog(filepath.Join(t.TempDir(), "audit.jsonl"), false, "pr review")
+	fake := &llmtest.FakeProvider{}
+
+	tests := []struct {
+		name     string
+		provider llm.Provider
+	}{
+		{name: "basic", provider: fake.Basic()},
+		{name: "interactive", provider: fake},
+		{name: "all optional interfaces", provider: &fullProvider{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := WithAudit(tt.provider, log)
+			if got, want := llm.CapabilitiesOf(wrapped), llm.CapabilitiesOf(tt.provider); got != want {
+				t.Errorf("CapabilitiesOf(WithAudit()) = %+v, want %+v", got, want)
+			}
+		})
+	}
+
+	wrapped := WithAudit(&fullProvider{}, log)
+	if _, ok := wrapped.(llm.CandidateGenerator); !ok {
+		t.Error("audited provider lost llm.CandidateGenerator")
+	}
+	if lister, ok := wrapped.(llm.ModelLister); !ok || len(lister.ListModels()) != 1 {
+		t.Error("audited provider did not forward llm.ModelLister")
+	}
+	if _, ok := wrapped.(llm.ModelResolver); !ok {
+		t.Error("audited provider lost llm.ModelResolver")
+	}
+	if _, ok := wrapped.(llm.InteractiveProvider); !ok {
+		t.Error("audited provider lost llm.InteractiveProvider")
+	}
+}
+
+func TestWithAudit_RecordsGenerateN(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider func(responses []string) llm.Provider
+	}{
+		{
+			name: "native candidates",
+			provider: func(responses []string) llm.Provider {
+				return &fullProvider{FakeProvider: llmtest.FakeProvider{Responses: responses}}
+			},
+		},
+		{
+			name: "sequential",
+			provider: func(responses []string) llm.Provider {
+				return (&llmtest.FakeProvider{Responses: responses}).Basic()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.jsonl")
+			provider := WithAudit(tt.provider([]string{"a", "bb", "ccc"}), NewAuditLog(path, false, "ask"))
+
+			if _, err := llm.GenerateN(context.Background(), provider, "hello", 3); err != nil {
+				t.Fatalf("GenerateN() error = %v", err)
+			}
+
+			records := readAuditRecords(t, path)
+			if len(records) != 3 {
+				t.Fatalf("got %d records, want 3", len(records))
+			}
+			for i, rec := range records {
+				if rec.ResponseLength != i+1 {
+					t.Errorf("record %d response length = %d, want %d", i, rec.ResponseLength, i+1)
+				}
+			}
+		})
+	}
+}
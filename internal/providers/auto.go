@@ -0,0 +1,66 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
+)
+
+// AutoProvider is the provider name that selects the first available provider in
+// config.AutoOrder (or Names when no order is configured)
+const AutoProvider = "auto"
+
+// getAuto returns the provider AutoProvider resolves to. The resolution is made once per
+// factory, so every command in the process uses the same provider.
+func (f *Factory) getAuto(ctx context.Context) (llm.Provider, error) {
+	f.mu.RLock()
+	resolved := f.auto
+	f.mu.RUnlock()
+	if resolved != "" {
+		return f.GetProvider(ctx, resolved)
+	}
+
+	order := config.AutoOrder()
+	if len(order) == 0 {
+		order = Names()
+	}
+
+	name, provider, err := resolveAuto(ctx, f.GetProvider, order)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	f.auto = name
+	f.mu.Unlock()
+	return provider, nil
+}
+
+// resolveAuto returns the first provider in order that get can construct. Providers that fail
+// for any reason (missing CLI or API key, unknown name) are skipped; a provider is only
+// constructed, no request is sent.
+func resolveAuto(ctx context.Context, get getterFunc, order []string) (string, llm.Provider, error) {
+	var errs []error
+	for _, name := range order {
+		if name == AutoProvider {
+			continue
+		}
+		provider, err := get(ctx, name)
+		if err != nil {
+			slog.Debug("auto provider candidate unavailable", "provider", name, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		slog.Debug("resolved auto provider", "provider", name)
+		return name, provider, nil
+	}
+
+	if len(errs) == 0 {
+		errs = append(errs, errors.New("no providers to try"))
+	}
+	return "", nil, llm.ErrProviderNotAvailable(AutoProvider, errors.Join(errs...))
+}
@@ -0,0 +1,98 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/llm/gemini"
+	"github.com/spf13/viper"
+)
+
+func TestResolveAuto(t *testing.T) {
+	tests := []struct {
+		name         string
+		order        []string
+		errs         map[string]error
+		wantProvider string
+		wantCalls    []string
+		wantErr      bool
+	}{
+		{
+			name:         "first available",
+			order:        []string{"claude", "gemini"},
+			wantProvider: "claude",
+			wantCalls:    []string{"claude"},
+		},
+		{
+			name:         "first unavailable, second chosen",
+			order:        []string{"claude", "gemini"},
+			errs:         map[string]error{"claude": llm.ErrProviderNotAvailable("claude", errors.New("no cli"))},
+			wantProvider: "gemini",
+			wantCalls:    []string{"claude", "gemini"},
+		},
+		{
+			name:      "auto in order is skipped",
+			order:     []string{"auto"},
+			wantErr:   true,
+			wantCalls: nil,
+		},
+		{
+			name:  "none available",
+			order: []string{"claude", "gemini"},
+			errs: map[string]error{
+				"claude": llm.ErrProviderNotAvailable("claude", errors.New("no cli")),
+				"gemini": llm.ErrProviderNotAvailable("gemini", errors.New("no key")),
+			},
+			wantErr:   true,
+			wantCalls: []string{"claude", "gemini"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			name, provider, err := resolveAuto(context.Background(), stubGetter(tt.errs, &calls), tt.order)
+			if strings.Join(calls, ",") != strings.Join(tt.wantCalls, ",") {
+				t.Errorf("calls = %v, want %v", calls, tt.wantCalls)
+			}
+			if tt.wantErr {
+				if llm.KindOf(err) != llm.KindNotAvailable {
+					t.Fatalf("resolveAuto() error = %v, want a not available error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveAuto() error = %v", err)
+			}
+			if name != tt.wantProvider || provider.Name() != tt.wantProvider {
+				t.Errorf("resolveAuto() = %q (%s), want %q", name, provider.Name(), tt.wantProvider)
+			}
+		})
+	}
+}
+
+func TestFactory_GetProviderAuto(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	t.Setenv(gemini.APIKeyEnvVar, "test-key")
+	viper.Set("auto.order", []string{"does-not-exist", "gemini"})
+
+	factory := NewFactory()
+	provider, err := factory.GetProvider(context.Background(), AutoProvider)
+	if err != nil {
+		t.Fatalf("GetProvider(auto) error = %v", err)
+	}
+	if provider.Name() != "gemini" {
+		t.Fatalf("GetProvider(auto) = %q, want gemini", provider.Name())
+	}
+
+	// The resolution is cached for the factory even when the order changes
+	viper.Set("auto.order", []string{"does-not-exist"})
+	provider, err = factory.GetProvider(context.Background(), AutoProvider)
+	if err != nil || provider.Name() != "gemini" {
+		t.Errorf("second GetProvider(auto) = %v, %v, want cached gemini", provider, err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"sync"
 
+	"github.com/connorhough/smix/internal/config"
 	"github.com/connorhough/smix/internal/llm"
 	"github.com/connorhough/smix/internal/llm/claude"
 	"github.com/connorhough/smix/internal/llm/gemini"
@@ -15,7 +16,10 @@ import (
 // Factory creates and caches provider instances
 type Factory struct {
 	cache map[string]llm.Provider
-	mu    sync.RWMutex
+	audit *AuditLog
+	// auto is the provider name AutoProvider resolved to, once resolved
+	auto string
+	mu   sync.RWMutex
 }
 
 // NewFactory creates a new provider factory
@@ -25,8 +29,12 @@ func NewFactory() *Factory {
 	}
 }
 
-// GetProvider returns a provider by name
+// GetProvider returns a provider by name. AutoProvider returns the first available provider.
 func (f *Factory) GetProvider(ctx context.Context, name string) (llm.Provider, error) {
+	if name == AutoProvider {
+		return f.getAuto(ctx)
+	}
+
 	f.mu.RLock()
 	if provider, ok := f.cache[name]; ok {
 		f.mu.RUnlock()
@@ -44,12 +52,29 @@ func (f *Factory) GetProvider(ctx context.Context, name string) (llm.Provider, e
 	var provider llm.Provider
 	var err error
 
+	httpCfg, err := httpConfig(name)
+	if err != nil {
+		return nil, err
+	}
+
 	switch name {
 	case claude.ProviderClaude:
-		provider, err = claude.NewProvider()
+		var p *claude.Provider
+		p, err = claude.NewProviderWithHTTP(apiKey(name, claude.APIKeyEnvVar), httpCfg)
+		if err == nil {
+			err = p.SetExtraArgs(config.ProviderArgs(claude.ProviderClaude))
+		}
+		provider = p
 	case gemini.ProviderGemini:
-		apiKey := os.Getenv(gemini.APIKeyEnvVar)
-		provider, err = gemini.NewProvider(ctx, apiKey)
+		var p *gemini.Provider
+		p, err = gemini.NewProviderWithHTTP(ctx, apiKey(name, gemini.APIKeyEnvVar), httpCfg)
+		if err == nil {
+			err = p.SetPreferredBackend(config.ProviderSetting(gemini.ProviderGemini, "prefer"))
+		}
+		if err == nil {
+			err = p.SetExtraArgs(config.ProviderArgs(gemini.ProviderGemini))
+		}
+		provider = p
 	default:
 		return nil, fmt.Errorf("unknown provider: %s", name)
 	}
@@ -58,14 +83,125 @@ func (f *Factory) GetProvider(ctx context.Context, name string) (llm.Provider, e
 		return nil, err
 	}
 
+	if f.audit != nil {
+		provider = WithAudit(provider, f.audit)
+	}
+
 	f.cache[name] = provider
 
 	return provider, nil
 }
 
+// httpConfig returns the endpoint settings for provider name from providers.<name>.base_url
+// and the global http.proxy
+func httpConfig(name string) (llm.HTTPConfig, error) {
+	client, err := llm.NewHTTPClient(config.HTTPProxy())
+	if err != nil {
+		return llm.HTTPConfig{}, fmt.Errorf("http.proxy: %w", err)
+	}
+
+	return llm.HTTPConfig{
+		BaseURL: config.ProviderSetting(name, "base_url"),
+		Client:  client,
+	}, nil
+}
+
+// apiKey returns the API key from envVar, falling back to providers.<name>.api_key in config
+// (which may itself reference an environment variable as ${VAR})
+func apiKey(name, envVar string) string {
+	if key := os.Getenv(envVar); key != "" {
+		return key
+	}
+	return config.ProviderSetting(name, "api_key")
+}
+
+// SetAuditLog records every Generate call of providers returned from now on in log (nil disables auditing)
+func (f *Factory) SetAuditLog(log *AuditLog) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.audit = log
+	f.cache = make(map[string]llm.Provider)
+}
+
+// Names returns the names of all registered providers
+func Names() []string {
+	return []string{claude.ProviderClaude, gemini.ProviderGemini}
+}
+
+// Info describes a registered provider and whether it can currently be used
+type Info struct {
+	Name         string   `json:"name"`
+	Available    bool     `json:"available"`
+	Error        string   `json:"error,omitempty"`
+	DefaultModel string   `json:"default_model"`
+	Models       []string `json:"models"`
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+// staticInfo returns the information known about a provider without constructing it
+func staticInfo(name string) Info {
+	info := Info{Name: name}
+	switch name {
+	case claude.ProviderClaude:
+		info.DefaultModel = claude.DefaultModel()
+		info.Models = claude.Models()
+	case gemini.ProviderGemini:
+		info.DefaultModel = gemini.DefaultModel()
+		info.Models = gemini.Models()
+	}
+	return info
+}
+
+// List describes every registered provider, attempting to construct each one to report availability
+func (f *Factory) List(ctx context.Context) []Info {
+	var infos []Info
+	for _, name := range Names() {
+		info := staticInfo(name)
+
+		provider, err := f.GetProvider(ctx, name)
+		if err != nil {
+			info.Error = err.Error()
+		} else {
+			info.Available = true
+			info.DefaultModel = provider.DefaultModel()
+			if lister, ok := provider.(llm.ModelLister); ok {
+				info.Models = lister.ListModels()
+			}
+			info.Capabilities = llm.CapabilitiesOf(provider).Names()
+		}
+
+		infos = append(infos, info)
+	}
+	return infos
+}
+
 // Global factory instance
 var globalFactory = NewFactory()
 
+// List is a convenience function that uses the global factory
+func List(ctx context.Context) []Info {
+	return globalFactory.List(ctx)
+}
+
+// CheckModel validates cfg.Model against the provider's known models when strict model checking is enabled
+func CheckModel(provider llm.Provider, cfg *config.ProviderConfig) error {
+	if !cfg.StrictModels {
+		return nil
+	}
+	return llm.CheckKnownModel(provider, cfg.Model)
+}
+
+// ConfigureAudit enables the audit log on the global factory when audit.file is configured.
+// command names the smix command that the recorded calls belong to.
+func ConfigureAudit(cfg config.AuditConfig, command string) {
+	if cfg.File == "" {
+		globalFactory.SetAuditLog(nil)
+		return
+	}
+	globalFactory.SetAuditLog(NewAuditLog(cfg.File, cfg.Full, command))
+}
+
 // GetProvider is a convenience function that uses the global factory
 func GetProvider(ctx context.Context, name string) (llm.Provider, error) {
 	return globalFactory.GetProvider(ctx, name)
@@ -6,6 +6,8 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/spf13/viper"
+
 	"github.com/connorhough/smix/internal/llm"
 	"github.com/connorhough/smix/internal/llm/gemini"
 )
@@ -127,3 +129,134 @@ func TestFactory_GetProvider(t *testing.T) {
 		// If we get here without panic, thread safety works
 	})
 }
+
+func TestFactory_List(t *testing.T) {
+	infos := NewFactory().List(context.Background())
+
+	byName := make(map[string]Info)
+	for _, info := range infos {
+		byName[info.Name] = info
+	}
+
+	wantModels := map[string][]string{
+		"claude": {"haiku", "sonnet", "opus"},
+		"gemini": {gemini.ModelFlash, gemini.ModelPro},
+	}
+
+	for name, models := range wantModels {
+		info, ok := byName[name]
+		if !ok {
+			t.Fatalf("expected %s in provider list", name)
+		}
+		if info.DefaultModel == "" {
+			t.Errorf("%s: expected a default model", name)
+		}
+		if !info.Available && info.Error == "" {
+			t.Errorf("%s: unavailable provider should report an error", name)
+		}
+		for _, model := range models {
+			found := false
+			for _, m := range info.Models {
+				if m == model {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("%s: expected model %q in %v", name, model, info.Models)
+			}
+		}
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       string
+		configKey string
+		want      string
+	}{
+		{name: "env set", env: "env-key", want: "env-key"},
+		{name: "config set", configKey: "config-key", want: "config-key"},
+		{name: "env takes precedence", env: "env-key", configKey: "config-key", want: "env-key"},
+		{name: "neither set", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			t.Setenv(gemini.APIKeyEnvVar, tt.env)
+			if tt.configKey != "" {
+				viper.Set("providers.gemini.api_key", tt.configKey)
+			}
+
+			if got := apiKey(gemini.ProviderGemini, gemini.APIKeyEnvVar); got != tt.want {
+				t.Errorf("apiKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFactory_GetProvider_APIKeyFromConfig(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	t.Setenv(gemini.APIKeyEnvVar, "")
+	t.Setenv("PATH", t.TempDir()) // no gemini CLI to fall back on
+	viper.Set("providers.gemini.api_key", "config-key")
+
+	provider, err := NewFactory().GetProvider(context.Background(), gemini.ProviderGemini)
+	if err != nil {
+		t.Fatalf("GetProvider() with providers.gemini.api_key error = %v", err)
+	}
+	if provider.Name() != gemini.ProviderGemini {
+		t.Errorf("provider = %q, want %q", provider.Name(), gemini.ProviderGemini)
+	}
+}
+
+func TestHTTPConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		settings   map[string]any
+		wantBase   string
+		wantClient bool
+		wantErr    bool
+	}{
+		{name: "unset", settings: nil},
+		{
+			name:     "provider base URL",
+			settings: map[string]any{"providers.gemini.base_url": "https://gemini.internal.example"},
+			wantBase: "https://gemini.internal.example",
+		},
+		{
+			name:       "global proxy",
+			settings:   map[string]any{"http.proxy": "http://proxy.example:3128"},
+			wantClient: true,
+		},
+		{name: "invalid proxy", settings: map[string]any{"http.proxy": "not a url"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			for k, v := range tt.settings {
+				viper.Set(k, v)
+			}
+
+			got, err := httpConfig(gemini.ProviderGemini)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("httpConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.BaseURL != tt.wantBase {
+				t.Errorf("BaseURL = %q, want %q", got.BaseURL, tt.wantBase)
+			}
+			if (got.Client != nil) != tt.wantClient {
+				t.Errorf("Client = %v, want client %v", got.Client, tt.wantClient)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
+)
+
+// getterFunc returns a provider by name (Factory.GetProvider in production)
+type getterFunc func(ctx context.Context, name string) (llm.Provider, error)
+
+// GetProviderWithFallback returns the provider named by cfg.Provider. When it is not available or
+// fails to authenticate and cfg.Fallback names another provider, the fallback is used instead:
+// a one-line notice is written to notice and cfg is switched to the fallback provider with its
+// default model, since the configured model belongs to the original provider.
+func (f *Factory) GetProviderWithFallback(ctx context.Context, cfg *config.ProviderConfig, notice io.Writer) (llm.Provider, error) {
+	return getWithFallback(ctx, f.GetProvider, cfg, notice)
+}
+
+// GetProviderWithFallback is a convenience function that uses the global factory
+func GetProviderWithFallback(ctx context.Context, cfg *config.ProviderConfig, notice io.Writer) (llm.Provider, error) {
+	return globalFactory.GetProviderWithFallback(ctx, cfg, notice)
+}
+
+func getWithFallback(ctx context.Context, get getterFunc, cfg *config.ProviderConfig, notice io.Writer) (llm.Provider, error) {
+	provider, err := get(ctx, cfg.Provider)
+	if err == nil || !shouldFallback(err) || cfg.Fallback == "" || cfg.Fallback == cfg.Provider {
+		return provider, err
+	}
+
+	fallback, fallbackErr := get(ctx, cfg.Fallback)
+	if fallbackErr != nil {
+		return nil, fmt.Errorf("%w (fallback provider %s: %v)", err, cfg.Fallback, fallbackErr)
+	}
+
+	reason := "is not available"
+	if llm.KindOf(err) == llm.KindAuthentication {
+		reason = "failed to authenticate"
+	}
+	fmt.Fprintf(notice, "%s %s, falling back to %s\n", cfg.Provider, reason, cfg.Fallback)
+	cfg.Provider = cfg.Fallback
+	cfg.Model = ""
+	return fallback, nil
+}
+
+// shouldFallback reports whether err means the provider cannot be used at all,
+// as opposed to a request-level failure such as an unknown model or a rate limit
+func shouldFallback(err error) bool {
+	switch llm.KindOf(err) {
+	case llm.KindNotAvailable, llm.KindAuthentication:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/connorhough/smix/internal/config"
+	"github.com/connorhough/smix/internal/llm"
+	"github.com/connorhough/smix/internal/llm/llmtest"
+)
+
+// stubGetter returns a getterFunc that fails with errs[name] and otherwise returns a fake provider named name
+func stubGetter(errs map[string]error, calls *[]string) getterFunc {
+	return func(_ context.Context, name string) (llm.Provider, error) {
+		*calls = append(*calls, name)
+		if err := errs[name]; err != nil {
+			return nil, err
+		}
+		return &llmtest.FakeProvider{ProviderName: name}, nil
+	}
+}
+
+func TestGetWithFallback(t *testing.T) {
+	tests := []struct {
+		name         string
+		errs         map[string]error
+		fallback     string
+		wantProvider string
+		wantErr      bool
+		wantCalls    []string
+		wantNotice   string
+	}{
+		{
+			name:         "primary available",
+			fallback:     "claude",
+			wantProvider: "gemini",
+			wantCalls:    []string{"gemini"},
+		},
+		{
+			name:         "primary not available",
+			errs:         map[string]error{"gemini": llm.ErrProviderNotAvailable("gemini", errors.New("no key"))},
+			fallback:     "claude",
+			wantProvider: "claude",
+			wantCalls:    []string{"gemini", "claude"},
+			wantNotice:   "gemini is not available, falling back to claude\n",
+		},
+		{
+			name:         "primary authentication failed",
+			errs:         map[string]error{"gemini": fmt.Errorf("wrapped: %w", llm.ErrAuthenticationFailed("gemini", errors.New("bad key")))},
+			fallback:     "claude",
+			wantProvider: "claude",
+			wantCalls:    []string{"gemini", "claude"},
+			wantNotice:   "gemini failed to authenticate, falling back to claude\n",
+		},
+		{
+			name:      "no fallback configured",
+			errs:      map[string]error{"gemini": llm.ErrProviderNotAvailable("gemini", errors.New("no key"))},
+			wantErr:   true,
+			wantCalls: []string{"gemini"},
+		},
+		{
+			name:      "fallback is the primary",
+			errs:      map[string]error{"gemini": llm.ErrProviderNotAvailable("gemini", errors.New("no key"))},
+			fallback:  "gemini",
+			wantErr:   true,
+			wantCalls: []string{"gemini"},
+		},
+		{
+			name:      "model not found does not fall back",
+			errs:      map[string]error{"gemini": llm.ErrModelNotFound("nope", "gemini", errors.New("unknown"))},
+			fallback:  "claude",
+			wantErr:   true,
+			wantCalls: []string{"gemini"},
+		},
+		{
+			name:      "rate limit does not fall back",
+			errs:      map[string]error{"gemini": llm.ErrRateLimitExceeded("gemini", errors.New("slow down"))},
+			fallback:  "claude",
+			wantErr:   true,
+			wantCalls: []string{"gemini"},
+		},
+		{
+			name: "fallback also unavailable",
+			errs: map[string]error{
+				"gemini": llm.ErrProviderNotAvailable("gemini", errors.New("no key")),
+				"claude": llm.ErrProviderNotAvailable("claude", errors.New("no cli")),
+			},
+			fallback:  "claude",
+			wantErr:   true,
+			wantCalls: []string{"gemini", "claude"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			var notice bytes.Buffer
+			cfg := &config.ProviderConfig{Provider: "gemini", Model: "gemini-3-pro-preview", Fallback: tt.fallback}
+
+			provider, err := getWithFallback(context.Background(), stubGetter(tt.errs, &calls), cfg, &notice)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getWithFallback() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if strings.Join(calls, ",") != strings.Join(tt.wantCalls, ",") {
+				t.Errorf("calls = %v, want %v", calls, tt.wantCalls)
+			}
+			if notice.String() != tt.wantNotice {
+				t.Errorf("notice = %q, want %q", notice.String(), tt.wantNotice)
+			}
+			if tt.wantErr {
+				if cfg.Provider != "gemini" || cfg.Model != "gemini-3-pro-preview" {
+					t.Errorf("cfg changed on error: %+v", cfg)
+				}
+				return
+			}
+			if provider.Name() != tt.wantProvider {
+				t.Errorf("provider = %q, want %q", provider.Name(), tt.wantProvider)
+			}
+			if tt.wantProvider != "gemini" && (cfg.Provider != tt.wantProvider || cfg.Model != "") {
+				t.Errorf("cfg = %+v, want provider %q with default model", cfg, tt.wantProvider)
+			}
+		})
+	}
+}
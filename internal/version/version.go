@@ -1,7 +1,10 @@
 // Package version provides version information for the smix application.
 package version
 
-import "fmt"
+import (
+	"fmt"
+	"runtime"
+)
 
 // These variables are set at build time using ldflags
 var (
@@ -10,7 +13,34 @@ var (
 	BuildDate = "unknown"
 )
 
+// BuildInfo describes the running binary
+type BuildInfo struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+}
+
+// Info returns the build metadata, using "dev" and "unknown" for values not injected at build time
+func Info() BuildInfo {
+	return BuildInfo{
+		Version:   valueOr(Version, "dev"),
+		GitCommit: valueOr(GitCommit, "unknown"),
+		BuildDate: valueOr(BuildDate, "unknown"),
+		GoVersion: runtime.Version(),
+	}
+}
+
 // String returns a formatted version string including version, git commit, and build date
 func String() string {
-	return fmt.Sprintf("%s (commit: %s, date: %s)", Version, GitCommit, BuildDate)
+	info := Info()
+	return fmt.Sprintf("%s (commit: %s, date: %s)", info.Version, info.GitCommit, info.BuildDate)
+}
+
+// valueOr returns value, or fallback when value is empty (e.g. set to "" via ldflags)
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
 }
@@ -0,0 +1,43 @@
+package version
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestInfo(t *testing.T) {
+	tests := []struct {
+		name                    string
+		version, commit, date   string
+		wantVersion, wantCommit string
+		wantDate                string
+	}{
+		{name: "defaults", version: "dev", commit: "unknown", date: "unknown", wantVersion: "dev", wantCommit: "unknown", wantDate: "unknown"},
+		{name: "empty values fall back", wantVersion: "dev", wantCommit: "unknown", wantDate: "unknown"},
+		{name: "injected", version: "v1.2.3", commit: "abc1234", date: "2026-01-02T03:04:05Z", wantVersion: "v1.2.3", wantCommit: "abc1234", wantDate: "2026-01-02T03:04:05Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origVersion, origCommit, origDate := Version, GitCommit, BuildDate
+			t.Cleanup(func() { Version, GitCommit, BuildDate = origVersion, origCommit, origDate })
+			Version, GitCommit, BuildDate = tt.version, tt.commit, tt.date
+
+			info := Info()
+			want := BuildInfo{Version: tt.wantVersion, GitCommit: tt.wantCommit, BuildDate: tt.wantDate, GoVersion: runtime.Version()}
+			if info != want {
+				t.Errorf("Info() = %+v, want %+v", info, want)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	origVersion, origCommit, origDate := Version, GitCommit, BuildDate
+	t.Cleanup(func() { Version, GitCommit, BuildDate = origVersion, origCommit, origDate })
+	Version, GitCommit, BuildDate = "v1.0.0", "abc1234", "2026-01-02"
+
+	if got, want := String(), "v1.0.0 (commit: abc1234, date: 2026-01-02)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
@@ -15,7 +14,7 @@ func main() {
 	defer stop()
 
 	if err := cmd.Execute(ctx); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		cmd.ReportError(os.Stderr, err)
 		os.Exit(1)
 	}
 }